@@ -2,11 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 	filmoteke "vk_restAPI"
 	logger "vk_restAPI/logs"
 	"vk_restAPI/package/handler"
@@ -44,6 +47,18 @@ type Config struct {
 		DBname   string `yaml:"dbname"`
 		SSLmode  string `yaml:"sslmode"`
 	}
+	Auth struct {
+		TokenTTL   time.Duration `yaml:"token_ttl"`
+		RefreshTTL time.Duration `yaml:"refresh_ttl"`
+		ActiveKey  string        `yaml:"active_key"`
+		Admin      string        `yaml:"admin"`
+		Keys       []struct {
+			Id        string `yaml:"id"`
+			Algorithm string `yaml:"alg"`
+			File      string `yaml:"file"`
+			Env       string `yaml:"env"`
+		} `yaml:"keys"`
+	}
 }
 
 func initConfig() (*Config, error) {
@@ -67,13 +82,77 @@ func initConfig() (*Config, error) {
 	return &config, nil
 }
 
+// initKeyRing loads the JWT signing keys. Key material is read from the
+// environment variable named by env, or from file when env is not set.
+// JWT_TOKEN_TTL and JWT_ACTIVE_KEY override the config file.
+func initKeyRing(config *Config) (*service.KeyRing, error) {
+	tokens := service.TokenConfig{
+		TTL:         config.Auth.TokenTTL,
+		RefreshTTL:  config.Auth.RefreshTTL,
+		ActiveKeyId: config.Auth.ActiveKey,
+	}
+
+	if ttl := os.Getenv("JWT_TOKEN_TTL"); ttl != "" {
+		d, err := time.ParseDuration(ttl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWT_TOKEN_TTL: %v", err)
+		}
+		tokens.TTL = d
+	}
+
+	if active := os.Getenv("JWT_ACTIVE_KEY"); active != "" {
+		tokens.ActiveKeyId = active
+	}
+
+	for _, k := range config.Auth.Keys {
+		var material []byte
+
+		if k.Env != "" {
+			value, ok := os.LookupEnv(k.Env)
+			if !ok {
+				return nil, fmt.Errorf("signing key %s: environment variable %s is not set", k.Id, k.Env)
+			}
+			material = []byte(value)
+		} else {
+			data, err := os.ReadFile(k.File)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read signing key %s: %v", k.Id, err)
+			}
+			material = data
+		}
+
+		key, err := service.NewSigningKey(k.Id, k.Algorithm, material)
+		if err != nil {
+			return nil, err
+		}
+		tokens.Keys = append(tokens.Keys, key)
+	}
+
+	return service.NewKeyRing(tokens)
+}
+
+// bootstrapAdmin creates the admin account, or promotes an existing one.
+// The password of a new account is read from ADMIN_PASSWORD.
+func bootstrapAdmin(services *service.Service, username string) error {
+	id, err := services.Authorization.BootstrapAdmin(username, os.Getenv("ADMIN_PASSWORD"))
+	if err != nil {
+		return err
+	}
+
+	logger.Log.Infof("User %s (id %d) is an admin", username, id)
+	return nil
+}
+
 func main() {
 
+	adminFlag := flag.String("bootstrap-admin", "", "create or promote the given user to admin and exit")
+	flag.Parse()
+
 	//Setting JSON format for our logs
 	logrus.SetFormatter(new(logrus.JSONFormatter))
 
-	//Loading .env
-	if err := godotenv.Load(); err != nil {
+	//Loading .env, which is optional when the environment is set otherwise
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
 		logrus.Fatalf("error loading env variables: %s", err.Error())
 	}
 
@@ -95,11 +174,30 @@ func main() {
 		logrus.Fatalf("failed to initialize db: %s", err.Error())
 	}
 
+	keys, err := initKeyRing(config)
+	if err != nil {
+		logrus.Fatalf("failed to initialize signing keys: %s", err.Error())
+	}
+
 	//Creating our dependencies
 	repositories := repository.NewRepository(db)
-	services := service.NewService(repositories)
+	services := service.NewService(repositories, keys)
 	handlers := handler.NewHandler(services)
 
+	if *adminFlag != "" {
+		if err := bootstrapAdmin(services, *adminFlag); err != nil {
+			logrus.Fatalf("failed to bootstrap admin: %s", err.Error())
+		}
+		db.Close()
+		return
+	}
+
+	if config.Auth.Admin != "" {
+		if err := bootstrapAdmin(services, config.Auth.Admin); err != nil {
+			logrus.Fatalf("failed to bootstrap admin: %s", err.Error())
+		}
+	}
+
 	//Running server
 	srv := new(filmoteke.Server)
 	go func() {
@@ -0,0 +1,87 @@
+package filmoteka
+
+import "time"
+
+const (
+	CollectionWatchlist = "watchlist"
+	CollectionFavorites = "favorites"
+	CollectionCustom    = "custom"
+)
+
+// Collection is a user's ordered list of movies. A user has at most one
+// watchlist and one favorites collection, and any number of custom ones.
+// Slug is blank until the collection is shared; anyone may then open it by
+// the slug.
+type Collection struct {
+	Id        int       `json:"id" db:"id"`
+	UserId    int       `json:"user_id" db:"user_id"`
+	Username  string    `json:"username" db:"username"`
+	Name      string    `json:"name" db:"name"`
+	Kind      string    `json:"kind" db:"kind"`
+	Slug      string    `json:"slug,omitempty" db:"slug"`
+	Movies    int       `json:"movies" db:"movies"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// CollectionMovie is a movie of a collection, listed in the collection's
+// order.
+type CollectionMovie struct {
+	MoviesWithActors
+	AddedAt time.Time `json:"added_at" db:"added_at"`
+}
+
+// CollectionInput creates a collection. A blank Kind is custom. Public
+// shares the collection right away.
+type CollectionInput struct {
+	Name   string `json:"name" validate:"required,max=100"`
+	Kind   string `json:"kind" validate:"oneof=watchlist favorites custom"`
+	Public bool   `json:"public"`
+}
+
+// UpdateCollection is a partial update of a collection, see UpdateMovies.
+// Sharing a shared collection again keeps its slug, so links handed out
+// earlier keep working until it is made private.
+type UpdateCollection struct {
+	Name   *string `json:"name" validate:"required,max=100"`
+	Public *bool   `json:"public"`
+}
+
+// CollectionItem adds a movie to the end of a collection.
+type CollectionItem struct {
+	MovieId int `json:"movie_id" validate:"min=1"`
+}
+
+// CollectionOrder is the new order of a collection. It lists every movie of
+// the collection exactly once.
+type CollectionOrder struct {
+	MovieIds []int `json:"movie_ids"`
+}
+
+type CollectionMoviesParams struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+type CollectionMoviesList struct {
+	Movies []CollectionMovie
+	Total  int
+}
+
+func (c CollectionInput) Validate() error {
+	return Validate(c)
+}
+
+func (u UpdateCollection) Validate() error {
+	if u.Name == nil && u.Public == nil {
+		return Errorf(ErrValidation, "update structure has no values")
+	}
+	return Validate(u)
+}
+
+func (i CollectionItem) Validate() error {
+	return Validate(i)
+}
+
+func (p CollectionMoviesParams) Validate() error {
+	return validatePage(p.Limit, p.Offset)
+}
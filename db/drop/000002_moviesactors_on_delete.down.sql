@@ -0,0 +1,9 @@
+ALTER TABLE MoviesActors
+    DROP CONSTRAINT moviesactors_actor_id_fkey,
+    DROP CONSTRAINT moviesactors_movie_id_fkey;
+
+ALTER TABLE MoviesActors
+    ADD CONSTRAINT moviesactors_actor_id_fkey
+        FOREIGN KEY (actor_id) REFERENCES Actors(id),
+    ADD CONSTRAINT moviesactors_movie_id_fkey
+        FOREIGN KEY (movie_id) REFERENCES Movies(id);
@@ -0,0 +1,5 @@
+ALTER TABLE Users ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE Users SET is_admin = (role = 'admin');
+
+ALTER TABLE Users DROP COLUMN role;
@@ -0,0 +1,10 @@
+ALTER TABLE Actors
+    DROP CONSTRAINT actors_gender_check,
+    ALTER COLUMN first_name TYPE VARCHAR,
+    ALTER COLUMN last_name TYPE VARCHAR;
+
+UPDATE Actors SET gender = CASE gender
+    WHEN 'male' THEN 'Мужчина'
+    WHEN 'female' THEN 'Женщина'
+    ELSE gender
+END;
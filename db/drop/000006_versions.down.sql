@@ -0,0 +1,2 @@
+ALTER TABLE Actors DROP COLUMN version;
+ALTER TABLE Movies DROP COLUMN version;
@@ -0,0 +1,3 @@
+DROP INDEX movies_search_idx;
+
+ALTER TABLE Movies DROP COLUMN search;
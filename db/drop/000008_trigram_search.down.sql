@@ -0,0 +1,4 @@
+DROP INDEX movies_title_trgm_idx;
+DROP INDEX actors_name_trgm_idx;
+
+DROP EXTENSION IF EXISTS pg_trgm;
@@ -0,0 +1,8 @@
+ALTER TABLE Movies
+    DROP COLUMN age_rating,
+    DROP COLUMN runtime,
+    DROP COLUMN country,
+    DROP COLUMN original_title;
+
+DROP TABLE MoviesGenres;
+DROP TABLE Genres;
@@ -0,0 +1,4 @@
+ALTER TABLE MoviesActors
+    DROP COLUMN role_type,
+    DROP COLUMN billing_order,
+    DROP COLUMN character_name;
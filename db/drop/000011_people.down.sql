@@ -0,0 +1,8 @@
+DROP VIEW Actors;
+DROP TABLE MoviesCrew;
+
+DELETE FROM People p
+WHERE p.known_for <> 'acting' AND NOT EXISTS (SELECT 1 FROM MoviesActors ma WHERE ma.actor_id = p.id);
+
+ALTER TABLE People DROP COLUMN known_for;
+ALTER TABLE People RENAME TO Actors;
@@ -0,0 +1,2 @@
+DROP TABLE CollectionItems;
+DROP TABLE Collections;
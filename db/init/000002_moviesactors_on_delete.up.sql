@@ -0,0 +1,11 @@
+-- Deleting a movie drops its cast links, deleting an actor who still
+-- appears in a movie is refused so the API can report the blocking movies.
+ALTER TABLE MoviesActors
+    DROP CONSTRAINT moviesactors_actor_id_fkey,
+    DROP CONSTRAINT moviesactors_movie_id_fkey;
+
+ALTER TABLE MoviesActors
+    ADD CONSTRAINT moviesactors_actor_id_fkey
+        FOREIGN KEY (actor_id) REFERENCES Actors(id) ON DELETE RESTRICT,
+    ADD CONSTRAINT moviesactors_movie_id_fkey
+        FOREIGN KEY (movie_id) REFERENCES Movies(id) ON DELETE CASCADE;
@@ -0,0 +1,14 @@
+CREATE TABLE RefreshTokens
+(
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES Users(id) ON DELETE CASCADE,
+    family_id VARCHAR(64) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMPTZ,
+    replaced_by INTEGER REFERENCES RefreshTokens(id) ON DELETE SET NULL
+);
+
+CREATE INDEX refreshtokens_family_id_idx ON RefreshTokens (family_id);
+CREATE INDEX refreshtokens_user_id_idx ON RefreshTokens (user_id);
@@ -0,0 +1,7 @@
+ALTER TABLE Users
+    ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'viewer'
+        CHECK (role IN ('viewer', 'editor', 'admin'));
+
+UPDATE Users SET role = 'admin' WHERE is_admin;
+
+ALTER TABLE Users DROP COLUMN is_admin;
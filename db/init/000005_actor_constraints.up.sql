@@ -0,0 +1,10 @@
+UPDATE Actors SET gender = CASE gender
+    WHEN 'Мужчина' THEN 'male'
+    WHEN 'Женщина' THEN 'female'
+    ELSE 'other'
+END;
+
+ALTER TABLE Actors
+    ADD CONSTRAINT actors_gender_check CHECK (gender IN ('male', 'female', 'other')),
+    ALTER COLUMN first_name TYPE VARCHAR(100),
+    ALTER COLUMN last_name TYPE VARCHAR(100);
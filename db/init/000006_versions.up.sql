@@ -0,0 +1,4 @@
+-- Every update bumps the version, which is served as the ETag so writes
+-- can be made conditional on it with If-Match.
+ALTER TABLE Movies ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE Actors ADD COLUMN version INT NOT NULL DEFAULT 1;
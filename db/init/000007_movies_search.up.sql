@@ -0,0 +1,8 @@
+-- Full-text search over titles and descriptions. Title words weigh more
+-- than description words in ts_rank.
+ALTER TABLE Movies ADD COLUMN search tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('russian', coalesce(description, '')), 'B')
+) STORED;
+
+CREATE INDEX movies_search_idx ON Movies USING GIN (search);
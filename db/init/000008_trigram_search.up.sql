@@ -0,0 +1,6 @@
+-- Typo tolerant search over actor full names and movie titles. GiST rather
+-- than GIN so autocomplete can read the nearest names straight off the index.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX actors_name_trgm_idx ON Actors USING GIST ((first_name || ' ' || last_name) gist_trgm_ops);
+CREATE INDEX movies_title_trgm_idx ON Movies USING GIST (title gist_trgm_ops);
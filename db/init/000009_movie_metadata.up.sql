@@ -0,0 +1,68 @@
+-- Movie metadata beyond the title: genres, which a movie may have several
+-- of, and the country, runtime in minutes, age rating and original title.
+-- Blank strings and a zero runtime mean the value is unknown.
+CREATE TABLE Genres
+(
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE
+);
+
+CREATE TABLE MoviesGenres
+(
+    movie_id INTEGER REFERENCES Movies(id) ON DELETE CASCADE,
+    genre_id INTEGER REFERENCES Genres(id) ON DELETE CASCADE,
+    PRIMARY KEY (movie_id, genre_id)
+);
+
+CREATE INDEX moviesgenres_genre_id_idx ON MoviesGenres (genre_id);
+
+ALTER TABLE Movies
+    ADD COLUMN original_title VARCHAR(150) NOT NULL DEFAULT '',
+    ADD COLUMN country VARCHAR(2) NOT NULL DEFAULT ''
+        CHECK (country = '' OR country ~ '^[A-Z]{2}$'),
+    ADD COLUMN runtime INT NOT NULL DEFAULT 0 CHECK (runtime >= 0),
+    ADD COLUMN age_rating VARCHAR(3) NOT NULL DEFAULT ''
+        CHECK (age_rating IN ('', '0+', '6+', '12+', '16+', '18+'));
+
+INSERT INTO Genres (name) VALUES
+    ('биография'),
+    ('боевик'),
+    ('драма'),
+    ('криминал'),
+    ('мелодрама'),
+    ('приключения'),
+    ('триллер'),
+    ('фантастика');
+
+INSERT INTO MoviesGenres (movie_id, genre_id)
+SELECT m.id, g.id FROM Movies m JOIN Genres g ON (m.title, g.name) IN (
+    ('Дюна: Часть вторая', 'фантастика'),
+    ('Дюна: Часть вторая', 'боевик'),
+    ('Дюна: Часть вторая', 'драма'),
+    ('Дюна: Часть вторая', 'приключения'),
+    ('В погоне за счастьем', 'драма'),
+    ('В погоне за счастьем', 'биография'),
+    ('Великий Гэтсби', 'драма'),
+    ('Великий Гэтсби', 'мелодрама'),
+    ('Темный рыцарь', 'фантастика'),
+    ('Темный рыцарь', 'боевик'),
+    ('Темный рыцарь', 'триллер'),
+    ('Темный рыцарь', 'криминал'),
+    ('Темный рыцарь', 'драма'),
+    ('Социальная сеть', 'драма'),
+    ('Социальная сеть', 'биография')
+);
+
+UPDATE Movies m SET
+    original_title = v.original_title,
+    country = v.country,
+    runtime = v.runtime,
+    age_rating = v.age_rating
+FROM (VALUES
+    ('Дюна: Часть вторая', 'Dune: Part Two', 'US', 166, '12+'),
+    ('В погоне за счастьем', 'The Pursuit of Happyness', 'US', 117, '12+'),
+    ('Великий Гэтсби', 'The Great Gatsby', 'AU', 143, '16+'),
+    ('Темный рыцарь', 'The Dark Knight', 'US', 152, '16+'),
+    ('Социальная сеть', 'The Social Network', 'US', 120, '12+')
+) AS v (title, original_title, country, runtime, age_rating)
+WHERE m.title = v.title;
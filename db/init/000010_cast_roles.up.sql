@@ -0,0 +1,40 @@
+-- Who an actor plays in a movie, where they are billed and what kind of
+-- role it is. Billing starts at 1; existing casts are billed by actor id.
+ALTER TABLE MoviesActors
+    ADD COLUMN character_name VARCHAR(150) NOT NULL DEFAULT '',
+    ADD COLUMN billing_order INT NOT NULL DEFAULT 0,
+    ADD COLUMN role_type VARCHAR(16) NOT NULL DEFAULT 'supporting'
+        CHECK (role_type IN ('lead', 'supporting', 'cameo', 'voice'));
+
+UPDATE MoviesActors ma SET billing_order = o.n
+FROM (
+    SELECT movie_id, actor_id, row_number() OVER (PARTITION BY movie_id ORDER BY actor_id) AS n
+    FROM MoviesActors
+) o
+WHERE ma.movie_id = o.movie_id AND ma.actor_id = o.actor_id;
+
+ALTER TABLE MoviesActors
+    ALTER COLUMN billing_order DROP DEFAULT,
+    ADD CONSTRAINT moviesactors_billing_order_check CHECK (billing_order > 0);
+
+UPDATE MoviesActors ma SET
+    character_name = v.character_name,
+    role_type = v.role_type
+FROM (VALUES
+    ('Дюна: Часть вторая', 'Тимоти', 'Шаламе', 'Пол Атрейдес', 'lead'),
+    ('Дюна: Часть вторая', 'Зендея', '', 'Чани', 'lead'),
+    ('Дюна: Часть вторая', 'Ребекка', 'Фергюсон', 'леди Джессика', 'supporting'),
+    ('Дюна: Часть вторая', 'Хавьер', 'Бардем', 'Стилгар', 'supporting'),
+    ('В погоне за счастьем', 'Уилл', 'Смит', 'Крис Гарднер', 'lead'),
+    ('В погоне за счастьем', 'Джейден', 'Смит', 'Кристофер', 'lead'),
+    ('Великий Гэтсби', 'Леонардо', 'ДиКаприо', 'Джей Гэтсби', 'lead'),
+    ('Великий Гэтсби', 'Тоби', 'Магуайр', 'Ник Каррауэй', 'lead'),
+    ('Великий Гэтсби', 'Кэри', 'Маллиган', 'Дэйзи Бьюкенен', 'lead'),
+    ('Великий Гэтсби', 'Джоэл', 'Эдгертон', 'Том Бьюкенен', 'supporting'),
+    ('Темный рыцарь', 'Кристиан', 'Бэйл', 'Брюс Уэйн / Бэтмен', 'lead'),
+    ('Темный рыцарь', 'Хит', 'Леджер', 'Джокер', 'lead'),
+    ('Социальная сеть', 'Джесси', 'Айзенберг', 'Марк Цукерберг', 'lead')
+) AS v (title, first_name, last_name, character_name, role_type)
+JOIN Movies m ON m.title = v.title
+JOIN Actors a ON a.first_name = v.first_name AND a.last_name = v.last_name
+WHERE ma.movie_id = m.id AND ma.actor_id = a.id;
@@ -0,0 +1,55 @@
+-- Actors are one kind of people. Crew credits link people to movies by
+-- department and job, acting credits stay in MoviesActors. Actors is now a
+-- view over the people known for acting or with an acting credit; it is
+-- simple enough for Postgres to update through, so writes to it still work.
+ALTER TABLE Actors RENAME TO People;
+
+ALTER TABLE People
+    ADD COLUMN known_for VARCHAR(16) NOT NULL DEFAULT 'acting'
+        CHECK (known_for IN ('acting', 'directing', 'writing', 'production', 'sound', 'camera', 'editing'));
+
+CREATE TABLE MoviesCrew
+(
+    movie_id INTEGER NOT NULL REFERENCES Movies(id) ON DELETE CASCADE,
+    person_id INTEGER NOT NULL REFERENCES People(id) ON DELETE RESTRICT,
+    department VARCHAR(16) NOT NULL
+        CHECK (department IN ('directing', 'writing', 'production', 'sound', 'camera', 'editing')),
+    job VARCHAR(100) NOT NULL,
+    PRIMARY KEY (movie_id, person_id, department, job)
+);
+
+CREATE INDEX moviescrew_person_id_idx ON MoviesCrew (person_id);
+
+CREATE VIEW Actors AS
+SELECT p.* FROM People p
+WHERE p.known_for = 'acting' OR EXISTS (SELECT 1 FROM MoviesActors ma WHERE ma.actor_id = p.id);
+
+INSERT INTO People (first_name, last_name, gender, date_of_birth, known_for) VALUES
+    ('Дени', 'Вильнёв', 'male', '1967-10-03', 'directing'),
+    ('Ханс', 'Циммер', 'male', '1957-09-12', 'sound'),
+    ('Габриэле', 'Муччино', 'male', '1967-05-20', 'directing'),
+    ('Баз', 'Лурман', 'male', '1962-09-17', 'directing'),
+    ('Кристофер', 'Нолан', 'male', '1970-07-30', 'directing'),
+    ('Дэвид', 'Финчер', 'male', '1962-08-28', 'directing'),
+    ('Аарон', 'Соркин', 'male', '1961-06-09', 'writing'),
+    ('Трент', 'Резнор', 'male', '1965-05-17', 'sound');
+
+INSERT INTO MoviesCrew (movie_id, person_id, department, job)
+SELECT m.id, p.id, v.department, v.job
+FROM (VALUES
+    ('Дюна: Часть вторая', 'Дени', 'Вильнёв', 'directing', 'Режиссёр'),
+    ('Дюна: Часть вторая', 'Дени', 'Вильнёв', 'writing', 'Сценарист'),
+    ('Дюна: Часть вторая', 'Ханс', 'Циммер', 'sound', 'Композитор'),
+    ('В погоне за счастьем', 'Габриэле', 'Муччино', 'directing', 'Режиссёр'),
+    ('Великий Гэтсби', 'Баз', 'Лурман', 'directing', 'Режиссёр'),
+    ('Великий Гэтсби', 'Баз', 'Лурман', 'writing', 'Сценарист'),
+    ('Великий Гэтсби', 'Баз', 'Лурман', 'production', 'Продюсер'),
+    ('Темный рыцарь', 'Кристофер', 'Нолан', 'directing', 'Режиссёр'),
+    ('Темный рыцарь', 'Кристофер', 'Нолан', 'writing', 'Сценарист'),
+    ('Темный рыцарь', 'Ханс', 'Циммер', 'sound', 'Композитор'),
+    ('Социальная сеть', 'Дэвид', 'Финчер', 'directing', 'Режиссёр'),
+    ('Социальная сеть', 'Аарон', 'Соркин', 'writing', 'Сценарист'),
+    ('Социальная сеть', 'Трент', 'Резнор', 'sound', 'Композитор')
+) AS v (title, first_name, last_name, department, job)
+JOIN Movies m ON m.title = v.title
+JOIN People p ON p.first_name = v.first_name AND p.last_name = v.last_name;
@@ -0,0 +1,18 @@
+-- User ratings and reviews, one per user and movie. The rating is required,
+-- the text is not. Moderators hide a review rather than delete it; hidden
+-- reviews are left out of listings and of the movie's user rating.
+CREATE TABLE Reviews
+(
+    id SERIAL PRIMARY KEY,
+    movie_id INTEGER NOT NULL REFERENCES Movies(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES Users(id) ON DELETE CASCADE,
+    rating INT NOT NULL CHECK (rating >= 1 AND rating <= 10),
+    body VARCHAR(5000) NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL DEFAULT 'published'
+        CHECK (status IN ('published', 'hidden')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (movie_id, user_id)
+);
+
+CREATE INDEX reviews_user_id_idx ON Reviews (user_id);
@@ -0,0 +1,30 @@
+-- Personal movie lists. Every user has at most one watchlist and one
+-- favorites list next to any number of named custom ones. A collection is
+-- private until it is shared: then it gets a slug anyone can open it by.
+CREATE TABLE Collections
+(
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES Users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    kind VARCHAR(16) NOT NULL DEFAULT 'custom'
+        CHECK (kind IN ('watchlist', 'favorites', 'custom')),
+    slug VARCHAR(32) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT collections_user_name_key UNIQUE (user_id, name)
+);
+
+CREATE UNIQUE INDEX collections_user_kind_idx ON Collections (user_id, kind) WHERE kind <> 'custom';
+CREATE UNIQUE INDEX collections_slug_idx ON Collections (slug) WHERE slug <> '';
+
+-- Movies of a collection, ordered by position. Positions only need to be
+-- increasing, removing a movie leaves a gap.
+CREATE TABLE CollectionItems
+(
+    collection_id INTEGER NOT NULL REFERENCES Collections(id) ON DELETE CASCADE,
+    movie_id INTEGER NOT NULL REFERENCES Movies(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    added_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (collection_id, movie_id)
+);
+
+CREATE INDEX collectionitems_movie_id_idx ON CollectionItems (movie_id);
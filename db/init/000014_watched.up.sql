@@ -0,0 +1,14 @@
+-- Movies a user has watched, one row per user and movie: watching a movie
+-- again moves its date. The personal rating is private to the user and 0
+-- when not given; it is unrelated to the public ratings of Reviews.
+CREATE TABLE Watched
+(
+    user_id INTEGER NOT NULL REFERENCES Users(id) ON DELETE CASCADE,
+    movie_id INTEGER NOT NULL REFERENCES Movies(id) ON DELETE CASCADE,
+    watched_on DATE NOT NULL DEFAULT CURRENT_DATE,
+    rating INT NOT NULL DEFAULT 0 CHECK (rating >= 0 AND rating <= 10),
+    PRIMARY KEY (user_id, movie_id)
+);
+
+CREATE INDEX watched_user_watched_on_idx ON Watched (user_id, watched_on DESC);
+CREATE INDEX watched_movie_id_idx ON Watched (movie_id);
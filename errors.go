@@ -0,0 +1,87 @@
+package filmoteka
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error kinds. Errors meant for clients wrap one of them, and the kind
+// decides the HTTP status the error is reported with.
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict")
+	ErrValidation = errors.New("validation failed")
+)
+
+var (
+	ErrInvalidCursor       = errors.New("invalid cursor")
+	ErrInvalidCredentials  = errors.New("invalid username or password")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+
+	// ErrVersionMismatch is returned by a conditional write when the
+	// resource has been changed since the client read it.
+	ErrVersionMismatch = errors.New("resource has changed since it was read")
+
+	ErrUserNotFound   = Errorf(ErrNotFound, "user not found")
+	ErrActorNotFound  = Errorf(ErrNotFound, "actor not found")
+	ErrMovieNotFound  = Errorf(ErrNotFound, "movie not found")
+	ErrGenreNotFound  = Errorf(ErrNotFound, "genre not found")
+	ErrPersonNotFound = Errorf(ErrNotFound, "person not found")
+	ErrReviewNotFound = Errorf(ErrNotFound, "review not found")
+
+	ErrCollectionNotFound      = Errorf(ErrNotFound, "collection not found")
+	ErrCollectionMovieNotFound = Errorf(ErrNotFound, "movie is not in the collection")
+	ErrWatchedNotFound         = Errorf(ErrNotFound, "movie is not marked as watched")
+
+	ErrUsernameTaken = Errorf(ErrConflict, "username is already taken")
+	ErrActorExists   = Errorf(ErrConflict, "actor with the same name already exists")
+	ErrMovieExists   = Errorf(ErrConflict, "movie with the same parameters already exists")
+	ErrGenreExists   = Errorf(ErrConflict, "genre with the same name already exists")
+	ErrPersonExists  = Errorf(ErrConflict, "person with the same name already exists")
+	ErrPersonInUse   = Errorf(ErrConflict, "person is credited in movies")
+	ErrReviewExists  = Errorf(ErrConflict, "movie is already reviewed, edit the review instead")
+
+	ErrCollectionExists   = Errorf(ErrConflict, "collection with the same name already exists")
+	ErrCollectionKindUsed = Errorf(ErrConflict, "only one watchlist and one favorites collection are allowed")
+	ErrMovieInCollection  = Errorf(ErrConflict, "movie is already in the collection")
+
+	// ErrCollectionOrder is returned by a reorder that doesn't list every
+	// movie of the collection exactly once.
+	ErrCollectionOrder = Errorf(ErrValidation, "movie_ids must list every movie of the collection exactly once")
+)
+
+// kindError is an error of a given kind whose message is safe to show to
+// clients.
+type kindError struct {
+	kind    error
+	message string
+}
+
+func (e *kindError) Error() string {
+	return e.message
+}
+
+func (e *kindError) Unwrap() error {
+	return e.kind
+}
+
+// Errorf formats an error of the given kind. errors.Is(err, kind) holds
+// for the result.
+func Errorf(kind error, format string, args ...interface{}) error {
+	return &kindError{kind: kind, message: fmt.Sprintf(format, args...)}
+}
+
+// ActorInUseError is returned when an actor can't be deleted in restrict
+// mode because they are still linked to movies.
+type ActorInUseError struct {
+	ActorId int
+	Movies  []MovieSummary
+}
+
+func (e *ActorInUseError) Error() string {
+	return fmt.Sprintf("actor %d appears in %d movie(s)", e.ActorId, len(e.Movies))
+}
+
+func (e *ActorInUseError) Unwrap() error {
+	return ErrConflict
+}
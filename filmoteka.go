@@ -1,69 +1,531 @@
 package filmoteka
 
 import (
-	"errors"
+	"encoding/json"
+	"fmt"
+	"strings"
 )
 
 type Actors struct {
 	Id          int    `json:"id" db:"id"`
-	FirstName   string `json:"first_name" db:"first_name"`
-	LastName    string `json:"last_name" db:"last_name"`
-	Gender      string `json:"gender" db:"gender"`
-	DateOfBirth string `json:"date_of_birth" db:"date_of_birth"`
+	FirstName   string `json:"first_name" db:"first_name" validate:"required,max=100"`
+	LastName    string `json:"last_name" db:"last_name" validate:"max=100"`
+	Gender      string `json:"gender" db:"gender" validate:"required,oneof=male female other"`
+	DateOfBirth string `json:"date_of_birth" db:"date_of_birth" validate:"required,date,notfuture"`
 }
 
-type Movies struct {
+// Person is anyone credited on a movie, in front of or behind the camera.
+// KnownFor is the department they mostly work in; people known for acting
+// are actors even before they are cast.
+type Person struct {
 	Id          int    `json:"id" db:"id"`
-	Title       string `json:"title" db:"title"`
-	Description string `json:"description" db:"description"`
-	ReleaseDate string `json:"release_date" db:"release_date"`
-	Rating      int    `json:"rating" db:"rating"`
+	FirstName   string `json:"first_name" db:"first_name" validate:"required,max=100"`
+	LastName    string `json:"last_name" db:"last_name" validate:"max=100"`
+	Gender      string `json:"gender" db:"gender" validate:"required,oneof=male female other"`
+	DateOfBirth string `json:"date_of_birth" db:"date_of_birth" validate:"required,date,notfuture"`
+	KnownFor    string `json:"known_for" db:"known_for" validate:"required,oneof=acting directing writing production sound camera editing"`
+}
+
+// PersonWithCredits is a person with their acting credits in Movies and
+// the rest in Crew.
+type PersonWithCredits struct {
+	Person
+	Movies  Appearances `json:"movies" db:"movies"`
+	Crew    CrewCredits `json:"crew" db:"crew"`
+	Version int         `json:"-" db:"version"`
+}
+
+// Movies is a movie's own columns. OriginalTitle, Country, Runtime (in
+// minutes) and AgeRating are optional: blank or 0 means unknown.
+type Movies struct {
+	Id            int    `json:"id" db:"id"`
+	Title         string `json:"title" db:"title" validate:"required,max=150"`
+	OriginalTitle string `json:"original_title" db:"original_title" validate:"max=150"`
+	Description   string `json:"description" db:"description" validate:"max=1000"`
+	ReleaseDate   string `json:"release_date" db:"release_date" validate:"required,date,notfuture"`
+	Rating        int    `json:"rating" db:"rating" validate:"min=0,max=10"`
+	Country       string `json:"country" db:"country" validate:"country"`
+	Runtime       int    `json:"runtime" db:"runtime" validate:"min=0,max=1000"`
+	AgeRating     string `json:"age_rating" db:"age_rating" validate:"oneof=0+ 6+ 12+ 16+ 18+"`
+}
+
+type Genre struct {
+	Id   int    `json:"id" db:"id"`
+	Name string `json:"name" db:"name" validate:"required,max=50"`
 }
 
 type ActorsWithMovies struct {
-	Id          int    `json:"id" db:"id"`
-	FirstName   string `json:"first_name" db:"first_name"`
-	LastName    string `json:"last_name" db:"last_name"`
-	Gender      string `json:"gender" db:"gender"`
-	DateOfBirth string `json:"date_of_birth" db:"date_of_birth"`
-	Movies      string `json:"movies" db:"movies"`
+	Id          int         `json:"id" db:"id"`
+	FirstName   string      `json:"first_name" db:"first_name"`
+	LastName    string      `json:"last_name" db:"last_name"`
+	Gender      string      `json:"gender" db:"gender"`
+	DateOfBirth string      `json:"date_of_birth" db:"date_of_birth"`
+	Movies      Appearances `json:"movies" db:"movies"`
+	Version     int         `json:"-" db:"version"`
 }
 
+// MoviesWithActors is a movie as every read returns it. Rating is the
+// editorial rating, UserRating aggregates the ratings users gave it. State
+// is the caller's own state of the movie; it is only filled in on request,
+// after the movies are read.
 type MoviesWithActors struct {
+	Id            int         `json:"id" db:"id"`
+	Title         string      `json:"title" db:"title"`
+	OriginalTitle string      `json:"original_title" db:"original_title"`
+	Description   string      `json:"description" db:"description"`
+	ReleaseDate   string      `json:"release_date" db:"release_date"`
+	Rating        int         `json:"rating" db:"rating"`
+	Country       string      `json:"country" db:"country"`
+	Runtime       int         `json:"runtime" db:"runtime"`
+	AgeRating     string      `json:"age_rating" db:"age_rating"`
+	Genres        Genres      `json:"genres" db:"genres"`
+	Actors        Cast        `json:"actors" db:"actors"`
+	Crew          Crew        `json:"crew" db:"crew"`
+	UserRating    UserRating  `json:"user_rating" db:"user_rating"`
+	State         *MovieState `json:"state,omitempty" db:"-"`
+	Version       int         `json:"-" db:"version"`
+}
+
+// MovieSearchResult is a movie found by full-text search. Headline is a
+// fragment of the title and description with the matched words wrapped in
+// <mark> tags.
+type MovieSearchResult struct {
+	MoviesWithActors
+	Rank     float64 `json:"rank" db:"rank"`
+	Headline string  `json:"headline" db:"headline"`
+}
+
+// ActorSummary is a person as listed in a movie's cast or crew.
+type ActorSummary struct {
+	Id        int    `json:"id" db:"id"`
+	FirstName string `json:"first_name" db:"first_name"`
+	LastName  string `json:"last_name" db:"last_name"`
+}
+
+// MovieSummary is a movie as listed in an actor's filmography.
+type MovieSummary struct {
 	Id          int    `json:"id" db:"id"`
 	Title       string `json:"title" db:"title"`
-	Description string `json:"description" db:"description"`
 	ReleaseDate string `json:"release_date" db:"release_date"`
-	Rating      int    `json:"rating" db:"rating"`
-	Actors      string `json:"actors" db:"actors"`
 }
 
+// CastMember is an actor as listed in a movie's cast, with the part they
+// play there.
+type CastMember struct {
+	ActorSummary
+	CastRole
+}
+
+// Appearance is a movie as listed in an actor's filmography, with the part
+// the actor plays in it.
+type Appearance struct {
+	MovieSummary
+	CastRole
+}
+
+// CrewMember is a person as listed in a movie's crew, with the job they
+// did there.
+type CrewMember struct {
+	ActorSummary
+	Job string `json:"job" db:"job"`
+}
+
+// CrewCredit is a movie as listed in a person's crew filmography.
+type CrewCredit struct {
+	MovieSummary
+	Department string `json:"department" db:"department"`
+	Job        string `json:"job" db:"job"`
+}
+
+// CrewEntry puts a person in a movie's crew. A person may be credited with
+// several jobs on the same movie.
+type CrewEntry struct {
+	PersonId   int    `json:"person_id" validate:"min=1"`
+	Department string `json:"department" validate:"required,oneof=directing writing production sound camera editing"`
+	Job        string `json:"job" validate:"required,max=100"`
+}
+
+// CastRole is what links an actor to a movie. Billing starts at 1.
+type CastRole struct {
+	CharacterName string `json:"character_name" db:"character_name"`
+	BillingOrder  int    `json:"billing_order" db:"billing_order"`
+	RoleType      string `json:"role_type" db:"role_type"`
+}
+
+// CastEntry puts an actor in a movie's cast. It may be sent as a bare actor
+// ID, which is what casts used to be. A BillingOrder of 0 bills the actor by
+// their position in the list, a blank RoleType makes the role supporting.
+type CastEntry struct {
+	ActorId       int    `json:"actor_id" validate:"min=1"`
+	CharacterName string `json:"character_name" validate:"max=150"`
+	BillingOrder  int    `json:"billing_order" validate:"min=0"`
+	RoleType      string `json:"role_type" validate:"oneof=lead supporting cameo voice"`
+}
+
+func (c *CastEntry) UnmarshalJSON(data []byte) error {
+	var actorId int
+	if err := json.Unmarshal(data, &actorId); err == nil {
+		*c = CastEntry{ActorId: actorId}
+		return nil
+	}
+
+	type castEntry CastEntry
+	return json.Unmarshal(data, (*castEntry)(c))
+}
+
+// Cast is scanned from a JSON array built with json_agg.
+type Cast []CastMember
+
+// Appearances is scanned from a JSON array built with json_agg.
+type Appearances []Appearance
+
+// Genres is scanned from a JSON array built with json_agg.
+type Genres []Genre
+
+// Crew groups a movie's crew by department. It is scanned from a JSON
+// object built with json_object_agg.
+type Crew map[string][]CrewMember
+
+// CrewCredits is scanned from a JSON array built with json_agg.
+type CrewCredits []CrewCredit
+
+func (c *Cast) Scan(src interface{}) error {
+	*c = Cast{}
+	return scanJSON(src, c)
+}
+
+func (a *Appearances) Scan(src interface{}) error {
+	*a = Appearances{}
+	return scanJSON(src, a)
+}
+
+func (g *Genres) Scan(src interface{}) error {
+	*g = Genres{}
+	return scanJSON(src, g)
+}
+
+func (c *Crew) Scan(src interface{}) error {
+	*c = Crew{}
+	return scanJSON(src, c)
+}
+
+func (c *CrewCredits) Scan(src interface{}) error {
+	*c = CrewCredits{}
+	return scanJSON(src, c)
+}
+
+func scanJSON(src interface{}, dest interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(value, dest)
+	case string:
+		return json.Unmarshal([]byte(value), dest)
+	default:
+		return fmt.Errorf("cannot scan %T into %T", src, dest)
+	}
+}
+
+const (
+	SortByRating      = "rating"
+	SortByTitle       = "title"
+	SortByReleaseDate = "release_date"
+	SortById          = "id"
+	SortByName        = "name"
+	SortByDateOfBirth = "date_of_birth"
+
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+
+	DefaultSuggestionLimit = 10
+	DefaultSimilarity      = 0.3
+
+	SuggestionActor = "actor"
+	SuggestionMovie = "movie"
+
+	CastLead       = "lead"
+	CastSupporting = "supporting"
+	CastCameo      = "cameo"
+	CastVoice      = "voice"
+
+	DepartmentActing     = "acting"
+	DepartmentDirecting  = "directing"
+	DepartmentWriting    = "writing"
+	DepartmentProduction = "production"
+	DepartmentSound      = "sound"
+	DepartmentCamera     = "camera"
+	DepartmentEditing    = "editing"
+
+	// DeleteModeRestrict refuses to delete an actor linked to movies,
+	// DeleteModeCascade unlinks the actor from every movie first.
+	DeleteModeRestrict = "restrict"
+	DeleteModeCascade  = "cascade"
+)
+
+// PageParams holds the sorting and pagination options shared by all lists.
+// Cursor, when set, takes precedence over Offset.
+type PageParams struct {
+	Sort   string
+	Order  string
+	Limit  int
+	Offset int
+	Cursor string
+}
+
+// MovieListParams narrows a movie list down with optional filters, named
+// after the query parameters they come from. Title and Actor match a
+// fragment of the title or of an actor's name, Director of a director's
+// name. ActorId keeps only the movies that actor appears in, GenreIds the
+// movies of any of those genres.
+// MinRuntime and MaxRuntime are inclusive and 0 when not set.
+type MovieListParams struct {
+	PageParams
+	Title      string `json:"q"`
+	Actor      string `json:"actor"`
+	Director   string `json:"director"`
+	ActorId    int    `json:"-"`
+	GenreIds   []int  `json:"genre"`
+	Country    string `json:"country" validate:"country"`
+	AgeRating  string `json:"age_rating" validate:"oneof=0+ 6+ 12+ 16+ 18+"`
+	MinRuntime int    `json:"min_runtime" validate:"min=0"`
+	MaxRuntime int    `json:"max_runtime" validate:"min=0"`
+}
+
+type MoviesList struct {
+	Movies     []MoviesWithActors
+	Total      int
+	NextCursor string
+	PrevCursor string
+}
+
+// MovieSearchParams is a full-text query over movie titles and
+// descriptions. Query takes web search syntax: quoted phrases, OR and -word.
+// Actor and Director, when set, keep only movies with an actor or a director
+// whose name contains them. Results are ordered by rank, so pages are
+// addressed by offset only.
+type MovieSearchParams struct {
+	Query    string
+	Actor    string
+	Director string
+	Limit    int
+	Offset   int
+}
+
+type MovieSearchList struct {
+	Movies []MovieSearchResult
+	Total  int
+}
+
+// FuzzySearchParams is a typo tolerant query over actor full names and
+// movie titles. A name matches when its word similarity to Query, between
+// 0 and 1, is at least Threshold.
+type FuzzySearchParams struct {
+	Query     string
+	Threshold float64
+	Limit     int
+}
+
+// ActorMatch is an actor found by a fuzzy query.
+type ActorMatch struct {
+	ActorSummary
+	Similarity float64 `json:"similarity" db:"similarity"`
+}
+
+// MovieMatch is a movie found by a fuzzy query.
+type MovieMatch struct {
+	MovieSummary
+	Similarity float64 `json:"similarity" db:"similarity"`
+}
+
+type FuzzySearchResults struct {
+	Actors []ActorMatch
+	Movies []MovieMatch
+}
+
+// Suggestion is an autocomplete entry, an actor's full name or a movie
+// title depending on Type.
+type Suggestion struct {
+	Type       string  `json:"type" db:"type"`
+	Id         int     `json:"id" db:"id"`
+	Label      string  `json:"label" db:"label"`
+	Similarity float64 `json:"similarity" db:"similarity"`
+}
+
+// ActorListParams narrows an actor list down with optional filters, named
+// after the query parameters they come from. Name matches a fragment of the
+// full name, BornAfter and BornBefore are inclusive, HasMovies keeps actors
+// with (true) or without (false) any movie.
+type ActorListParams struct {
+	PageParams
+	Name       string `json:"q"`
+	Gender     string `json:"gender" validate:"oneof=male female other"`
+	BornAfter  string `json:"born_after" validate:"date"`
+	BornBefore string `json:"born_before" validate:"date"`
+	HasMovies  *bool  `json:"has_movies"`
+}
+
+// PersonListParams narrows a list of people down. Name matches a fragment
+// of the full name, Department keeps the people known for it or credited
+// in it. People are ordered by name, so pages are addressed by offset only.
+type PersonListParams struct {
+	Name       string `json:"q"`
+	Department string `json:"department" validate:"oneof=acting directing writing production sound camera editing"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+}
+
+type PeopleList struct {
+	People []Person
+	Total  int
+}
+
+type ActorsList struct {
+	Actors     []ActorsWithMovies
+	Total      int
+	NextCursor string
+	PrevCursor string
+}
+
+// UpdateActors, UpdatePerson and UpdateMovies hold partial updates: nil
+// fields are left as they are. Fields tagged patch:"nullable" may be
+// cleared with null in a merge patch, see ApplyMergePatch.
 type UpdateActors struct {
-	FirstName   *string `json:"first_name"`
-	LastName    *string `json:"last_name"`
-	Gender      *string `json:"gender"`
-	DateOfBirth *string `json:"date_of_birth"`
+	FirstName   *string `json:"first_name" validate:"required,max=100"`
+	LastName    *string `json:"last_name" validate:"max=100" patch:"nullable"`
+	Gender      *string `json:"gender" validate:"required,oneof=male female other"`
+	DateOfBirth *string `json:"date_of_birth" validate:"required,date,notfuture"`
+}
+
+type UpdatePerson struct {
+	FirstName   *string `json:"first_name" validate:"required,max=100"`
+	LastName    *string `json:"last_name" validate:"max=100" patch:"nullable"`
+	Gender      *string `json:"gender" validate:"required,oneof=male female other"`
+	DateOfBirth *string `json:"date_of_birth" validate:"required,date,notfuture"`
+	KnownFor    *string `json:"known_for" validate:"required,oneof=acting directing writing production sound camera editing"`
 }
 
 type UpdateMovies struct {
-	Id          *int    `json:"id" db:"id"`
-	Title       *string `json:"title" db:"title"`
-	Description *string `json:"description" db:"description"`
-	ReleaseDate *string `json:"release_date" db:"release_date"`
-	Rating      *int    `json:"rating" db:"rating"`
-	Actors      *[]int  `json:"actors" db:"actors"`
+	Id            *int         `json:"id" db:"id"`
+	Title         *string      `json:"title" db:"title" validate:"required,max=150"`
+	OriginalTitle *string      `json:"original_title" db:"original_title" validate:"max=150" patch:"nullable"`
+	Description   *string      `json:"description" db:"description" validate:"max=1000" patch:"nullable"`
+	ReleaseDate   *string      `json:"release_date" db:"release_date" validate:"required,date,notfuture"`
+	Rating        *int         `json:"rating" db:"rating" validate:"min=0,max=10"`
+	Country       *string      `json:"country" db:"country" validate:"country" patch:"nullable"`
+	Runtime       *int         `json:"runtime" db:"runtime" validate:"min=0,max=1000" patch:"nullable"`
+	AgeRating     *string      `json:"age_rating" db:"age_rating" validate:"oneof=0+ 6+ 12+ 16+ 18+" patch:"nullable"`
+	Actors        *[]CastEntry `json:"actors" db:"actors" validate:"dive" patch:"nullable"`
+	Crew          *[]CrewEntry `json:"crew" db:"crew" validate:"dive" patch:"nullable"`
+	Genres        *[]int       `json:"genres" db:"genres" patch:"nullable"`
+}
+
+func (a Actors) Validate() error {
+	return Validate(a)
+}
+
+func (m Movies) Validate() error {
+	return Validate(m)
+}
+
+func (p Person) Validate() error {
+	return Validate(p)
+}
+
+func (g Genre) Validate() error {
+	return Validate(g)
 }
 
 func (u UpdateActors) Validate() error {
 	if u.FirstName == nil && u.LastName == nil && u.Gender == nil && u.DateOfBirth == nil {
-		return errors.New("update structure has no values")
+		return Errorf(ErrValidation, "update structure has no values")
 	}
-	return nil
+	return Validate(u)
+}
+
+func (u UpdatePerson) Validate() error {
+	if u.FirstName == nil && u.LastName == nil && u.Gender == nil && u.DateOfBirth == nil && u.KnownFor == nil {
+		return Errorf(ErrValidation, "update structure has no values")
+	}
+	return Validate(u)
 }
 
 func (u UpdateMovies) Validate() error {
-	if u.Title == nil && u.Description == nil && u.ReleaseDate == nil && u.Rating == nil && u.Actors == nil {
-		return errors.New("update structure has no values")
+	if u.Title == nil && u.OriginalTitle == nil && u.Description == nil && u.ReleaseDate == nil && u.Rating == nil &&
+		u.Country == nil && u.Runtime == nil && u.AgeRating == nil && u.Actors == nil && u.Crew == nil && u.Genres == nil {
+		return Errorf(ErrValidation, "update structure has no values")
+	}
+	return Validate(u)
+}
+
+func (p MovieListParams) Validate() error {
+	if err := p.PageParams.validate(SortByRating, SortByTitle, SortByReleaseDate, SortById); err != nil {
+		return err
+	}
+	return Validate(p)
+}
+
+func (p MovieSearchParams) Validate() error {
+	if strings.TrimSpace(p.Query) == "" {
+		return Errorf(ErrValidation, "q is required")
+	}
+	return validatePage(p.Limit, p.Offset)
+}
+
+func (p PersonListParams) Validate() error {
+	if err := Validate(p); err != nil {
+		return err
+	}
+	return validatePage(p.Limit, p.Offset)
+}
+
+func (p FuzzySearchParams) Validate() error {
+	if strings.TrimSpace(p.Query) == "" {
+		return Errorf(ErrValidation, "q is required")
+	}
+
+	if p.Threshold <= 0 || p.Threshold > 1 {
+		return Errorf(ErrValidation, "threshold must be greater than 0 and at most 1")
+	}
+	return validatePage(p.Limit, 0)
+}
+
+func (p ActorListParams) Validate() error {
+	if err := p.PageParams.validate(SortById, SortByName, SortByDateOfBirth); err != nil {
+		return err
+	}
+	return Validate(p)
+}
+
+func (p PageParams) validate(sorts ...string) error {
+	supported := false
+	for _, sort := range sorts {
+		if p.Sort == sort {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return Errorf(ErrValidation, "sort must be one of: %s", strings.Join(sorts, ", "))
+	}
+
+	if p.Order != OrderAsc && p.Order != OrderDesc {
+		return Errorf(ErrValidation, "order must be one of: asc, desc")
+	}
+
+	return validatePage(p.Limit, p.Offset)
+}
+
+func validatePage(limit, offset int) error {
+	if limit < 1 || limit > MaxListLimit {
+		return Errorf(ErrValidation, "limit must be between 1 and %d", MaxListLimit)
+	}
+
+	if offset < 0 {
+		return Errorf(ErrValidation, "offset must not be negative")
 	}
 	return nil
 }
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -10,10 +11,10 @@ import (
 )
 
 type CreateActorRequest struct {
-	FirstName   string `json:"first_name"`
-	LastName    string `json:"last_name"`
-	Gender      string `json:"gender"`
-	DateOfBirth string `json:"date_of_birth"`
+	FirstName   string `json:"first_name" maxLength:"100"`
+	LastName    string `json:"last_name" maxLength:"100"`
+	Gender      string `json:"gender" enums:"male,female,other"`
+	DateOfBirth string `json:"date_of_birth" format:"date"`
 }
 
 // @Summary Create Actor
@@ -24,19 +25,16 @@ type CreateActorRequest struct {
 // @Produce json
 // @Param input body CreateActorRequest true "Actor information"
 // @Success 200 {string} string "id"
-// @Failure 400 {object} Err
-// @Failure 404 {object} Err
-// @Failure 500 {object} Err
-// @Router /api/actors/create [post]
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/actors [post]
 func (h *Handler) handleCreateActor(w http.ResponseWriter, r *http.Request) {
 
 	logger.Log.Info("Handling Create Actor request")
 
-	if err := h.checkAdminStatus(w, r); err != nil {
-		logger.Log.Error("Admin status is not available:", err.Error())
-		NewErrorResponse(w, http.StatusForbidden, "This function is only available to the administrator")
-		return
-	}
 	var input filmoteka.Actors
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		logger.Log.Error("Failed to decaode request body:", err.Error())
@@ -47,7 +45,7 @@ func (h *Handler) handleCreateActor(w http.ResponseWriter, r *http.Request) {
 	id, err := h.service.Actors.CreateActor(input)
 	if err != nil {
 		logger.Log.Error("Failed to create actor:", err.Error())
-		NewErrorResponse(w, http.StatusInternalServerError, err.Error())
+		writeError(w, err)
 		return
 	}
 
@@ -64,36 +62,55 @@ func (h *Handler) handleCreateActor(w http.ResponseWriter, r *http.Request) {
 
 type getActorsResponse struct {
 	Data []filmoteka.ActorsWithMovies `json:"data"`
+	Meta *listMeta                    `json:"meta,omitempty"`
 }
 
 // @Summary Get All Actors
 // @Security ApiKeyAuth
 // @Tags actors
-// @Description Get List of Actors
+// @Description Get a page of Actors sorted by id, name (last name first) or date of birth.
+// @Description q, gender, born_after, born_before and has_movies narrow the list down; the dates are inclusive.
+// @Description Pass next_cursor or prev_cursor from a previous page as cursor to scroll without offsets.
 // @Accept json
 // @Produce json
+// @Param q query string false "Fragment of the full name"
+// @Param gender query string false "Gender" Enums(male, female, other)
+// @Param born_after query string false "Born on or after, YYYY-MM-DD" format(date)
+// @Param born_before query string false "Born on or before, YYYY-MM-DD" format(date)
+// @Param has_movies query bool false "Only actors with (true) or without (false) movies"
+// @Param sort query string false "Sort field" Enums(id, name, date_of_birth) default(id)
+// @Param order query string false "Sort order" Enums(asc, desc) default(asc)
+// @Param limit query int false "Page size" default(20) maximum(100)
+// @Param offset query int false "Number of actors to skip, ignored with cursor" default(0)
+// @Param cursor query string false "Opaque page cursor"
 // @Success 200 {object} getActorsResponse
-// @Failure 400 {object} Err
-// @Failure 404 {object} Err
-// @Failure 500 {object} Err
-// @Router /api/actors [get]
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/actors [get]
 func (h *Handler) handleGetAllActors(w http.ResponseWriter, r *http.Request) {
 
 	logger.Log.Info("Handling Get All Actors")
 
-	actors, err := h.service.ActorsWithMovies.GetActors()
+	params, err := parseActorListParams(r)
 	if err != nil {
-		logger.Log.Error("Failed to Get All Actors: ", err.Error())
-		NewErrorResponse(w, http.StatusInternalServerError, err.Error())
+		logger.Log.Error("Invalid list parameters: ", err.Error())
+		writeError(w, err)
 		return
 	}
 
-	if len(actors) == 0 {
-		NewErrorResponse(w, http.StatusOK, "The list of actors is empty")
+	list, err := h.service.ActorsWithMovies.GetActors(params)
+	if err != nil {
+		logger.Log.Error("Failed to Get All Actors: ", err.Error())
+		writeError(w, err)
 		return
 	}
 
-	response := getActorsResponse{Data: actors}
+	response := getActorsResponse{
+		Data: orEmpty(list.Actors),
+		Meta: pageMeta(list.Total, params.PageParams, list.NextCursor, list.PrevCursor),
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
@@ -102,6 +119,32 @@ func (h *Handler) handleGetAllActors(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func parseActorListParams(r *http.Request) (filmoteka.ActorListParams, error) {
+	page, err := parsePageParams(r.URL.Query(), filmoteka.SortById, filmoteka.OrderAsc)
+	if err != nil {
+		return filmoteka.ActorListParams{}, err
+	}
+
+	query := r.URL.Query()
+	params := filmoteka.ActorListParams{
+		PageParams: page,
+		Name:       strings.TrimSpace(query.Get("q")),
+		Gender:     query.Get("gender"),
+		BornAfter:  query.Get("born_after"),
+		BornBefore: query.Get("born_before"),
+	}
+
+	if value := query.Get("has_movies"); value != "" {
+		hasMovies, err := strconv.ParseBool(value)
+		if err != nil {
+			return params, filmoteka.Errorf(errMalformedRequest, "invalid has_movies parameter")
+		}
+		params.HasMovies = &hasMovies
+	}
+
+	return params, params.Validate()
+}
+
 // @Summary Get Actor By ID
 // @Security ApiKeyAuth
 // @Tags actors
@@ -110,39 +153,31 @@ func (h *Handler) handleGetAllActors(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path int true "Actor ID"
 // @Success 200 {object} filmoteka.ActorsWithMovies
-// @Failure 400 {object} Err
-// @Failure 404 {object} Err
-// @Failure 500 {object} Err
-// @Router /api/actors/{id} [get]
+// @Header 200 {string} ETag "Version of the actor, for If-Match"
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/actors/{id} [get]
 func (h *Handler) handleGetActorById(w http.ResponseWriter, r *http.Request) {
 
 	logger.Log.Info("Handling Get Actors By ID")
 
-	path := r.URL.Path
-
-	parts := strings.Split(path, "/")
-	if len(parts) < 4 {
-		logger.Log.Error("Missing ID parameter")
-		NewErrorResponse(w, http.StatusBadRequest, "missing id parameter")
-		return
-	}
-
-	idStr := parts[3]
-	id, err := strconv.Atoi(idStr)
+	id, err := pathID(r)
 	if err != nil {
-		logger.Log.Error("Invailed ID parameter: ", err.Error())
-		NewErrorResponse(w, http.StatusBadRequest, "invalid id parameter")
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	actor, err := h.service.ActorsWithMovies.GetActorById(id)
 	if err != nil {
 		logger.Log.Error("Failed to Get Actor By ID: ", err.Error())
-		NewErrorResponse(w, http.StatusInternalServerError, err.Error())
+		writeError(w, err)
 		return
 	}
 
 	response := actor
+	w.Header().Set("ETag", etag(actor.Version))
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
@@ -151,60 +186,100 @@ func (h *Handler) handleGetActorById(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// @Summary Update Actor
+// @Summary Get Actor Movies
 // @Security ApiKeyAuth
 // @Tags actors
-// @Description Update information about Actor
+// @Description Get a page of the Movies an Actor appears in, sorted by release date by default.
 // @Accept json
 // @Produce json
 // @Param id path int true "Actor ID"
+// @Param sort query string false "Sort field" Enums(rating, title, release_date, id) default(release_date)
+// @Param order query string false "Sort order" Enums(asc, desc) default(asc)
+// @Param limit query int false "Page size" default(20) maximum(100)
+// @Param offset query int false "Number of movies to skip, ignored with cursor" default(0)
+// @Param cursor query string false "Opaque page cursor"
+// @Param with_state query bool false "Add the caller's state of each movie"
+// @Success 200 {object} getMoviesResponse
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/actors/{id}/movies [get]
+func (h *Handler) handleGetActorMovies(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Get Actor Movies")
+
+	id, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params, err := parseMovieListParams(r, filmoteka.SortByReleaseDate, filmoteka.OrderAsc)
+	if err != nil {
+		logger.Log.Error("Invalid list parameters: ", err.Error())
+		writeError(w, err)
+		return
+	}
+	params.ActorId = id
+
+	h.listMovies(w, r, params)
+}
+
+// @Summary Update Actor
+// @Security ApiKeyAuth
+// @Tags actors
+// @Description Update information about Actor. PATCH takes a JSON merge patch, in which null clears a field that can be empty. Send the ETag of a previous read in If-Match to fail with 412 instead of overwriting someone else's change.
+// @Accept json,application/merge-patch+json
+// @Produce json
+// @Param id path int true "Actor ID"
+// @Param If-Match header string false "ETag the update is conditional on"
 // @Param input body filmoteka.UpdateActors true "Actor information for update"
 // @Success 200 {object} StatusResponse
-// @Failure 400 {object} Err
-// @Failure 404 {object} Err
-// @Failure 500 {object} Err
-// @Router /api/actors/{id} [put]
+// @Header 200 {string} ETag "New version of the actor"
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 412 {object} Problem
+// @Failure 415 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/actors/{id} [patch]
 func (h *Handler) handleUpdateActor(w http.ResponseWriter, r *http.Request) {
 
 	logger.Log.Info("Handling Update Actor")
 
-	if err := h.checkAdminStatus(w, r); err != nil {
-		logger.Log.Error("Admin status is not available:", err.Error())
-		NewErrorResponse(w, http.StatusForbidden, "This function is only available to the administrator")
-		return
-	}
-
-	path := r.URL.Path
-
-	parts := strings.Split(path, "/")
-	if len(parts) < 4 {
-		logger.Log.Error("Missing ID parameter")
-		NewErrorResponse(w, http.StatusBadRequest, "missing id parameter")
+	id, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	idStr := parts[3]
-	id, err := strconv.Atoi(idStr)
+	version, err := ifMatchVersion(r)
 	if err != nil {
-		logger.Log.Error("Invailid ID parameter: ", err.Error())
-		NewErrorResponse(w, http.StatusBadRequest, "invalid id parameter")
+		logger.Log.Error("Invalid If-Match header: ", err.Error())
+		writeError(w, err)
 		return
 	}
 
 	var input filmoteka.UpdateActors
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+	if err := decodeUpdate(r, &input); err != nil {
 		logger.Log.Error("Failed to decode request body: ", err.Error())
-		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		writeError(w, err)
 		return
 	}
 
-	if err := h.service.UpdateActor(id, input); err != nil {
+	newVersion, err := h.service.UpdateActor(id, input, version)
+	if err != nil {
 		logger.Log.Error("Failed to update actor: ", err.Error())
-		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		writeError(w, err)
 		return
 	}
 
 	response := StatusResponse{Status: "ok"}
+	w.Header().Set("ETag", etag(newVersion))
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
@@ -213,49 +288,66 @@ func (h *Handler) handleUpdateActor(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// actorInUseProblem lists the movies that keep an actor from being deleted.
+type actorInUseProblem struct {
+	Problem
+	Movies []filmoteka.MovieSummary `json:"movies"`
+}
+
 // @Summary Delete Actor by Id
 // @Security ApiKeyAuth
 // @Tags actors
-// @Description Delete information about Actor
+// @Description Delete information about Actor.
+// @Description In restrict mode an actor who appears in movies is kept and the blocking movies are returned with 409.
+// @Description In cascade mode the actor is unlinked from every movie first.
 // @Accept json
 // @Produce json
 // @Param id path int true "Actor ID"
+// @Param mode query string false "Delete mode" Enums(restrict, cascade) default(restrict)
 // @Success 200 {object} StatusResponse
-// @Failure 400 {object} Err
-// @Failure 404 {object} Err
-// @Failure 500 {object} Err
-// @Router /api/actors/{id} [delete]
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} actorInUseProblem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/actors/{id} [delete]
 func (h *Handler) handleDeleteActor(w http.ResponseWriter, r *http.Request) {
 
 	logger.Log.Info("Handling Delete Actor")
 
-	if err := h.checkAdminStatus(w, r); err != nil {
-		logger.Log.Error("Admin status is not available:", err.Error())
-		NewErrorResponse(w, http.StatusForbidden, "This function is only available to the administrator")
+	id, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	path := r.URL.Path
+	mode := r.URL.Query().Get("mode")
+	if mode == "" {
+		mode = filmoteka.DeleteModeRestrict
+	}
 
-	parts := strings.Split(path, "/")
-	if len(parts) < 4 {
-		logger.Log.Error("Missing ID parameter")
-		NewErrorResponse(w, http.StatusBadRequest, "missing id parameter")
+	if mode != filmoteka.DeleteModeRestrict && mode != filmoteka.DeleteModeCascade {
+		logger.Log.Error("Invalid delete mode: ", mode)
+		writeError(w, filmoteka.Errorf(filmoteka.ErrValidation, "mode must be one of: %s, %s", filmoteka.DeleteModeRestrict, filmoteka.DeleteModeCascade))
 		return
 	}
 
-	idStr := parts[3]
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		logger.Log.Error("Failed to decode request body: ", err.Error())
-		NewErrorResponse(w, http.StatusBadRequest, "invalid id parameter")
+	err = h.service.Actors.DeleteActor(id, mode)
+
+	var inUse *filmoteka.ActorInUseError
+	if errors.As(err, &inUse) {
+		logger.Log.Error("Failed to delete actor: ", err.Error())
+		writeProblem(w, http.StatusConflict, actorInUseProblem{
+			Problem: newProblem(http.StatusConflict, inUse.Error()),
+			Movies:  inUse.Movies,
+		})
 		return
 	}
 
-	err = h.service.Actors.DeleteActor(id)
 	if err != nil {
 		logger.Log.Error("Failed to delete actor: ", err.Error())
-		NewErrorResponse(w, http.StatusInternalServerError, err.Error())
+		writeError(w, err)
 		return
 	}
 
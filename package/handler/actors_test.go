@@ -2,6 +2,7 @@ package handler
 
 import (
 	"bytes"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -26,11 +27,49 @@ func TestHandler_handleCreateActor(t *testing.T) {
 		expectedRequestBody string
 	}{
 		{
-			name:                "Only for administrator",
-			inputBody:           `{"first_name":"John", "last_name":"Doe", "gender":"male", "date_of_birth":"1990-01-01"}`,
-			mockBehavior:        func(s *mock_service.MockActors, input filmoteka.Actors) {},
-			expectedStatusCode:  403,
-			expectedRequestBody: `{"error":"This function is only available to the administrator"}`,
+			name:      "OK",
+			inputBody: `{"first_name":"John", "last_name":"Doe", "gender":"male", "date_of_birth":"1990-01-01"}`,
+			inputActor: filmoteka.Actors{
+				FirstName:   "John",
+				LastName:    "Doe",
+				Gender:      "male",
+				DateOfBirth: "1990-01-01",
+			},
+			mockBehavior: func(s *mock_service.MockActors, input filmoteka.Actors) {
+				s.EXPECT().CreateActor(input).Return(1, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":1}`,
+		},
+		{
+			name:       "Already Exists",
+			inputBody:  `{"first_name":"John", "last_name":"Doe"}`,
+			inputActor: filmoteka.Actors{FirstName: "John", LastName: "Doe"},
+			mockBehavior: func(s *mock_service.MockActors, input filmoteka.Actors) {
+				s.EXPECT().CreateActor(input).Return(0, filmoteka.ErrActorExists)
+			},
+			expectedStatusCode:  409,
+			expectedRequestBody: `{"type":"about:blank","title":"Conflict","status":409,"detail":"actor with the same name already exists"}`,
+		},
+		{
+			name:       "Invalid Fields",
+			inputBody:  `{"first_name":"", "gender":"robot"}`,
+			inputActor: filmoteka.Actors{Gender: "robot"},
+			mockBehavior: func(s *mock_service.MockActors, input filmoteka.Actors) {
+				s.EXPECT().CreateActor(input).Return(0, input.Validate())
+			},
+			expectedStatusCode:  422,
+			expectedRequestBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"request has invalid fields","errors":[{"field":"first_name","message":"is required"},{"field":"gender","message":"must be one of: male, female, other"},{"field":"date_of_birth","message":"is required"}]}`,
+		},
+		{
+			name:       "Database Failure",
+			inputBody:  `{"first_name":"John", "last_name":"Doe"}`,
+			inputActor: filmoteka.Actors{FirstName: "John", LastName: "Doe"},
+			mockBehavior: func(s *mock_service.MockActors, input filmoteka.Actors) {
+				s.EXPECT().CreateActor(input).Return(0, errors.New(`pq: relation "actors" does not exist`))
+			},
+			expectedStatusCode:  500,
+			expectedRequestBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"internal server error"}`,
 		},
 	}
 	for _, testCase := range testTable {
@@ -60,6 +99,9 @@ func TestHandler_handleCreateActor(t *testing.T) {
 			//Asserts
 			assert.Equal(t, testCase.expectedStatusCode, w.Code)
 			assert.Equal(t, expected, actual)
+			if testCase.expectedStatusCode != 200 {
+				assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+			}
 
 		})
 	}
@@ -70,38 +112,118 @@ func TestHandler_handleGetAllActors(t *testing.T) {
 
 	testTable := []struct {
 		name                string
+		requestURL          string
 		mockBehavior        mockBehaivior
 		expectedStatusCode  int
 		expectedRequestBody string
 	}{
 		{
-			name: "OK",
+			name:       "OK",
+			requestURL: "/api/actors",
 			mockBehavior: func(s *mock_service.MockActorsWithMovies) {
-				actor := []filmoteka.ActorsWithMovies{
-					{
-						Id:          1,
-						FirstName:   "Jhon",
-						LastName:    "Doe",
-						Gender:      "male",
-						DateOfBirth: "1970-01-01",
-						Movies:      "Terminator",
+				list := filmoteka.ActorsList{
+					Actors: []filmoteka.ActorsWithMovies{
+						{
+							Id:          1,
+							FirstName:   "Jhon",
+							LastName:    "Doe",
+							Gender:      "male",
+							DateOfBirth: "1970-01-01",
+							Movies:      filmoteka.Appearances{{MovieSummary: filmoteka.MovieSummary{Id: 1, Title: "Terminator", ReleaseDate: "1984-10-26"}, CastRole: filmoteka.CastRole{CharacterName: "T-800", BillingOrder: 1, RoleType: filmoteka.CastLead}}},
+						},
 					},
+					Total:      2,
+					NextCursor: "next",
 				}
 
-				s.EXPECT().GetActors().Return(actor, nil)
+				params := filmoteka.ActorListParams{PageParams: filmoteka.PageParams{Sort: "id", Order: "asc", Limit: 20}}
+				s.EXPECT().GetActors(params).Return(list, nil)
 			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `{"data":[{"id":1,"first_name":"Jhon","last_name":"Doe","gender":"male","date_of_birth":"1970-01-01","movies":"Terminator"}]}`,
+			expectedRequestBody: `{"data":[{"id":1,"first_name":"Jhon","last_name":"Doe","gender":"male","date_of_birth":"1970-01-01","movies":[{"id":1,"title":"Terminator","release_date":"1984-10-26","character_name":"T-800","billing_order":1,"role_type":"lead"}]}],"meta":{"total":2,"limit":20,"offset":0,"next_cursor":"next"}}`,
 		},
 		{
-			name: "Empty List",
+			//The offset is ignored next to a cursor and isn't echoed back
+			name:       "With cursor",
+			requestURL: "/api/actors?cursor=next&limit=1&offset=5",
 			mockBehavior: func(s *mock_service.MockActorsWithMovies) {
-				actor := []filmoteka.ActorsWithMovies{}
+				list := filmoteka.ActorsList{
+					Actors: []filmoteka.ActorsWithMovies{
+						{
+							Id:          2,
+							FirstName:   "Jane",
+							LastName:    "Doe",
+							Gender:      "female",
+							DateOfBirth: "1971-01-01",
+							Movies:      filmoteka.Appearances{{MovieSummary: filmoteka.MovieSummary{Id: 1, Title: "Terminator", ReleaseDate: "1984-10-26"}, CastRole: filmoteka.CastRole{CharacterName: "T-800", BillingOrder: 1, RoleType: filmoteka.CastLead}}},
+						},
+					},
+					Total:      2,
+					PrevCursor: "prev",
+				}
 
-				s.EXPECT().GetActors().Return(actor, nil)
+				params := filmoteka.ActorListParams{PageParams: filmoteka.PageParams{Sort: "id", Order: "asc", Limit: 1, Offset: 5, Cursor: "next"}}
+				s.EXPECT().GetActors(params).Return(list, nil)
 			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `{"error":"The list of actors is empty"}`,
+			expectedRequestBody: `{"data":[{"id":2,"first_name":"Jane","last_name":"Doe","gender":"female","date_of_birth":"1971-01-01","movies":[{"id":1,"title":"Terminator","release_date":"1984-10-26","character_name":"T-800","billing_order":1,"role_type":"lead"}]}],"meta":{"total":2,"limit":1,"offset":0,"prev_cursor":"prev"}}`,
+		},
+		{
+			name:       "Invalid cursor",
+			requestURL: "/api/actors?cursor=garbage",
+			mockBehavior: func(s *mock_service.MockActorsWithMovies) {
+				s.EXPECT().GetActors(gomock.Any()).Return(filmoteka.ActorsList{}, filmoteka.ErrInvalidCursor)
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid cursor"}`,
+		},
+		{
+			name:                "Unsupported sort field",
+			requestURL:          "/api/actors?sort=rating",
+			mockBehavior:        func(s *mock_service.MockActorsWithMovies) {},
+			expectedStatusCode:  422,
+			expectedRequestBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"sort must be one of: id, name, date_of_birth"}`,
+		},
+		{
+			name:       "Filters",
+			requestURL: "/api/actors?q=%20smith%20&gender=male&born_after=1960-01-01&born_before=1990-12-31&has_movies=true&sort=date_of_birth&order=desc",
+			mockBehavior: func(s *mock_service.MockActorsWithMovies) {
+				hasMovies := true
+				params := filmoteka.ActorListParams{
+					PageParams: filmoteka.PageParams{Sort: "date_of_birth", Order: "desc", Limit: 20},
+					Name:       "smith",
+					Gender:     "male",
+					BornAfter:  "1960-01-01",
+					BornBefore: "1990-12-31",
+					HasMovies:  &hasMovies,
+				}
+				s.EXPECT().GetActors(params).Return(filmoteka.ActorsList{}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"data":[],"meta":{"total":0,"limit":20,"offset":0}}`,
+		},
+		{
+			name:                "Invalid Filters",
+			requestURL:          "/api/actors?gender=Мужчина&born_after=01.01.1960",
+			mockBehavior:        func(s *mock_service.MockActorsWithMovies) {},
+			expectedStatusCode:  422,
+			expectedRequestBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"request has invalid fields","errors":[{"field":"gender","message":"must be one of: male, female, other"},{"field":"born_after","message":"must be a date in YYYY-MM-DD format"}]}`,
+		},
+		{
+			name:                "Malformed has_movies",
+			requestURL:          "/api/actors?has_movies=maybe",
+			mockBehavior:        func(s *mock_service.MockActorsWithMovies) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid has_movies parameter"}`,
+		},
+		{
+			name:       "Empty List",
+			requestURL: "/api/actors",
+			mockBehavior: func(s *mock_service.MockActorsWithMovies) {
+				s.EXPECT().GetActors(gomock.Any()).Return(filmoteka.ActorsList{}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"data":[],"meta":{"total":0,"limit":20,"offset":0}}`,
 		},
 	}
 	for _, testCase := range testTable {
@@ -119,7 +241,7 @@ func TestHandler_handleGetAllActors(t *testing.T) {
 			mux := http.NewServeMux()
 			mux.HandleFunc("/api/actors", handler.handleGetAllActors)
 
-			req := httptest.NewRequest("GET", "/api/actors", nil)
+			req := httptest.NewRequest("GET", testCase.requestURL, nil)
 
 			w := httptest.NewRecorder()
 
@@ -156,21 +278,21 @@ func TestHandler_handleGetActorById(t *testing.T) {
 					LastName:    "Doe",
 					Gender:      "male",
 					DateOfBirth: "1970-01-01",
-					Movies:      "Terminator",
+					Movies:      filmoteka.Appearances{{MovieSummary: filmoteka.MovieSummary{Id: 1, Title: "Terminator", ReleaseDate: "1984-10-26"}, CastRole: filmoteka.CastRole{CharacterName: "T-800", BillingOrder: 1, RoleType: filmoteka.CastLead}}},
 				}
 
 				s.EXPECT().GetActorById(id).Return(actor, nil)
 			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `{"id":1,"first_name":"Jhon","last_name":"Doe","gender":"male","date_of_birth":"1970-01-01","movies":"Terminator"}`,
+			expectedRequestBody: `{"id":1,"first_name":"Jhon","last_name":"Doe","gender":"male","date_of_birth":"1970-01-01","movies":[{"id":1,"title":"Terminator","release_date":"1984-10-26","character_name":"T-800","billing_order":1,"role_type":"lead"}]}`,
 		},
 		{
 			name:       "Invalid ID parameter",
-			requestURL: "/api/actors/",
+			requestURL: "/api/actors/abc",
 			mockBehavior: func(s *mock_service.MockActorsWithMovies, id int) {
 			},
 			expectedStatusCode:  400,
-			expectedRequestBody: `{"error":"invalid id parameter"}`,
+			expectedRequestBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid id parameter"}`,
 		},
 	}
 	for _, testCase := range testTable {
@@ -186,7 +308,7 @@ func TestHandler_handleGetActorById(t *testing.T) {
 			handler := NewHandler(services)
 
 			mux := http.NewServeMux()
-			mux.HandleFunc("/api/actors/", handler.handleGetActorById)
+			mux.HandleFunc("GET /api/actors/{id}", handler.handleGetActorById)
 
 			req := httptest.NewRequest("GET", testCase.requestURL, nil)
 
@@ -211,23 +333,36 @@ func TestHandler_handleUpdateActor(t *testing.T) {
 	testTable := []struct {
 		name                string
 		requestURL          string
+		ifMatch             string
 		mockBehavior        mockBehavior
 		expectedStatusCode  int
 		expectedRequestBody string
 	}{
 		{
-			name:                "Only for administrator",
-			requestURL:          "/actors/update/1",
-			mockBehavior:        func(s *mock_service.MockActors, id int) {},
-			expectedStatusCode:  403,
-			expectedRequestBody: `{"error":"This function is only available to the administrator"}`,
+			name:       "OK",
+			requestURL: "/actors/update/1",
+			mockBehavior: func(s *mock_service.MockActors, id int) {
+				s.EXPECT().UpdateActor(id, filmoteka.UpdateActors{}, 0).Return(2, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+		{
+			name:       "Stale Version",
+			requestURL: "/actors/update/1",
+			ifMatch:    `"1"`,
+			mockBehavior: func(s *mock_service.MockActors, id int) {
+				s.EXPECT().UpdateActor(id, filmoteka.UpdateActors{}, 1).Return(0, filmoteka.ErrVersionMismatch)
+			},
+			expectedStatusCode:  412,
+			expectedRequestBody: `{"type":"about:blank","title":"Precondition Failed","status":412,"detail":"resource has changed since it was read"}`,
 		},
 		{
 			name:                "Missing ID Parameter",
 			requestURL:          "/actors/update",
 			mockBehavior:        func(s *mock_service.MockActors, id int) {},
-			expectedStatusCode:  301,
-			expectedRequestBody: ``,
+			expectedStatusCode:  404,
+			expectedRequestBody: `404 page not found`,
 		},
 	}
 	for _, testCase := range testTable {
@@ -243,9 +378,12 @@ func TestHandler_handleUpdateActor(t *testing.T) {
 			handler := NewHandler(services)
 
 			mux := http.NewServeMux()
-			mux.HandleFunc("/actors/update/", handler.handleUpdateActor)
+			mux.HandleFunc("PUT /actors/update/{id}", handler.handleUpdateActor)
 
-			req := httptest.NewRequest("PUT", testCase.requestURL, nil)
+			req := httptest.NewRequest("PUT", testCase.requestURL, bytes.NewBufferString(`{}`))
+			if testCase.ifMatch != "" {
+				req.Header.Set("If-Match", testCase.ifMatch)
+			}
 
 			w := httptest.NewRecorder()
 
@@ -273,18 +411,20 @@ func TestHandler_handleDeleteActor(t *testing.T) {
 		expectedRequestBody string
 	}{
 		{
-			name:                "Only for administrator",
-			requestURL:          "/actors/delete/1",
-			mockBehavior:        func(s *mock_service.MockActors, id int) {},
-			expectedStatusCode:  403,
-			expectedRequestBody: `{"error":"This function is only available to the administrator"}`,
+			name:       "OK",
+			requestURL: "/actors/delete/1",
+			mockBehavior: func(s *mock_service.MockActors, id int) {
+				s.EXPECT().DeleteActor(id, filmoteka.DeleteModeRestrict).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
 		},
 		{
 			name:                "Missing ID Parameter",
 			requestURL:          "/actors/delete",
 			mockBehavior:        func(s *mock_service.MockActors, id int) {},
-			expectedStatusCode:  301,
-			expectedRequestBody: ``,
+			expectedStatusCode:  404,
+			expectedRequestBody: `404 page not found`,
 		},
 	}
 	for _, testCase := range testTable {
@@ -300,7 +440,87 @@ func TestHandler_handleDeleteActor(t *testing.T) {
 			handler := NewHandler(services)
 
 			mux := http.NewServeMux()
-			mux.HandleFunc("/actors/delete/", handler.handleDeleteActor)
+			mux.HandleFunc("DELETE /actors/delete/{id}", handler.handleDeleteActor)
+
+			req := httptest.NewRequest("DELETE", testCase.requestURL, nil)
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
+
+func TestHandler_handleDeleteActorModes(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockActors)
+
+	testTable := []struct {
+		name                string
+		requestURL          string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:       "Restrict by default",
+			requestURL: "/api/actors/1",
+			mockBehavior: func(s *mock_service.MockActors) {
+				s.EXPECT().DeleteActor(1, "restrict").Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+		{
+			name:       "Blocked by movies",
+			requestURL: "/api/actors/1?mode=restrict",
+			mockBehavior: func(s *mock_service.MockActors) {
+				s.EXPECT().DeleteActor(1, "restrict").Return(&filmoteka.ActorInUseError{
+					ActorId: 1,
+					Movies:  []filmoteka.MovieSummary{{Id: 1, Title: "Dune", ReleaseDate: "2021-09-03"}},
+				})
+			},
+			expectedStatusCode:  409,
+			expectedRequestBody: `{"type":"about:blank","title":"Conflict","status":409,"detail":"actor 1 appears in 1 movie(s)","movies":[{"id":1,"title":"Dune","release_date":"2021-09-03"}]}`,
+		},
+		{
+			name:       "Cascade",
+			requestURL: "/api/actors/1?mode=cascade",
+			mockBehavior: func(s *mock_service.MockActors) {
+				s.EXPECT().DeleteActor(1, "cascade").Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+		{
+			name:                "Unknown mode",
+			requestURL:          "/api/actors/1?mode=force",
+			mockBehavior:        func(s *mock_service.MockActors) {},
+			expectedStatusCode:  422,
+			expectedRequestBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"mode must be one of: restrict, cascade"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			actorService := mock_service.NewMockActors(c)
+			testCase.mockBehavior(actorService)
+
+			services := &service.Service{Actors: actorService}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("DELETE /api/actors/{id}", handler.handleDeleteActor)
 
 			req := httptest.NewRequest("DELETE", testCase.requestURL, nil)
 
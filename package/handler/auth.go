@@ -7,22 +7,31 @@ import (
 	logger "vk_restAPI/logs"
 )
 
+// signUpInput deliberately has no role field: accounts are always created
+// as viewers.
+type signUpInput struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
 // @Summary SignUp
 // @Description  create account
 // @Tags auth
 // @Accept json
 // @Produce json
-// @Param input body filmoteka.User true "account info"
+// @Param input body signUpInput true "account info"
 // @Success 200 {integer} integer 1
-// @Failure 400 {object} Err
-// @Failure 404 {object} Err
-// @Failure 500 {object} Err
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
 // @Router       /auth/sign-up [post]
 func (h *Handler) handleSignUp(w http.ResponseWriter, r *http.Request) {
 
 	logger.Log.Info("Handling Sign Up")
 
-	var input filmoteka.User
+	var input signUpInput
 
 	err := json.NewDecoder(r.Body).Decode(&input)
 	if err != nil {
@@ -32,16 +41,19 @@ func (h *Handler) handleSignUp(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if input.Username == "" || input.Password == "" {
-		errMsg := "Username and password are required"
-		logger.Log.Error(errMsg)
-		NewErrorResponse(w, http.StatusBadRequest, errMsg)
+		err := filmoteka.Errorf(filmoteka.ErrValidation, "Username and password are required")
+		logger.Log.Error(err.Error())
+		writeError(w, err)
 		return
 	}
 
-	id, err := h.service.Authorization.CreateUser(input)
+	id, err := h.service.Authorization.CreateUser(filmoteka.User{
+		Username: input.Username,
+		Password: input.Password,
+	})
 	if err != nil {
 		logger.Log.Error("Failed to create new user:", err.Error())
-		NewErrorResponse(w, http.StatusInternalServerError, err.Error())
+		writeError(w, err)
 		return
 	}
 
@@ -67,10 +79,12 @@ type logInInInput struct {
 // @Accept json
 // @Produce json
 // @Param input body logInInInput true "credentials"
-// @Success 200 {string} string "token"
-// @Failure 400 {object} Err
-// @Failure 404 {object} Err
-// @Failure 500 {object} Err
+// @Success 200 {object} filmoteka.Tokens
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
 // @Router       /auth/log-in [post]
 func (h *Handler) handleSignIn(w http.ResponseWriter, r *http.Request) {
 
@@ -86,27 +100,138 @@ func (h *Handler) handleSignIn(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if input.Username == "" || input.Password == "" {
-		errMsg := "Username and password are required"
-		logger.Log.Error(errMsg)
-		NewErrorResponse(w, http.StatusBadRequest, errMsg)
+		err := filmoteka.Errorf(filmoteka.ErrValidation, "Username and password are required")
+		logger.Log.Error(err.Error())
+		writeError(w, err)
 		return
 	}
 
-	token, err := h.service.Authorization.GenerateToken(input.Username, input.Password)
+	tokens, err := h.service.Authorization.GenerateToken(input.Username, input.Password)
 	if err != nil {
 		logger.Log.Error("Failed to generate JWT Token:", err.Error())
-		NewErrorResponse(w, http.StatusInternalServerError, err.Error())
+		writeError(w, err)
 		return
 	}
 
-	response := map[string]interface{}{
-		"token": token,
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(tokens); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
 	}
+
+}
+
+type refreshTokenInput struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// @Summary Refresh
+// @Description  exchange a refresh token for a new token pair, the old refresh token is revoked
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body refreshTokenInput true "refresh token"
+// @Success 200 {object} filmoteka.Tokens
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router       /auth/refresh [post]
+func (h *Handler) handleRefresh(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Refresh")
+
+	refreshToken, ok := decodeRefreshToken(w, r)
+	if !ok {
+		return
+	}
+
+	tokens, err := h.service.Authorization.RefreshToken(refreshToken)
+	if err != nil {
+		logger.Log.Error("Failed to refresh token:", err.Error())
+		writeError(w, err)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
-	if err := json.NewEncoder(w).Encode(response); err != nil {
+	if err := json.NewEncoder(w).Encode(tokens); err != nil {
 		logger.Log.Error("Failed to encode response", err.Error())
 	}
+}
 
+// @Summary LogOut
+// @Description  revoke the refresh token and every token rotated from the same log in
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body refreshTokenInput true "refresh token"
+// @Success 200 {object} StatusResponse
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router       /auth/logout [post]
+func (h *Handler) handleLogout(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Log Out")
+
+	refreshToken, ok := decodeRefreshToken(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.service.Authorization.Logout(refreshToken)
+	if err != nil {
+		logger.Log.Error("Failed to log out:", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(StatusResponse{Status: "ok"}); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+func decodeRefreshToken(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var input refreshTokenInput
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logger.Log.Error("Failed to decaode request body:", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return "", false
+	}
+
+	if input.RefreshToken == "" {
+		err := filmoteka.Errorf(filmoteka.ErrValidation, "Refresh token is required")
+		logger.Log.Error(err.Error())
+		writeError(w, err)
+		return "", false
+	}
+
+	return input.RefreshToken, true
+}
+
+// @Summary JWKS
+// @Description  public keys access tokens can be verified with
+// @Tags auth
+// @Produce json
+// @Success 200 {object} service.JSONWebKeySet
+// @Router       /.well-known/jwks.json [get]
+func (h *Handler) handleJWKS(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling JWKS")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(h.service.Authorization.JWKS()); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
 }
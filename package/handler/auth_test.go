@@ -28,11 +28,10 @@ func TestHandler_signUp(t *testing.T) {
 	}{
 		{
 			name:      "OK",
-			inputBody: `{"username":"test", "password":"test","is_admin":true}`,
+			inputBody: `{"username":"test", "password":"test","role":"admin"}`,
 			inputUser: filmoteka.User{
 				Username: "test",
 				Password: "test",
-				Is_admin: true,
 			},
 			mockBehaivior: func(s *mock_service.MockAuthorization, user filmoteka.User) {
 				s.EXPECT().CreateUser(user).Return(1, nil)
@@ -45,22 +44,21 @@ func TestHandler_signUp(t *testing.T) {
 			inputBody: `{"username":"test"}`,
 			mockBehaivior: func(s *mock_service.MockAuthorization, user filmoteka.User) {
 			},
-			expectedStatusCode:  400,
-			expectedRequestBody: `{"error":"Username and password are required"}`,
+			expectedStatusCode:  422,
+			expectedRequestBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"Username and password are required"}`,
 		},
 		{
 			name:      "Service Failure",
-			inputBody: `{"username":"test", "password":"test","is_admin":true}`,
+			inputBody: `{"username":"test", "password":"test","role":"admin"}`,
 			inputUser: filmoteka.User{
 				Username: "test",
 				Password: "test",
-				Is_admin: true,
 			},
 			mockBehaivior: func(s *mock_service.MockAuthorization, user filmoteka.User) {
 				s.EXPECT().CreateUser(user).Return(1, errors.New("service failure"))
 			},
 			expectedStatusCode:  500,
-			expectedRequestBody: `{"error":"service failure"}`,
+			expectedRequestBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"internal server error"}`,
 		},
 	}
 	for _, testCase := range testTable {
@@ -116,16 +114,20 @@ func TestHandler_handleSignIn(t *testing.T) {
 			username:  "test",
 			password:  "test",
 			mockBehavior: func(s *mock_service.MockAuthorization, username, password string) {
-				s.EXPECT().GenerateToken(username, password).Return("testtoken", nil)
+				s.EXPECT().GenerateToken(username, password).Return(filmoteka.Tokens{
+					AccessToken:  "testtoken",
+					RefreshToken: "testrefresh",
+					ExpiresIn:    900,
+				}, nil)
 			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `{"token":"testtoken"}`,
+			expectedRequestBody: `{"token":"testtoken","refresh_token":"testrefresh","expires_in":900}`,
 		},
 		{
 			name:                "Empty Fields",
 			inputBody:           `{"password":"test"}`,
-			expectedStatusCode:  400,
-			expectedRequestBody: `{"error":"Username and password are required"}`,
+			expectedStatusCode:  422,
+			expectedRequestBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"Username and password are required"}`,
 		},
 		{
 			name:      "Service Failure",
@@ -133,10 +135,21 @@ func TestHandler_handleSignIn(t *testing.T) {
 			username:  "test",
 			password:  "test",
 			mockBehavior: func(s *mock_service.MockAuthorization, username, password string) {
-				s.EXPECT().GenerateToken(username, password).Return("", errors.New("service failure"))
+				s.EXPECT().GenerateToken(username, password).Return(filmoteka.Tokens{}, errors.New("service failure"))
 			},
 			expectedStatusCode:  500,
-			expectedRequestBody: `{"error":"service failure"}`,
+			expectedRequestBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"internal server error"}`,
+		},
+		{
+			name:      "Invalid Credentials",
+			inputBody: `{"username":"test", "password":"wrong"}`,
+			username:  "test",
+			password:  "wrong",
+			mockBehavior: func(s *mock_service.MockAuthorization, username, password string) {
+				s.EXPECT().GenerateToken(username, password).Return(filmoteka.Tokens{}, filmoteka.ErrInvalidCredentials)
+			},
+			expectedStatusCode:  401,
+			expectedRequestBody: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"invalid username or password"}`,
 		},
 	}
 	for _, testCase := range testTable {
@@ -175,3 +188,191 @@ func TestHandler_handleSignIn(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_handleJWKS(t *testing.T) {
+	// Init Deps
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	auth := mock_service.NewMockAuthorization(c)
+	auth.EXPECT().JWKS().Return(service.JSONWebKeySet{Keys: []service.JSONWebKey{
+		{Kty: "OKP", Kid: "ed-1", Use: "sig", Alg: "EdDSA", Crv: "Ed25519", X: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"},
+	}})
+
+	services := &service.Service{Authorization: auth}
+	handler := NewHandler(services)
+
+	//Test server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/jwks.json", handler.handleJWKS)
+
+	//Test request
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/.well-known/jwks.json", nil)
+
+	//Perform Request
+	mux.ServeHTTP(w, req)
+
+	//Asserts
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"keys":[{"kty":"OKP","kid":"ed-1","use":"sig","alg":"EdDSA","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}]}`,
+		strings.TrimSpace(w.Body.String()))
+}
+
+func TestHandler_handleRefresh(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAuthorization, refreshToken string)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		refreshToken        string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:         "OK",
+			inputBody:    `{"refresh_token":"oldrefresh"}`,
+			refreshToken: "oldrefresh",
+			mockBehavior: func(s *mock_service.MockAuthorization, refreshToken string) {
+				s.EXPECT().RefreshToken(refreshToken).Return(filmoteka.Tokens{
+					AccessToken:  "testtoken",
+					RefreshToken: "newrefresh",
+					ExpiresIn:    900,
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"token":"testtoken","refresh_token":"newrefresh","expires_in":900}`,
+		},
+		{
+			name:                "Empty Token",
+			inputBody:           `{}`,
+			expectedStatusCode:  422,
+			expectedRequestBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"Refresh token is required"}`,
+		},
+		{
+			name:         "Revoked Token",
+			inputBody:    `{"refresh_token":"reused"}`,
+			refreshToken: "reused",
+			mockBehavior: func(s *mock_service.MockAuthorization, refreshToken string) {
+				s.EXPECT().RefreshToken(refreshToken).Return(filmoteka.Tokens{}, filmoteka.ErrInvalidRefreshToken)
+			},
+			expectedStatusCode:  401,
+			expectedRequestBody: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"invalid refresh token"}`,
+		},
+		{
+			name:         "Service Failure",
+			inputBody:    `{"refresh_token":"oldrefresh"}`,
+			refreshToken: "oldrefresh",
+			mockBehavior: func(s *mock_service.MockAuthorization, refreshToken string) {
+				s.EXPECT().RefreshToken(refreshToken).Return(filmoteka.Tokens{}, errors.New("service failure"))
+			},
+			expectedStatusCode:  500,
+			expectedRequestBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"internal server error"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockAuthorization(c)
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(auth, testCase.refreshToken)
+			}
+
+			services := &service.Service{Authorization: auth}
+			handler := NewHandler(services)
+
+			//Test server
+			mux := http.NewServeMux()
+			mux.HandleFunc("/auth/refresh", handler.handleRefresh)
+
+			//Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/auth/refresh",
+				bytes.NewBufferString(testCase.inputBody))
+
+			//Perform Request
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
+
+func TestHandler_handleLogout(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAuthorization, refreshToken string)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		refreshToken        string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:         "OK",
+			inputBody:    `{"refresh_token":"refresh"}`,
+			refreshToken: "refresh",
+			mockBehavior: func(s *mock_service.MockAuthorization, refreshToken string) {
+				s.EXPECT().Logout(refreshToken).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+		{
+			name:         "Unknown Token",
+			inputBody:    `{"refresh_token":"unknown"}`,
+			refreshToken: "unknown",
+			mockBehavior: func(s *mock_service.MockAuthorization, refreshToken string) {
+				s.EXPECT().Logout(refreshToken).Return(filmoteka.ErrInvalidRefreshToken)
+			},
+			expectedStatusCode:  401,
+			expectedRequestBody: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"invalid refresh token"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockAuthorization(c)
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(auth, testCase.refreshToken)
+			}
+
+			services := &service.Service{Authorization: auth}
+			handler := NewHandler(services)
+
+			//Test server
+			mux := http.NewServeMux()
+			mux.HandleFunc("/auth/logout", handler.handleLogout)
+
+			//Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/auth/logout",
+				bytes.NewBufferString(testCase.inputBody))
+
+			//Perform Request
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
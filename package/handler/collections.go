@@ -0,0 +1,583 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	filmoteka "vk_restAPI"
+	logger "vk_restAPI/logs"
+)
+
+type CollectionRequest struct {
+	Name   string `json:"name" maxLength:"100"`
+	Kind   string `json:"kind" enums:"watchlist,favorites,custom" default:"custom"`
+	Public bool   `json:"public"`
+}
+
+type getCollectionsResponse struct {
+	Data []filmoteka.Collection `json:"data"`
+}
+
+type getCollectionMoviesResponse struct {
+	Data []filmoteka.CollectionMovie `json:"data"`
+	Meta *listMeta                   `json:"meta,omitempty"`
+}
+
+// @Summary Create Collection
+// @Security ApiKeyAuth
+// @Tags collections
+// @Description Create a watchlist, a favorites or a named custom collection. A user has at most one watchlist and one favorites collection. A public collection gets a slug to share it by.
+// @Accept json
+// @Produce json
+// @Param input body CollectionRequest true "Collection"
+// @Success 200 {string} string "id"
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/me/collections [post]
+func (h *Handler) handleCreateCollection(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Create Collection request")
+
+	userId, err := getUserId(r)
+	if err != nil {
+		logger.Log.Error("Failed to get user id: ", err.Error())
+		NewErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var input filmoteka.CollectionInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logger.Log.Error("Failed to decode request body: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id, err := h.service.Collections.CreateCollection(userId, input)
+	if err != nil {
+		logger.Log.Error("Failed to create collection: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := map[string]interface{}{
+		"id": id,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+// @Summary Get My Collections
+// @Security ApiKeyAuth
+// @Tags collections
+// @Description Get the caller's collections with the number of movies in each: the watchlist, the favorites, then the custom ones by name.
+// @Accept json
+// @Produce json
+// @Success 200 {object} getCollectionsResponse
+// @Failure 401 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/me/collections [get]
+func (h *Handler) handleGetMyCollections(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Get My Collections")
+
+	userId, err := getUserId(r)
+	if err != nil {
+		logger.Log.Error("Failed to get user id: ", err.Error())
+		NewErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	collections, err := h.service.Collections.GetCollections(userId)
+	if err != nil {
+		logger.Log.Error("Failed to Get Collections: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := getCollectionsResponse{Data: orEmpty(collections)}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+// @Summary Get My Collection
+// @Security ApiKeyAuth
+// @Tags collections
+// @Description Get a collection of the caller. Other users' collections are reported as not found; open shared ones through /api/v1/shared/{slug}.
+// @Accept json
+// @Produce json
+// @Param id path int true "Collection ID"
+// @Success 200 {object} filmoteka.Collection
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/me/collections/{id} [get]
+func (h *Handler) handleGetMyCollection(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Get My Collection")
+
+	id, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId, err := getUserId(r)
+	if err != nil {
+		logger.Log.Error("Failed to get user id: ", err.Error())
+		NewErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	collection, err := h.service.Collections.GetCollection(id, userId)
+	if err != nil {
+		logger.Log.Error("Failed to Get Collection: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	writeCollection(w, collection)
+}
+
+// @Summary Get Shared Collection
+// @Tags collections
+// @Description Get a collection shared by its owner. No token is needed.
+// @Accept json
+// @Produce json
+// @Param slug path string true "Slug of the collection"
+// @Success 200 {object} filmoteka.Collection
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/shared/{slug} [get]
+func (h *Handler) handleGetSharedCollection(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Get Shared Collection")
+
+	collection, err := h.service.Collections.GetSharedCollection(r.PathValue("slug"))
+	if err != nil {
+		logger.Log.Error("Failed to Get Shared Collection: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	writeCollection(w, collection)
+}
+
+func writeCollection(w http.ResponseWriter, collection filmoteka.Collection) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(collection); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+// @Summary Update Collection
+// @Security ApiKeyAuth
+// @Tags collections
+// @Description Rename a collection of the caller, share it with "public": true or make it private again with false. Sharing an already shared collection keeps its slug. PATCH takes a JSON merge patch.
+// @Accept json,application/merge-patch+json
+// @Produce json
+// @Param id path int true "Collection ID"
+// @Param input body filmoteka.UpdateCollection true "Name and sharing"
+// @Success 200 {object} StatusResponse
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 415 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/me/collections/{id} [patch]
+func (h *Handler) handleUpdateCollection(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Update Collection")
+
+	id, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId, err := getUserId(r)
+	if err != nil {
+		logger.Log.Error("Failed to get user id: ", err.Error())
+		NewErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var input filmoteka.UpdateCollection
+	if err := decodeUpdate(r, &input); err != nil {
+		logger.Log.Error("Failed to decode request body: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	if err := h.service.Collections.UpdateCollection(id, userId, input); err != nil {
+		logger.Log.Error("Failed to update collection: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := StatusResponse{Status: "ok"}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+// @Summary Delete Collection
+// @Security ApiKeyAuth
+// @Tags collections
+// @Description Delete a collection of the caller. The movies themselves stay.
+// @Accept json
+// @Produce json
+// @Param id path int true "Collection ID"
+// @Success 200 {object} StatusResponse
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/me/collections/{id} [delete]
+func (h *Handler) handleDeleteCollection(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Delete Collection")
+
+	id, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId, err := getUserId(r)
+	if err != nil {
+		logger.Log.Error("Failed to get user id: ", err.Error())
+		NewErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err := h.service.Collections.DeleteCollection(id, userId); err != nil {
+		logger.Log.Error("Failed to delete collection: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := StatusResponse{Status: "ok"}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+// @Summary Get My Collection Movies
+// @Security ApiKeyAuth
+// @Tags collections
+// @Description Get a page of the movies of a collection of the caller, in the collection's order.
+// @Accept json
+// @Produce json
+// @Param id path int true "Collection ID"
+// @Param limit query int false "Page size" default(20) maximum(100)
+// @Param offset query int false "Number of movies to skip" default(0)
+// @Param with_state query bool false "Add the caller's state of each movie"
+// @Success 200 {object} getCollectionMoviesResponse
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/me/collections/{id}/movies [get]
+func (h *Handler) handleGetMyCollectionMovies(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Get My Collection Movies")
+
+	id, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId, err := getUserId(r)
+	if err != nil {
+		logger.Log.Error("Failed to get user id: ", err.Error())
+		NewErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	params, err := parseCollectionPage(r)
+	if err != nil {
+		logger.Log.Error("Invalid list parameters: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	list, err := h.service.Collections.GetCollectionMovies(id, userId, params)
+	if err != nil {
+		logger.Log.Error("Failed to Get Collection Movies: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	movies := make([]*filmoteka.MoviesWithActors, len(list.Movies))
+	for i := range list.Movies {
+		movies[i] = &list.Movies[i].MoviesWithActors
+	}
+
+	if err := h.annotateMovies(r, movies); err != nil {
+		logger.Log.Error("Failed to annotate movies: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	writeCollectionMovies(w, list, params)
+}
+
+// @Summary Get Shared Collection Movies
+// @Tags collections
+// @Description Get a page of the movies of a shared collection, in the collection's order. No token is needed.
+// @Accept json
+// @Produce json
+// @Param slug path string true "Slug of the collection"
+// @Param limit query int false "Page size" default(20) maximum(100)
+// @Param offset query int false "Number of movies to skip" default(0)
+// @Success 200 {object} getCollectionMoviesResponse
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/shared/{slug}/movies [get]
+func (h *Handler) handleGetSharedCollectionMovies(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Get Shared Collection Movies")
+
+	params, err := parseCollectionPage(r)
+	if err != nil {
+		logger.Log.Error("Invalid list parameters: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	list, err := h.service.Collections.GetSharedCollectionMovies(r.PathValue("slug"), params)
+	if err != nil {
+		logger.Log.Error("Failed to Get Shared Collection Movies: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	writeCollectionMovies(w, list, params)
+}
+
+func parseCollectionPage(r *http.Request) (filmoteka.CollectionMoviesParams, error) {
+	query := r.URL.Query()
+
+	limit, err := queryInt(query, "limit", filmoteka.DefaultListLimit)
+	if err != nil {
+		return filmoteka.CollectionMoviesParams{}, err
+	}
+
+	offset, err := queryInt(query, "offset", 0)
+	if err != nil {
+		return filmoteka.CollectionMoviesParams{}, err
+	}
+
+	return filmoteka.CollectionMoviesParams{Limit: limit, Offset: offset}, nil
+}
+
+func writeCollectionMovies(w http.ResponseWriter, list filmoteka.CollectionMoviesList, params filmoteka.CollectionMoviesParams) {
+	response := getCollectionMoviesResponse{
+		Data: orEmpty(list.Movies),
+		Meta: &listMeta{
+			Total:  list.Total,
+			Limit:  params.Limit,
+			Offset: params.Offset,
+		},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+// @Summary Add Movie To Collection
+// @Security ApiKeyAuth
+// @Tags collections
+// @Description Append a movie to a collection of the caller.
+// @Accept json
+// @Produce json
+// @Param id path int true "Collection ID"
+// @Param input body filmoteka.CollectionItem true "Movie"
+// @Success 200 {object} StatusResponse
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/me/collections/{id}/movies [post]
+func (h *Handler) handleAddCollectionMovie(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Add Collection Movie")
+
+	id, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId, err := getUserId(r)
+	if err != nil {
+		logger.Log.Error("Failed to get user id: ", err.Error())
+		NewErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var input filmoteka.CollectionItem
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logger.Log.Error("Failed to decode request body: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.service.Collections.AddCollectionMovie(id, userId, input); err != nil {
+		logger.Log.Error("Failed to add movie to collection: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := StatusResponse{Status: "ok"}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+// @Summary Reorder Collection
+// @Security ApiKeyAuth
+// @Tags collections
+// @Description Put the movies of a collection of the caller in a new order. movie_ids lists every movie of the collection exactly once.
+// @Accept json
+// @Produce json
+// @Param id path int true "Collection ID"
+// @Param input body filmoteka.CollectionOrder true "Movie IDs in the new order"
+// @Success 200 {object} StatusResponse
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/me/collections/{id}/movies [put]
+func (h *Handler) handleReorderCollection(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Reorder Collection")
+
+	id, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId, err := getUserId(r)
+	if err != nil {
+		logger.Log.Error("Failed to get user id: ", err.Error())
+		NewErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var input filmoteka.CollectionOrder
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logger.Log.Error("Failed to decode request body: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.service.Collections.ReorderCollection(id, userId, input); err != nil {
+		logger.Log.Error("Failed to reorder collection: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := StatusResponse{Status: "ok"}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+// @Summary Remove Movie From Collection
+// @Security ApiKeyAuth
+// @Tags collections
+// @Description Take a movie out of a collection of the caller. The other movies keep their order.
+// @Accept json
+// @Produce json
+// @Param id path int true "Collection ID"
+// @Param movieId path int true "Movie ID"
+// @Success 200 {object} StatusResponse
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/me/collections/{id}/movies/{movieId} [delete]
+func (h *Handler) handleRemoveCollectionMovie(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Remove Collection Movie")
+
+	id, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	movieId, err := pathInt(r, "movieId")
+	if err != nil {
+		logger.Log.Error("Invailid movieId parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId, err := getUserId(r)
+	if err != nil {
+		logger.Log.Error("Failed to get user id: ", err.Error())
+		NewErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err := h.service.Collections.RemoveCollectionMovie(id, userId, movieId); err != nil {
+		logger.Log.Error("Failed to remove movie from collection: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := StatusResponse{Status: "ok"}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
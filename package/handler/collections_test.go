@@ -0,0 +1,347 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	filmoteka "vk_restAPI"
+	"vk_restAPI/package/service"
+	mock_service "vk_restAPI/package/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_handleCreateCollection(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockCollections)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"name":"Нолан", "public":true}`,
+			mockBehavior: func(s *mock_service.MockCollections) {
+				s.EXPECT().CreateCollection(2, filmoteka.CollectionInput{Name: "Нолан", Public: true}).Return(4, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":4}`,
+		},
+		{
+			name:      "Second Watchlist",
+			inputBody: `{"name":"Ещё посмотреть", "kind":"watchlist"}`,
+			mockBehavior: func(s *mock_service.MockCollections) {
+				s.EXPECT().CreateCollection(2, filmoteka.CollectionInput{Name: "Ещё посмотреть", Kind: "watchlist"}).
+					Return(0, filmoteka.ErrCollectionKindUsed)
+			},
+			expectedStatusCode:  409,
+			expectedRequestBody: `{"type":"about:blank","title":"Conflict","status":409,"detail":"only one watchlist and one favorites collection are allowed"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			collections := mock_service.NewMockCollections(c)
+			testCase.mockBehavior(collections)
+
+			services := &service.Service{Collections: collections}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /me/collections", handler.handleCreateCollection)
+
+			req := httptest.NewRequest("POST", "/me/collections", bytes.NewBufferString(testCase.inputBody))
+			req = req.WithContext(context.WithValue(req.Context(), userCtx, 2))
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
+
+func TestHandler_handleGetSharedCollection(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockCollections)
+
+	created := time.Date(2024, 3, 8, 20, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name                string
+		requestURL          string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:       "OK",
+			requestURL: "/shared/c2x5ZzEyMzQ1Njc4",
+			mockBehavior: func(s *mock_service.MockCollections) {
+				s.EXPECT().GetSharedCollection("c2x5ZzEyMzQ1Njc4").Return(filmoteka.Collection{
+					Id: 4, UserId: 2, Username: "user", Name: "Нолан", Kind: "custom", Slug: "c2x5ZzEyMzQ1Njc4", Movies: 3, CreatedAt: created,
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":4,"user_id":2,"username":"user","name":"Нолан","kind":"custom","slug":"c2x5ZzEyMzQ1Njc4","movies":3,"created_at":"2024-03-08T20:00:00Z"}`,
+		},
+		{
+			name:       "Not Shared",
+			requestURL: "/shared/unknown",
+			mockBehavior: func(s *mock_service.MockCollections) {
+				s.EXPECT().GetSharedCollection("unknown").Return(filmoteka.Collection{}, filmoteka.ErrCollectionNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"type":"about:blank","title":"Not Found","status":404,"detail":"collection not found"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			collections := mock_service.NewMockCollections(c)
+			testCase.mockBehavior(collections)
+
+			services := &service.Service{Collections: collections}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /shared/{slug}", handler.handleGetSharedCollection)
+
+			req := httptest.NewRequest("GET", testCase.requestURL, nil)
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
+
+func TestHandler_handleGetMyCollectionMovies(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockCollections)
+
+	added := time.Date(2024, 3, 8, 20, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name                string
+		requestURL          string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:       "OK",
+			requestURL: "/me/collections/4/movies?limit=1",
+			mockBehavior: func(s *mock_service.MockCollections) {
+				s.EXPECT().GetCollectionMovies(4, 2, filmoteka.CollectionMoviesParams{Limit: 1}).Return(filmoteka.CollectionMoviesList{
+					Movies: []filmoteka.CollectionMovie{{
+						MoviesWithActors: filmoteka.MoviesWithActors{Id: 1, Title: "Дюна 2", ReleaseDate: "2024-02-29", Rating: 9, Actors: filmoteka.Cast{}},
+						AddedAt:          added,
+					}},
+					Total: 3,
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"data":[{"id":1,"title":"Дюна 2","original_title":"","description":"","release_date":"2024-02-29","rating":9,"country":"","runtime":0,"age_rating":"","genres":null,"actors":[],"crew":null,"user_rating":{"average":0,"votes":0,"distribution":null},"added_at":"2024-03-08T20:00:00Z"}],"meta":{"total":3,"limit":1,"offset":0}}`,
+		},
+		{
+			name:       "Someone Else's Collection",
+			requestURL: "/me/collections/5/movies",
+			mockBehavior: func(s *mock_service.MockCollections) {
+				s.EXPECT().GetCollectionMovies(5, 2, filmoteka.CollectionMoviesParams{Limit: 20}).
+					Return(filmoteka.CollectionMoviesList{}, filmoteka.ErrCollectionNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"type":"about:blank","title":"Not Found","status":404,"detail":"collection not found"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			collections := mock_service.NewMockCollections(c)
+			testCase.mockBehavior(collections)
+
+			services := &service.Service{Collections: collections}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /me/collections/{id}/movies", handler.handleGetMyCollectionMovies)
+
+			req := httptest.NewRequest("GET", testCase.requestURL, nil)
+			req = req.WithContext(context.WithValue(req.Context(), userCtx, 2))
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
+
+func TestHandler_handleReorderCollection(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockCollections)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"movie_ids":[3,1,2]}`,
+			mockBehavior: func(s *mock_service.MockCollections) {
+				s.EXPECT().ReorderCollection(4, 2, filmoteka.CollectionOrder{MovieIds: []int{3, 1, 2}}).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+		{
+			name:      "Movie Missing",
+			inputBody: `{"movie_ids":[3,1]}`,
+			mockBehavior: func(s *mock_service.MockCollections) {
+				s.EXPECT().ReorderCollection(4, 2, filmoteka.CollectionOrder{MovieIds: []int{3, 1}}).Return(filmoteka.ErrCollectionOrder)
+			},
+			expectedStatusCode:  422,
+			expectedRequestBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"movie_ids must list every movie of the collection exactly once"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			collections := mock_service.NewMockCollections(c)
+			testCase.mockBehavior(collections)
+
+			services := &service.Service{Collections: collections}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("PUT /me/collections/{id}/movies", handler.handleReorderCollection)
+
+			req := httptest.NewRequest("PUT", "/me/collections/4/movies", bytes.NewBufferString(testCase.inputBody))
+			req = req.WithContext(context.WithValue(req.Context(), userCtx, 2))
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
+
+func TestHandler_handleRemoveCollectionMovie(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockCollections)
+
+	testTable := []struct {
+		name                string
+		requestURL          string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:       "OK",
+			requestURL: "/me/collections/4/movies/1",
+			mockBehavior: func(s *mock_service.MockCollections) {
+				s.EXPECT().RemoveCollectionMovie(4, 2, 1).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+		{
+			name:                "Invalid Movie ID",
+			requestURL:          "/me/collections/4/movies/dune",
+			mockBehavior:        func(s *mock_service.MockCollections) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid movieId parameter"}`,
+		},
+		{
+			name:       "Not In Collection",
+			requestURL: "/me/collections/4/movies/7",
+			mockBehavior: func(s *mock_service.MockCollections) {
+				s.EXPECT().RemoveCollectionMovie(4, 2, 7).Return(filmoteka.ErrCollectionMovieNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"type":"about:blank","title":"Not Found","status":404,"detail":"movie is not in the collection"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			collections := mock_service.NewMockCollections(c)
+			testCase.mockBehavior(collections)
+
+			services := &service.Service{Collections: collections}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("DELETE /me/collections/{id}/movies/{movieId}", handler.handleRemoveCollectionMovie)
+
+			req := httptest.NewRequest("DELETE", testCase.requestURL, nil)
+			req = req.WithContext(context.WithValue(req.Context(), userCtx, 2))
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
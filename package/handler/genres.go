@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	filmoteka "vk_restAPI"
+	logger "vk_restAPI/logs"
+)
+
+type GenreRequest struct {
+	Name string `json:"name" maxLength:"50"`
+}
+
+type getGenresResponse struct {
+	Data []filmoteka.Genre `json:"data"`
+}
+
+// @Summary Create Genre
+// @Security ApiKeyAuth
+// @Tags genres
+// @Description Create a new genre
+// @Accept json
+// @Produce json
+// @Param input body GenreRequest true "Genre information"
+// @Success 200 {string} string "id"
+// @Failure 400 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/genres [post]
+func (h *Handler) handleCreateGenre(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Create Genre request")
+
+	var input filmoteka.Genre
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logger.Log.Error("Failed to decode request body: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id, err := h.service.Genres.CreateGenre(input)
+	if err != nil {
+		logger.Log.Error("Failed to create genre: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := map[string]interface{}{
+		"id": id,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+// @Summary Get All Genres
+// @Security ApiKeyAuth
+// @Tags genres
+// @Description Get every genre in alphabetical order. Browse the movies of a genre with /api/v1/movies?genre=
+// @Accept json
+// @Produce json
+// @Success 200 {object} getGenresResponse
+// @Failure 500 {object} Problem
+// @Router /api/v1/genres [get]
+func (h *Handler) handleGetAllGenres(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Get All Genres")
+
+	genres, err := h.service.Genres.GetGenres()
+	if err != nil {
+		logger.Log.Error("Failed to Get All Genres: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := getGenresResponse{Data: orEmpty(genres)}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+// @Summary Get Genre By ID
+// @Security ApiKeyAuth
+// @Tags genres
+// @Description Get Genre by ID
+// @Accept json
+// @Produce json
+// @Param id path int true "Genre ID"
+// @Success 200 {object} filmoteka.Genre
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/genres/{id} [get]
+func (h *Handler) handleGetGenreById(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Get Genre By ID")
+
+	id, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	genre, err := h.service.Genres.GetGenreById(id)
+	if err != nil {
+		logger.Log.Error("Failed to Get Genre By ID: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := genre
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+// @Summary Rename Genre
+// @Security ApiKeyAuth
+// @Tags genres
+// @Description Rename a genre. Its movies keep it under the new name.
+// @Accept json
+// @Produce json
+// @Param id path int true "Genre ID"
+// @Param input body GenreRequest true "Genre information"
+// @Success 200 {object} StatusResponse
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/genres/{id} [put]
+func (h *Handler) handleUpdateGenre(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Update Genre")
+
+	id, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var input filmoteka.Genre
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logger.Log.Error("Failed to decode request body: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.service.Genres.UpdateGenre(id, input); err != nil {
+		logger.Log.Error("Failed to update genre: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := StatusResponse{Status: "ok"}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+// @Summary Delete Genre by Id
+// @Security ApiKeyAuth
+// @Tags genres
+// @Description Delete a genre and remove it from every movie.
+// @Accept json
+// @Produce json
+// @Param id path int true "Genre ID"
+// @Success 200 {object} StatusResponse
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/genres/{id} [delete]
+func (h *Handler) handleDeleteGenre(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Delete Genre")
+
+	id, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.service.Genres.DeleteGenre(id); err != nil {
+		logger.Log.Error("Failed to delete genre: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := StatusResponse{Status: "ok"}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
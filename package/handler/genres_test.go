@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	filmoteka "vk_restAPI"
+	"vk_restAPI/package/service"
+	mock_service "vk_restAPI/package/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_handleCreateGenre(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockGenres)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"name":"вестерн"}`,
+			mockBehavior: func(s *mock_service.MockGenres) {
+				s.EXPECT().CreateGenre(filmoteka.Genre{Name: "вестерн"}).Return(9, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":9}`,
+		},
+		{
+			name:      "Name Taken",
+			inputBody: `{"name":"драма"}`,
+			mockBehavior: func(s *mock_service.MockGenres) {
+				s.EXPECT().CreateGenre(filmoteka.Genre{Name: "драма"}).Return(0, filmoteka.ErrGenreExists)
+			},
+			expectedStatusCode:  409,
+			expectedRequestBody: `{"type":"about:blank","title":"Conflict","status":409,"detail":"genre with the same name already exists"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			genreService := mock_service.NewMockGenres(c)
+			testCase.mockBehavior(genreService)
+
+			services := &service.Service{Genres: genreService}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /genres", handler.handleCreateGenre)
+
+			req := httptest.NewRequest("POST", "/genres", bytes.NewBufferString(testCase.inputBody))
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
+
+func TestHandler_handleGetAllGenres(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockGenres)
+
+	testTable := []struct {
+		name                string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mock_service.MockGenres) {
+				s.EXPECT().GetGenres().Return([]filmoteka.Genre{{Id: 1, Name: "биография"}, {Id: 3, Name: "драма"}}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"data":[{"id":1,"name":"биография"},{"id":3,"name":"драма"}]}`,
+		},
+		{
+			name: "Empty",
+			mockBehavior: func(s *mock_service.MockGenres) {
+				s.EXPECT().GetGenres().Return(nil, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"data":[]}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			genreService := mock_service.NewMockGenres(c)
+			testCase.mockBehavior(genreService)
+
+			services := &service.Service{Genres: genreService}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /genres", handler.handleGetAllGenres)
+
+			req := httptest.NewRequest("GET", "/genres", nil)
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
+
+func TestHandler_handleUpdateGenre(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockGenres)
+
+	testTable := []struct {
+		name                string
+		requestURL          string
+		inputBody           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:       "OK",
+			requestURL: "/genres/8",
+			inputBody:  `{"name":"научная фантастика"}`,
+			mockBehavior: func(s *mock_service.MockGenres) {
+				s.EXPECT().UpdateGenre(8, filmoteka.Genre{Name: "научная фантастика"}).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+		{
+			name:       "Not Found",
+			requestURL: "/genres/99",
+			inputBody:  `{"name":"нуар"}`,
+			mockBehavior: func(s *mock_service.MockGenres) {
+				s.EXPECT().UpdateGenre(99, filmoteka.Genre{Name: "нуар"}).Return(filmoteka.ErrGenreNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"type":"about:blank","title":"Not Found","status":404,"detail":"genre not found"}`,
+		},
+		{
+			name:                "Invalid ID",
+			requestURL:          "/genres/drama",
+			inputBody:           `{"name":"нуар"}`,
+			mockBehavior:        func(s *mock_service.MockGenres) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid id parameter"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			genreService := mock_service.NewMockGenres(c)
+			testCase.mockBehavior(genreService)
+
+			services := &service.Service{Genres: genreService}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("PUT /genres/{id}", handler.handleUpdateGenre)
+
+			req := httptest.NewRequest("PUT", testCase.requestURL, bytes.NewBufferString(testCase.inputBody))
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
+
+func TestHandler_handleDeleteGenre(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockGenres)
+
+	testTable := []struct {
+		name                string
+		requestURL          string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:       "OK",
+			requestURL: "/genres/5",
+			mockBehavior: func(s *mock_service.MockGenres) {
+				s.EXPECT().DeleteGenre(5).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+		{
+			name:       "Not Found",
+			requestURL: "/genres/99",
+			mockBehavior: func(s *mock_service.MockGenres) {
+				s.EXPECT().DeleteGenre(99).Return(filmoteka.ErrGenreNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"type":"about:blank","title":"Not Found","status":404,"detail":"genre not found"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			genreService := mock_service.NewMockGenres(c)
+			testCase.mockBehavior(genreService)
+
+			services := &service.Service{Genres: genreService}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("DELETE /genres/{id}", handler.handleDeleteGenre)
+
+			req := httptest.NewRequest("DELETE", testCase.requestURL, nil)
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
@@ -1,7 +1,12 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	filmoteka "vk_restAPI"
 	"vk_restAPI/package/service"
 
 	_ "vk_restAPI/docs"
@@ -17,147 +22,217 @@ func NewHandler(service *service.Service) *Handler {
 	return &Handler{service: service}
 }
 
+// InitRoutes registers every endpoint as a method+pattern route. Requests
+// to a known path with another method get 405 with an Allow header from
+// the mux itself.
 func (h *Handler) InitRoutes() *http.ServeMux {
 	mux := http.NewServeMux()
 
-	mux.Handle("/swagger/", httpSwagger.Handler())
+	mux.Handle("GET /swagger/", httpSwagger.Handler())
+	mux.HandleFunc("GET /.well-known/jwks.json", h.handleJWKS)
 
-	auth := "/auth"
+	auth := newRouteGroup(mux, "/auth")
+	auth.handle(http.MethodPost, "/sign-up", h.handleSignUp)
+	auth.handle(http.MethodPost, "/log-in", h.handleSignIn)
+	auth.handle(http.MethodPost, "/refresh", h.handleRefresh)
+	auth.handle(http.MethodPost, "/logout", h.handleLogout)
 
-	mux.HandleFunc(auth+"/sign-up", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodPost {
-			h.handleSignUp(w, r)
-			return
-		} else {
-			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
-		}
-	})
-
-	mux.HandleFunc(auth+"/log-in", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodPost {
-			h.handleSignIn(w, r)
-			return
-		} else {
-			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
-		}
-	})
+	//Shared collections are open to anyone who has the link
+	shared := newRouteGroup(mux, "/api/v1/shared")
+	shared.handle(http.MethodGet, "/{slug}", h.handleGetSharedCollection)
+	shared.handle(http.MethodGet, "/{slug}/movies", h.handleGetSharedCollectionMovies)
+
+	api := newRouteGroup(mux, "/api", h.userIdentity)
+	v1 := api.group("/v1")
 
-	api := "/api"
+	//Users
+	users := v1.group("/users", h.can(filmoteka.PermUsersManage))
+	users.handle(http.MethodGet, "/{id}/role", h.handleGetUserRole)
+	users.handle(http.MethodPut, "/{id}/role", h.handleSetUserRole)
 
 	//Actors
-	apiActors := api + "/actors"
-
-	//POST for /api/actors/create
-	mux.HandleFunc(apiActors+"/create", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodPost {
-			h.userIdentity(h.handleCreateActor)(w, r)
-			return
-		} else {
-			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
-		}
-	})
-
-	//GET for /api/actors
-	mux.HandleFunc(apiActors, func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodGet {
-			h.userIdentity(h.handleGetAllActors)(w, r)
-			return
-		} else {
-			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
-		}
-	})
-
-	//GET, PUT, DELETE for /api/actors/id
-	mux.HandleFunc(apiActors+"/", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodGet {
-			h.userIdentity(h.handleGetActorById)(w, r)
-		} else if r.Method == http.MethodDelete {
-			h.userIdentity(h.handleDeleteActor)(w, r)
-		} else if r.Method == http.MethodPut {
-			h.userIdentity(h.handleUpdateActor)(w, r)
-			return
-		} else {
-			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
-		}
-	})
+	actors := v1.group("/actors")
+	actors.handle(http.MethodPost, "", h.handleCreateActor, h.can(filmoteka.PermActorsWrite))
+	actors.handle(http.MethodGet, "", h.handleGetAllActors, h.can(filmoteka.PermActorsRead))
+	actors.handle(http.MethodGet, "/{id}", h.handleGetActorById, h.can(filmoteka.PermActorsRead))
+	actors.handle(http.MethodPatch, "/{id}", h.handleUpdateActor, h.can(filmoteka.PermActorsWrite))
+	actors.handle(http.MethodDelete, "/{id}", h.handleDeleteActor, h.can(filmoteka.PermActorsDelete))
+	actors.handle(http.MethodGet, "/{id}/movies", h.handleGetActorMovies, h.can(filmoteka.PermActorsRead), h.can(filmoteka.PermMoviesRead))
 
 	//Movies
-	apiMovies := api + "/movies"
-
-	//POST for /api/movies/create
-	mux.HandleFunc(apiMovies+"/create", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodPost {
-			h.userIdentity(h.handleCreateMovie)(w, r)
-			return
-		} else {
-			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
-		}
-	})
-
-	//GET for /api/movies
-	mux.HandleFunc(apiMovies, func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodGet {
-			h.userIdentity(h.handleGetAllMovies)(w, r)
-			return
-		} else {
-			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
-		}
-	})
-
-	//GET for /api/movies/sort/title
-	mux.HandleFunc(apiMovies+"/sort/title", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodGet {
-			h.userIdentity(h.handleGetAllMoviesSortedByTitle)(w, r)
-			return
-		} else {
-			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
-		}
-	})
-
-	//GET for /api/movies/sort/date
-	mux.HandleFunc(apiMovies+"/sort/date", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodGet {
-			h.userIdentity(h.handleGetAllMoviesSortedByDate)(w, r)
-			return
-		} else {
-			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
-		}
-	})
-
-	//GET, PUT, DELETE for /api/movies/id
-	mux.HandleFunc(apiMovies+"/", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodGet {
-			h.userIdentity(h.handleGetMovieById)(w, r)
-			return
-		} else if r.Method == http.MethodDelete {
-			h.userIdentity(h.handleDeleteMovie)(w, r)
-			return
-		} else if r.Method == http.MethodPut {
-			h.userIdentity(h.handleUpdateMovie)(w, r)
-		} else {
-			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
-		}
-	})
-
-	//GET for /api/movies/searchbytitle
-	mux.HandleFunc(apiMovies+"/searchbytitle", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodPost {
-			h.userIdentity(h.handleSearchMoviesByTitle)(w, r)
-			return
-		} else {
-			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
-		}
-	})
-
-	//GET for /api/movies/searchbyactor
-	mux.HandleFunc(apiMovies+"/searchbyactor", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodPost {
-			h.userIdentity(h.handleSearchMoviesByActorName)(w, r)
-			return
-		} else {
-			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
-		}
-	})
+	movies := v1.group("/movies")
+	movies.handle(http.MethodPost, "", h.handleCreateMovie, h.can(filmoteka.PermMoviesWrite))
+	movies.handle(http.MethodGet, "", h.handleGetAllMovies, h.can(filmoteka.PermMoviesRead))
+	movies.handle(http.MethodGet, "/search", h.handleSearchMovies, h.can(filmoteka.PermMoviesRead))
+	movies.handle(http.MethodGet, "/{id}", h.handleGetMovieById, h.can(filmoteka.PermMoviesRead))
+	movies.handle(http.MethodPatch, "/{id}", h.handleUpdateMovie, h.can(filmoteka.PermMoviesWrite))
+	movies.handle(http.MethodDelete, "/{id}", h.handleDeleteMovie, h.can(filmoteka.PermMoviesDelete))
+	movies.handle(http.MethodPost, "/{id}/reviews", h.handleCreateReview, h.can(filmoteka.PermReviewsWrite))
+	movies.handle(http.MethodGet, "/{id}/reviews", h.handleGetMovieReviews, h.can(filmoteka.PermMoviesRead))
+
+	//Genres
+	genres := v1.group("/genres")
+	genres.handle(http.MethodPost, "", h.handleCreateGenre, h.can(filmoteka.PermMoviesWrite))
+	genres.handle(http.MethodGet, "", h.handleGetAllGenres, h.can(filmoteka.PermMoviesRead))
+	genres.handle(http.MethodGet, "/{id}", h.handleGetGenreById, h.can(filmoteka.PermMoviesRead))
+	genres.handle(http.MethodPut, "/{id}", h.handleUpdateGenre, h.can(filmoteka.PermMoviesWrite))
+	genres.handle(http.MethodDelete, "/{id}", h.handleDeleteGenre, h.can(filmoteka.PermMoviesDelete))
+
+	//People
+	people := v1.group("/people")
+	people.handle(http.MethodPost, "", h.handleCreatePerson, h.can(filmoteka.PermActorsWrite))
+	people.handle(http.MethodGet, "", h.handleGetAllPeople, h.can(filmoteka.PermActorsRead))
+	people.handle(http.MethodGet, "/{id}", h.handleGetPersonById, h.can(filmoteka.PermActorsRead))
+	people.handle(http.MethodPatch, "/{id}", h.handleUpdatePerson, h.can(filmoteka.PermActorsWrite))
+	people.handle(http.MethodDelete, "/{id}", h.handleDeletePerson, h.can(filmoteka.PermActorsDelete))
+
+	//Reviews
+	reviews := v1.group("/reviews", h.can(filmoteka.PermReviewsModerate))
+	reviews.handle(http.MethodGet, "", h.handleGetReviews)
+	reviews.handle(http.MethodPut, "/{id}/status", h.handleSetReviewStatus)
+	reviews.handle(http.MethodDelete, "/{id}", h.handleDeleteReview)
+
+	//The caller's own data
+	me := v1.group("/me")
+	me.handle(http.MethodGet, "/reviews", h.handleGetMyReviews, h.can(filmoteka.PermReviewsWrite))
+	me.handle(http.MethodPatch, "/reviews/{id}", h.handleUpdateMyReview, h.can(filmoteka.PermReviewsWrite))
+	me.handle(http.MethodDelete, "/reviews/{id}", h.handleDeleteMyReview, h.can(filmoteka.PermReviewsWrite))
+	me.handle(http.MethodPost, "/collections", h.handleCreateCollection, h.can(filmoteka.PermCollectionsWrite))
+	me.handle(http.MethodGet, "/collections", h.handleGetMyCollections, h.can(filmoteka.PermCollectionsWrite))
+	me.handle(http.MethodGet, "/collections/{id}", h.handleGetMyCollection, h.can(filmoteka.PermCollectionsWrite))
+	me.handle(http.MethodPatch, "/collections/{id}", h.handleUpdateCollection, h.can(filmoteka.PermCollectionsWrite))
+	me.handle(http.MethodDelete, "/collections/{id}", h.handleDeleteCollection, h.can(filmoteka.PermCollectionsWrite))
+	me.handle(http.MethodGet, "/collections/{id}/movies", h.handleGetMyCollectionMovies, h.can(filmoteka.PermCollectionsWrite), h.can(filmoteka.PermMoviesRead))
+	me.handle(http.MethodPost, "/collections/{id}/movies", h.handleAddCollectionMovie, h.can(filmoteka.PermCollectionsWrite))
+	me.handle(http.MethodPut, "/collections/{id}/movies", h.handleReorderCollection, h.can(filmoteka.PermCollectionsWrite))
+	me.handle(http.MethodDelete, "/collections/{id}/movies/{movieId}", h.handleRemoveCollectionMovie, h.can(filmoteka.PermCollectionsWrite))
+	me.handle(http.MethodGet, "/watched", h.handleGetWatched, h.can(filmoteka.PermHistoryWrite), h.can(filmoteka.PermMoviesRead))
+	me.handle(http.MethodGet, "/watched/stats", h.handleGetWatchedStats, h.can(filmoteka.PermHistoryWrite))
+	me.handle(http.MethodPut, "/watched/{id}", h.handleMarkWatched, h.can(filmoteka.PermHistoryWrite))
+	me.handle(http.MethodDelete, "/watched/{id}", h.handleUnmarkWatched, h.can(filmoteka.PermHistoryWrite))
+
+	//Search
+	search := v1.group("/search", h.can(filmoteka.PermActorsRead), h.can(filmoteka.PermMoviesRead))
+	search.handle(http.MethodGet, "", h.handleFuzzySearch)
+	search.handle(http.MethodGet, "/autocomplete", h.handleAutocomplete)
+
+	h.initLegacyRoutes(api)
 
 	return mux
 }
+
+// Unversioned /api routes predate /api/v1. They keep working until
+// legacySunset, but every response carries Deprecation, Sunset and a Link
+// to the route that replaces it.
+var (
+	legacyDeprecation = time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+	legacySunset      = time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func (h *Handler) initLegacyRoutes(api *routeGroup) {
+	//Users
+	api.alias(http.MethodGet, "/users/{id}/role", "/api/v1/users/{id}/role", h.handleGetUserRole, h.can(filmoteka.PermUsersManage))
+	api.alias(http.MethodPut, "/users/{id}/role", "/api/v1/users/{id}/role", h.handleSetUserRole, h.can(filmoteka.PermUsersManage))
+
+	//Actors
+	api.alias(http.MethodPost, "/actors/create", "/api/v1/actors", h.handleCreateActor, h.can(filmoteka.PermActorsWrite))
+	api.alias(http.MethodGet, "/actors", "/api/v1/actors", h.handleGetAllActors, h.can(filmoteka.PermActorsRead))
+	api.alias(http.MethodGet, "/actors/{id}", "/api/v1/actors/{id}", h.handleGetActorById, h.can(filmoteka.PermActorsRead))
+	api.alias(http.MethodPut, "/actors/{id}", "/api/v1/actors/{id}", h.handleUpdateActor, h.can(filmoteka.PermActorsWrite))
+	api.alias(http.MethodDelete, "/actors/{id}", "/api/v1/actors/{id}", h.handleDeleteActor, h.can(filmoteka.PermActorsDelete))
+
+	//Movies
+	api.alias(http.MethodPost, "/movies/create", "/api/v1/movies", h.handleCreateMovie, h.can(filmoteka.PermMoviesWrite))
+	api.alias(http.MethodGet, "/movies", "/api/v1/movies", h.handleGetAllMovies, h.can(filmoteka.PermMoviesRead))
+	api.alias(http.MethodGet, "/movies/sort/title", "/api/v1/movies?sort=title", h.handleGetAllMoviesSortedByTitle, h.can(filmoteka.PermMoviesRead))
+	api.alias(http.MethodGet, "/movies/sort/date", "/api/v1/movies?sort=release_date", h.handleGetAllMoviesSortedByDate, h.can(filmoteka.PermMoviesRead))
+	api.alias(http.MethodPost, "/movies/searchbytitle", "/api/v1/movies?q=", h.handleSearchMoviesByTitle, h.can(filmoteka.PermMoviesRead))
+	api.alias(http.MethodPost, "/movies/searchbyactor", "/api/v1/movies?actor=", h.handleSearchMoviesByActorName, h.can(filmoteka.PermMoviesRead))
+	api.alias(http.MethodGet, "/movies/{id}", "/api/v1/movies/{id}", h.handleGetMovieById, h.can(filmoteka.PermMoviesRead))
+	api.alias(http.MethodPut, "/movies/{id}", "/api/v1/movies/{id}", h.handleUpdateMovie, h.can(filmoteka.PermMoviesWrite))
+	api.alias(http.MethodDelete, "/movies/{id}", "/api/v1/movies/{id}", h.handleDeleteMovie, h.can(filmoteka.PermMoviesDelete))
+}
+
+type middleware func(http.HandlerFunc) http.HandlerFunc
+
+// routeGroup registers routes under a common prefix, wrapping each of them
+// in the middleware of the group and of its parents.
+type routeGroup struct {
+	mux        *http.ServeMux
+	prefix     string
+	middleware []middleware
+}
+
+func newRouteGroup(mux *http.ServeMux, prefix string, mw ...middleware) *routeGroup {
+	return &routeGroup{mux: mux, prefix: prefix, middleware: mw}
+}
+
+func (g *routeGroup) group(prefix string, mw ...middleware) *routeGroup {
+	chain := append(append([]middleware{}, g.middleware...), mw...)
+	return &routeGroup{mux: g.mux, prefix: g.prefix + prefix, middleware: chain}
+}
+
+// handle registers method+path. Group middleware runs first, in the order it
+// was added, then the route's own middleware.
+func (g *routeGroup) handle(method, path string, next http.HandlerFunc, mw ...middleware) {
+	chain := append(append([]middleware{}, g.middleware...), mw...)
+	for i := len(chain) - 1; i >= 0; i-- {
+		next = chain[i](next)
+	}
+
+	g.mux.HandleFunc(method+" "+g.prefix+path, next)
+}
+
+// alias registers a deprecated route. The deprecation headers are set
+// before any group middleware runs, so they are sent on auth errors too.
+func (g *routeGroup) alias(method, path, successor string, next http.HandlerFunc, mw ...middleware) {
+	chain := append([]middleware{deprecated(successor)}, g.middleware...)
+	legacy := &routeGroup{mux: g.mux, prefix: g.prefix, middleware: chain}
+	legacy.handle(method, path, next, mw...)
+}
+
+// deprecated marks the response as coming from a route scheduled for
+// removal. A {id} in successor is filled in from the request path.
+func deprecated(successor string) middleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			link := successor
+			if id := r.PathValue("id"); id != "" {
+				link = strings.Replace(link, "{id}", id, 1)
+			}
+
+			w.Header().Set("Deprecation", fmt.Sprintf("@%d", legacyDeprecation.Unix()))
+			w.Header().Set("Sunset", legacySunset.Format(http.TimeFormat))
+			w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", link))
+
+			next(w, r)
+		}
+	}
+}
+
+// can is requirePermission in middleware form, for route registration.
+func (h *Handler) can(permission string) middleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return h.requirePermission(permission, next)
+	}
+}
+
+// pathID reads the {id} path parameter.
+func pathID(r *http.Request) (int, error) {
+	return pathInt(r, "id")
+}
+
+// pathInt reads an integer path parameter.
+func pathInt(r *http.Request, name string) (int, error) {
+	value := r.PathValue(name)
+	if value == "" {
+		return 0, fmt.Errorf("missing %s parameter", name)
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s parameter", name)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	filmoteka "vk_restAPI"
+	"vk_restAPI/package/service"
+	mock_service "vk_restAPI/package/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_InitRoutes(t *testing.T) {
+	type mockBehavior func(a *mock_service.MockAuthorization, m *mock_service.MockMoviesWithActors)
+
+	testTable := []struct {
+		name               string
+		method             string
+		requestURL         string
+		inputBody          string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedAllow      []string
+		expectedHeaders    map[string]string
+	}{
+		{
+			name:               "Method Not Allowed",
+			method:             "GET",
+			requestURL:         "/auth/sign-up",
+			mockBehavior:       func(a *mock_service.MockAuthorization, m *mock_service.MockMoviesWithActors) {},
+			expectedStatusCode: 405,
+			expectedAllow:      []string{"POST"},
+		},
+		{
+			name:               "Method Not Allowed With Path Parameter",
+			method:             "PATCH",
+			requestURL:         "/api/movies/1",
+			mockBehavior:       func(a *mock_service.MockAuthorization, m *mock_service.MockMoviesWithActors) {},
+			expectedStatusCode: 405,
+			expectedAllow:      []string{"DELETE", "GET", "HEAD", "PUT"},
+		},
+		{
+			name:       "Static Segment Wins Over ID",
+			method:     "POST",
+			requestURL: "/api/movies/searchbytitle",
+			inputBody:  `{"fragment":"Du"}`,
+			mockBehavior: func(a *mock_service.MockAuthorization, m *mock_service.MockMoviesWithActors) {
+				a.EXPECT().ParseToken("token").Return(filmoteka.Identity{UserId: 1, Role: filmoteka.RoleViewer}, nil)
+				m.EXPECT().SearchMoviesByTitle("Du").Return([]filmoteka.MoviesWithActors{{Id: 1}}, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:       "Search Is Not An ID",
+			method:     "GET",
+			requestURL: "/api/v1/movies/search?q=dune",
+			mockBehavior: func(a *mock_service.MockAuthorization, m *mock_service.MockMoviesWithActors) {
+				a.EXPECT().ParseToken("token").Return(filmoteka.Identity{UserId: 1, Role: filmoteka.RoleViewer}, nil)
+				m.EXPECT().SearchMovies(filmoteka.MovieSearchParams{Query: "dune", Limit: filmoteka.DefaultListLimit}).Return(filmoteka.MovieSearchList{}, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:       "Path Parameter",
+			method:     "GET",
+			requestURL: "/api/v1/movies/7",
+			mockBehavior: func(a *mock_service.MockAuthorization, m *mock_service.MockMoviesWithActors) {
+				a.EXPECT().ParseToken("token").Return(filmoteka.Identity{UserId: 1, Role: filmoteka.RoleViewer}, nil)
+				m.EXPECT().GetMovieById(7).Return(filmoteka.MoviesWithActors{Id: 7, Version: 3}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedHeaders:    map[string]string{"Deprecation": "", "Sunset": "", "Link": "", "ETag": `"3"`},
+		},
+		{
+			name:       "Group Middleware",
+			method:     "DELETE",
+			requestURL: "/api/v1/movies/7",
+			mockBehavior: func(a *mock_service.MockAuthorization, m *mock_service.MockMoviesWithActors) {
+				a.EXPECT().ParseToken("token").Return(filmoteka.Identity{UserId: 1, Role: filmoteka.RoleEditor}, nil)
+			},
+			expectedStatusCode: 403,
+		},
+		{
+			name:       "Search Query",
+			method:     "GET",
+			requestURL: "/api/v1/movies?q=dune&actor=%20chalamet%20",
+			mockBehavior: func(a *mock_service.MockAuthorization, m *mock_service.MockMoviesWithActors) {
+				a.EXPECT().ParseToken("token").Return(filmoteka.Identity{UserId: 1, Role: filmoteka.RoleViewer}, nil)
+				m.EXPECT().GetMovies(filmoteka.MovieListParams{
+					PageParams: filmoteka.PageParams{Sort: filmoteka.SortByRating, Order: filmoteka.OrderDesc, Limit: filmoteka.DefaultListLimit},
+					Title:      "dune",
+					Actor:      "chalamet",
+				}).Return(filmoteka.MoviesList{Movies: []filmoteka.MoviesWithActors{{Id: 1}}, Total: 1}, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:       "Actor Movies",
+			method:     "GET",
+			requestURL: "/api/v1/actors/3/movies",
+			mockBehavior: func(a *mock_service.MockAuthorization, m *mock_service.MockMoviesWithActors) {
+				a.EXPECT().ParseToken("token").Return(filmoteka.Identity{UserId: 1, Role: filmoteka.RoleViewer}, nil)
+				m.EXPECT().GetMovies(filmoteka.MovieListParams{
+					PageParams: filmoteka.PageParams{Sort: filmoteka.SortByReleaseDate, Order: filmoteka.OrderAsc, Limit: filmoteka.DefaultListLimit},
+					ActorId:    3,
+				}).Return(filmoteka.MoviesList{Movies: []filmoteka.MoviesWithActors{{Id: 1}}, Total: 1}, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:       "Legacy Alias",
+			method:     "GET",
+			requestURL: "/api/movies/7",
+			mockBehavior: func(a *mock_service.MockAuthorization, m *mock_service.MockMoviesWithActors) {
+				a.EXPECT().ParseToken("token").Return(filmoteka.Identity{UserId: 1, Role: filmoteka.RoleViewer}, nil)
+				m.EXPECT().GetMovieById(7).Return(filmoteka.MoviesWithActors{Id: 7}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedHeaders: map[string]string{
+				"Deprecation": "@1792281600",
+				"Sunset":      "Thu, 01 Apr 2027 00:00:00 GMT",
+				"Link":        `</api/v1/movies/7>; rel="successor-version"`,
+			},
+		},
+		{
+			name:       "Legacy Alias Denied",
+			method:     "DELETE",
+			requestURL: "/api/movies/7",
+			mockBehavior: func(a *mock_service.MockAuthorization, m *mock_service.MockMoviesWithActors) {
+				a.EXPECT().ParseToken("token").Return(filmoteka.Identity{UserId: 1, Role: filmoteka.RoleEditor}, nil)
+			},
+			expectedStatusCode: 403,
+			expectedHeaders:    map[string]string{"Link": `</api/v1/movies/7>; rel="successor-version"`},
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockAuthorization(c)
+			movieService := mock_service.NewMockMoviesWithActors(c)
+			testCase.mockBehavior(auth, movieService)
+
+			services := &service.Service{Authorization: auth, MoviesWithActors: movieService}
+			handler := NewHandler(services)
+
+			mux := handler.InitRoutes()
+
+			req := httptest.NewRequest(testCase.method, testCase.requestURL, bytes.NewBufferString(testCase.inputBody))
+			req.Header.Set("Authorization", "Bearer token")
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			if testCase.expectedAllow != nil {
+				assert.ElementsMatch(t, testCase.expectedAllow, strings.Split(w.Header().Get("Allow"), ", "))
+			}
+			for key, value := range testCase.expectedHeaders {
+				assert.Equal(t, value, w.Header().Get(key), key)
+			}
+
+		})
+	}
+}
@@ -5,12 +5,14 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+	filmoteka "vk_restAPI"
 	logger "vk_restAPI/logs"
 )
 
 const (
 	authorizationHeader = "Authorization"
 	userCtx             = "userId"
+	roleCtx             = "role"
 )
 
 func (h *Handler) userIdentity(next http.HandlerFunc) http.HandlerFunc {
@@ -42,13 +44,14 @@ func (h *Handler) userIdentity(next http.HandlerFunc) http.HandlerFunc {
 			NewErrorResponse(w, http.StatusUnauthorized, "token is empty")
 		}
 
-		userId, err := h.service.Authorization.ParseToken(token)
+		identity, err := h.service.Authorization.ParseToken(token)
 		if err != nil {
 			NewErrorResponse(w, http.StatusUnauthorized, err.Error())
 			return
 		}
 
-		ctx := context.WithValue(r.Context(), userCtx, userId)
+		ctx := context.WithValue(r.Context(), userCtx, identity.UserId)
+		ctx = context.WithValue(ctx, roleCtx, identity.Role)
 		r = r.WithContext(ctx)
 
 		next(w, r)
@@ -69,20 +72,18 @@ func getUserId(r *http.Request) (int, error) {
 	return idInt, nil
 }
 
-func (h *Handler) checkAdminStatus(w http.ResponseWriter, r *http.Request) error {
-	userId, err := getUserId(r)
-	if err != nil {
-		return err
-	}
+// requirePermission lets the request through only if the role carried in
+// the access token grants permission. It must run after userIdentity.
+func (h *Handler) requirePermission(permission string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		role, _ := r.Context().Value(roleCtx).(string)
 
-	user, err := h.service.GetUserStatus(userId)
-	if err != nil {
-		return err
-	}
+		if !filmoteka.HasPermission(role, permission) {
+			logger.Log.Errorf("role %q lacks permission %s", role, permission)
+			NewErrorResponse(w, http.StatusForbidden, "permission denied: "+permission)
+			return
+		}
 
-	if !user {
-		return errors.New("user is not admin")
+		next(w, r)
 	}
-
-	return nil
 }
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	filmoteka "vk_restAPI"
 	"vk_restAPI/package/service"
 	mock_service "vk_restAPI/package/service/mocks"
 
@@ -32,7 +33,7 @@ func TestHandler_userIdentity(t *testing.T) {
 			headerValue: "Bearer token",
 			token:       "token",
 			mockBehaivior: func(s *mock_service.MockAuthorization, token string) {
-				s.EXPECT().ParseToken(token).Return(1, nil)
+				s.EXPECT().ParseToken(token).Return(filmoteka.Identity{UserId: 1, Role: filmoteka.RoleViewer}, nil)
 			},
 			expectedStatusCode:    200,
 			exptextedResponseBody: "1",
@@ -43,7 +44,7 @@ func TestHandler_userIdentity(t *testing.T) {
 			token:                 "token",
 			mockBehaivior:         func(s *mock_service.MockAuthorization, token string) {},
 			expectedStatusCode:    401,
-			exptextedResponseBody: `{"error":"empty auth header"}`,
+			exptextedResponseBody: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"empty auth header"}`,
 		},
 		{
 			name:                  "Invailed Bearer or Empty Token",
@@ -51,7 +52,7 @@ func TestHandler_userIdentity(t *testing.T) {
 			token:                 "Bearrtoken",
 			mockBehaivior:         func(s *mock_service.MockAuthorization, token string) {},
 			expectedStatusCode:    401,
-			exptextedResponseBody: `{"error":"empty auth header"}`,
+			exptextedResponseBody: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"empty auth header"}`,
 		},
 		{
 			name:        "Service Failure",
@@ -59,10 +60,10 @@ func TestHandler_userIdentity(t *testing.T) {
 			headerValue: "Bearer token",
 			token:       "token",
 			mockBehaivior: func(s *mock_service.MockAuthorization, token string) {
-				s.EXPECT().ParseToken(token).Return(1, errors.New("service failure"))
+				s.EXPECT().ParseToken(token).Return(filmoteka.Identity{}, errors.New("service failure"))
 			},
 			expectedStatusCode:    401,
-			exptextedResponseBody: `{"error":"service failure"}`,
+			exptextedResponseBody: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"service failure"}`,
 		},
 	}
 	for _, testCase := range testTable {
@@ -102,3 +103,90 @@ func TestHandler_userIdentity(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_requirePermission(t *testing.T) {
+	testTable := []struct {
+		name                  string
+		role                  string
+		permission            string
+		expectedStatusCode    int
+		exptextedResponseBody string
+	}{
+		{
+			name:                  "Viewer Reads",
+			role:                  filmoteka.RoleViewer,
+			permission:            filmoteka.PermMoviesRead,
+			expectedStatusCode:    200,
+			exptextedResponseBody: "ok",
+		},
+		{
+			name:                  "Viewer Writes",
+			role:                  filmoteka.RoleViewer,
+			permission:            filmoteka.PermMoviesWrite,
+			expectedStatusCode:    403,
+			exptextedResponseBody: `{"type":"about:blank","title":"Forbidden","status":403,"detail":"permission denied: movies:write"}`,
+		},
+		{
+			name:                  "Editor Writes",
+			role:                  filmoteka.RoleEditor,
+			permission:            filmoteka.PermMoviesWrite,
+			expectedStatusCode:    200,
+			exptextedResponseBody: "ok",
+		},
+		{
+			name:                  "Editor Deletes",
+			role:                  filmoteka.RoleEditor,
+			permission:            filmoteka.PermActorsDelete,
+			expectedStatusCode:    403,
+			exptextedResponseBody: `{"type":"about:blank","title":"Forbidden","status":403,"detail":"permission denied: actors:delete"}`,
+		},
+		{
+			name:                  "Admin Deletes",
+			role:                  filmoteka.RoleAdmin,
+			permission:            filmoteka.PermActorsDelete,
+			expectedStatusCode:    200,
+			exptextedResponseBody: "ok",
+		},
+		{
+			name:                  "No Role",
+			permission:            filmoteka.PermMoviesRead,
+			expectedStatusCode:    403,
+			exptextedResponseBody: `{"type":"about:blank","title":"Forbidden","status":403,"detail":"permission denied: movies:read"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockAuthorization(c)
+			auth.EXPECT().ParseToken("token").Return(filmoteka.Identity{UserId: 1, Role: testCase.role}, nil)
+
+			services := &service.Service{Authorization: auth}
+			handler := NewHandler(services)
+
+			//Test server
+			mux := http.NewServeMux()
+			mux.HandleFunc("/", handler.userIdentity(handler.requirePermission(testCase.permission, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("ok"))
+			})))
+
+			//Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("Authorization", "Bearer token")
+
+			//Perform Request
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.exptextedResponseBody)
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
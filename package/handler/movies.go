@@ -3,6 +3,7 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	filmoteka "vk_restAPI"
@@ -10,44 +11,65 @@ import (
 )
 
 type CreateMoviSwaggerRequest struct {
-	Title       string `json:"title" db:"title"`
-	Description string `json:"description" db:"description"`
-	ReleaseDate string `json:"release_date" db:"release_date"`
-	Rating      int    `json:"rating" db:"rating"`
+	Title         string `json:"title" db:"title" maxLength:"150"`
+	OriginalTitle string `json:"original_title" db:"original_title" maxLength:"150"`
+	Description   string `json:"description" db:"description" maxLength:"1000"`
+	ReleaseDate   string `json:"release_date" db:"release_date" format:"date"`
+	Rating        int    `json:"rating" db:"rating" minimum:"0" maximum:"10"`
+	Country       string `json:"country" db:"country" example:"RU"`
+	Runtime       int    `json:"runtime" db:"runtime" minimum:"0" maximum:"1000"`
+	AgeRating     string `json:"age_rating" db:"age_rating" enums:"0+,6+,12+,16+,18+"`
 }
 
+// MovieSwaggerRequest describes the create body. Every actorIDs entry may
+// also be a bare actor ID.
 type MovieSwaggerRequest struct {
 	Movie    CreateMoviSwaggerRequest `json:"movie"`
-	ActorIDs []int                    `json:"actorIDs"`
+	ActorIDs []CastEntrySwagger       `json:"actorIDs"`
+	Crew     []CrewEntrySwagger       `json:"crew"`
+	GenreIDs []int                    `json:"genreIDs"`
+}
+
+type CastEntrySwagger struct {
+	ActorId       int    `json:"actor_id"`
+	CharacterName string `json:"character_name" maxLength:"150"`
+	BillingOrder  int    `json:"billing_order" minimum:"0"`
+	RoleType      string `json:"role_type" enums:"lead,supporting,cameo,voice" default:"supporting"`
+}
+
+type CrewEntrySwagger struct {
+	PersonId   int    `json:"person_id"`
+	Department string `json:"department" enums:"directing,writing,production,sound,camera,editing"`
+	Job        string `json:"job" maxLength:"100" example:"Режиссёр"`
 }
 
 type movieRequest struct {
-	Movie    filmoteka.Movies `json:"movie"`
-	ActorIDs []int            `json:"actorIDs"`
+	Movie    filmoteka.Movies      `json:"movie"`
+	ActorIDs []filmoteka.CastEntry `json:"actorIDs"`
+	Crew     []filmoteka.CrewEntry `json:"crew"`
+	GenreIDs []int                 `json:"genreIDs"`
 }
 
 // @Summary Create Movie
 // @Security ApiKeyAuth
 // @Tags movies
-// @Description Create a new movie
+// @Description Create a new movie. actorIDs lists the cast, each entry being an actor ID or an object with the character, billing order and role type.
+// @Description Without billing_order actors are billed in the order they are listed.
+// @Description crew credits people from /api/v1/people with a department and a job; a person may have several jobs.
 // @Accept json
 // @Produce json
 // @Param input body MovieSwaggerRequest true "Movie information"
 // @Success 200 {string} string "id"
-// @Failure 400 {object} Err
-// @Failure 404 {object} Err
-// @Failure 500 {object} Err
-// @Router /api/movies/create [post]
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/movies [post]
 func (h *Handler) handleCreateMovie(w http.ResponseWriter, r *http.Request) {
 
 	logger.Log.Info("Handling Create Movie request")
 
-	if err := h.checkAdminStatus(w, r); err != nil {
-		logger.Log.Error("Admin status is not available:", err.Error())
-		NewErrorResponse(w, http.StatusForbidden, "This function is only available to the administrator")
-		return
-	}
-
 	var request movieRequest
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
 		logger.Log.Error("Failed to decaode request body:", err.Error())
@@ -55,32 +77,10 @@ func (h *Handler) handleCreateMovie(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	actors, err := h.service.GetActors()
-	if err != nil {
-		logger.Log.Error("Failed to handle GetActors:", err.Error())
-		NewErrorResponse(w, http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	mapActors := make(map[int]bool)
-	for _, actor := range actors {
-		mapActors[actor.Id] = true
-	}
-
-	validateActorIDs := make([]int, 0)
-	for _, actorID := range request.ActorIDs {
-		if mapActors[actorID] {
-			validateActorIDs = append(validateActorIDs, actorID)
-		} else {
-			logger.Log.Printf("actor ID not found: %v", actorID)
-			continue
-		}
-	}
-
-	id, err := h.service.Movies.CreateMovie(request.Movie, validateActorIDs)
+	id, err := h.service.Movies.CreateMovie(request.Movie, request.ActorIDs, request.Crew, request.GenreIDs)
 	if err != nil {
 		logger.Log.Error("Failed to create movie:", err.Error())
-		NewErrorResponse(w, http.StatusInternalServerError, err.Error())
+		writeError(w, err)
 		return
 	}
 
@@ -96,74 +96,153 @@ func (h *Handler) handleCreateMovie(w http.ResponseWriter, r *http.Request) {
 
 }
 
+type listMeta struct {
+	Total      int    `json:"total"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// pageMeta describes a page of a list paginated by cursor or offset. The
+// repositories ignore the offset once a cursor is given, so it's reported
+// as 0 then rather than echoed back.
+func pageMeta(total int, params filmoteka.PageParams, nextCursor, prevCursor string) *listMeta {
+	meta := &listMeta{Total: total, Limit: params.Limit, Offset: params.Offset, NextCursor: nextCursor, PrevCursor: prevCursor}
+	if params.Cursor != "" {
+		meta.Offset = 0
+	}
+	return meta
+}
+
 type getMoviesResponse struct {
 	Data []filmoteka.MoviesWithActors `json:"data"`
+	Meta *listMeta                    `json:"meta,omitempty"`
+}
+
+type searchMoviesResponse struct {
+	Data []filmoteka.MovieSearchResult `json:"data"`
+	Meta *listMeta                     `json:"meta,omitempty"`
 }
 
 // @Summary Get All Movies
 // @Security ApiKeyAuth
 // @Tags movies
-// @Description Get List of Movies Sorted By Rating
+// @Description Get a page of Movies sorted by rating, title, release date or id.
+// @Description q, actor and director narrow the list down to titles, cast or director names containing the fragment.
+// @Description genre keeps the movies of any of the given genres; country, age_rating, min_runtime and max_runtime filter on the movie's own fields.
+// @Description Pass next_cursor or prev_cursor from a previous page as cursor to scroll without offsets.
 // @Accept json
 // @Produce json
+// @Param q query string false "Fragment of the title"
+// @Param actor query string false "Fragment of an actor's first or last name"
+// @Param director query string false "Fragment of a director's first or last name"
+// @Param genre query []int false "Genre ID, may be repeated" collectionFormat(multi)
+// @Param country query string false "Two letter country code" example(US)
+// @Param age_rating query string false "Age rating" Enums(0+, 6+, 12+, 16+, 18+)
+// @Param min_runtime query int false "Least runtime in minutes"
+// @Param max_runtime query int false "Most runtime in minutes"
+// @Param sort query string false "Sort field" Enums(rating, title, release_date, id) default(rating)
+// @Param order query string false "Sort order" Enums(asc, desc) default(desc)
+// @Param limit query int false "Page size" default(20) maximum(100)
+// @Param offset query int false "Number of movies to skip, ignored with cursor" default(0)
+// @Param cursor query string false "Opaque page cursor"
+// @Param with_state query bool false "Add the caller's state of each movie"
 // @Success 200 {object} getMoviesResponse
-// @Failure 400 {object} Err
-// @Failure 404 {object} Err
-// @Failure 500 {object} Err
-// @Router /api/movies [get]
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/movies [get]
 func (h *Handler) handleGetAllMovies(w http.ResponseWriter, r *http.Request) {
 
 	logger.Log.Info("Handling Get All Movies")
 
-	movies, err := h.service.MoviesWithActors.GetMovies()
-	if err != nil {
-		logger.Log.Error("Failed to Get All Movies: ", err.Error())
-		NewErrorResponse(w, http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	if len(movies) == 0 {
-		NewErrorResponse(w, http.StatusOK, "The list of movies is empty")
-		return
-	}
-
-	response := getMoviesResponse{Data: movies}
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		logger.Log.Error("Failed to encode response", err.Error())
-	}
+	h.getMovies(w, r, filmoteka.SortByRating, filmoteka.OrderDesc)
 }
 
 // @Summary Get All Movies Sorted By Title
 // @Security ApiKeyAuth
 // @Tags movies
-// @Description Get List of Movies Sorted By Title
+// @Description Get List of Movies Sorted By Title. Deprecated: use /api/v1/movies?sort=title
 // @Accept json
 // @Produce json
+// @Param limit query int false "Page size" default(20) maximum(100)
+// @Param offset query int false "Number of movies to skip" default(0)
+// @Param cursor query string false "Opaque page cursor"
 // @Success 200 {object} getMoviesResponse
-// @Failure 400 {object} Err
-// @Failure 404 {object} Err
-// @Failure 500 {object} Err
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Deprecated
 // @Router /api/movies/sort/title [get]
 func (h *Handler) handleGetAllMoviesSortedByTitle(w http.ResponseWriter, r *http.Request) {
 
 	logger.Log.Info("Handling Get All Movies Sorted by Title")
 
-	movies, err := h.service.MoviesWithActors.GetMoviesSortedByTitle()
+	h.getMovies(w, r, filmoteka.SortByTitle, filmoteka.OrderAsc)
+}
+
+// @Summary Get All Movies Sorted By Date
+// @Security ApiKeyAuth
+// @Tags movies
+// @Description Get List of Movies Sorted By Date. Deprecated: use /api/v1/movies?sort=release_date
+// @Accept json
+// @Produce json
+// @Param limit query int false "Page size" default(20) maximum(100)
+// @Param offset query int false "Number of movies to skip" default(0)
+// @Param cursor query string false "Opaque page cursor"
+// @Success 200 {object} getMoviesResponse
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Deprecated
+// @Router /api/movies/sort/date [get]
+func (h *Handler) handleGetAllMoviesSortedByDate(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Get All Movies Sorted by Date")
+
+	h.getMovies(w, r, filmoteka.SortByReleaseDate, filmoteka.OrderAsc)
+}
+
+// getMovies serves a page of movies. Query parameters override the given
+// default sort field and order.
+func (h *Handler) getMovies(w http.ResponseWriter, r *http.Request, sort, order string) {
+	params, err := parseMovieListParams(r, sort, order)
 	if err != nil {
-		logger.Log.Error("Failed to Get All Movies Sorted by Title: ", err.Error())
-		NewErrorResponse(w, http.StatusInternalServerError, err.Error())
+		logger.Log.Error("Invalid list parameters: ", err.Error())
+		writeError(w, err)
 		return
 	}
 
-	if len(movies) == 0 {
-		NewErrorResponse(w, http.StatusOK, "The list of movies is empty")
+	h.listMovies(w, r, params)
+}
+
+func (h *Handler) listMovies(w http.ResponseWriter, r *http.Request, params filmoteka.MovieListParams) {
+	list, err := h.service.MoviesWithActors.GetMovies(params)
+	if err != nil {
+		logger.Log.Error("Failed to Get All Movies: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	movies := make([]*filmoteka.MoviesWithActors, len(list.Movies))
+	for i := range list.Movies {
+		movies[i] = &list.Movies[i]
+	}
+
+	if err := h.annotateMovies(r, movies); err != nil {
+		logger.Log.Error("Failed to annotate movies: ", err.Error())
+		writeError(w, err)
 		return
 	}
 
-	response := getMoviesResponse{Data: movies}
+	response := getMoviesResponse{
+		Data: orEmpty(list.Movies),
+		Meta: pageMeta(list.Total, params.PageParams, list.NextCursor, list.PrevCursor),
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
@@ -172,34 +251,63 @@ func (h *Handler) handleGetAllMoviesSortedByTitle(w http.ResponseWriter, r *http
 	}
 }
 
-// @Summary Get All Movies Sorted By Date
+// @Summary Search Movies
 // @Security ApiKeyAuth
 // @Tags movies
-// @Description Get List of Movies Sorted By Date
+// @Description Full-text search over movie titles and descriptions, which understands Russian word forms.
+// @Description q takes web search syntax: "quoted phrases", OR and -excluded words.
+// @Description actor and director keep only movies with an actor or a director whose name contains the fragment.
+// @Description Results are ordered by rank; headline holds the matching fragments with the found words in <mark> tags.
 // @Accept json
 // @Produce json
-// @Success 200 {object} getMoviesResponse
-// @Failure 400 {object} Err
-// @Failure 404 {object} Err
-// @Failure 500 {object} Err
-// @Router /api/movies/sort/date [get]
-func (h *Handler) handleGetAllMoviesSortedByDate(w http.ResponseWriter, r *http.Request) {
-
-	logger.Log.Info("Handling Get All Movies Sorted by Date")
+// @Param q query string true "Search query"
+// @Param actor query string false "Fragment of an actor's first or last name"
+// @Param director query string false "Fragment of a director's first or last name"
+// @Param limit query int false "Page size" default(20) maximum(100)
+// @Param offset query int false "Number of results to skip" default(0)
+// @Param with_state query bool false "Add the caller's state of each movie"
+// @Success 200 {object} searchMoviesResponse
+// @Failure 400 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/movies/search [get]
+func (h *Handler) handleSearchMovies(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Search Movies")
+
+	params, err := parseMovieSearchParams(r)
+	if err != nil {
+		logger.Log.Error("Invalid search parameters: ", err.Error())
+		writeError(w, err)
+		return
+	}
 
-	movies, err := h.service.MoviesWithActors.GetMoviesSortedByDate()
+	list, err := h.service.SearchMovies(params)
 	if err != nil {
-		logger.Log.Error("Failed to Get All Movies Sorted by Date: ", err.Error())
-		NewErrorResponse(w, http.StatusInternalServerError, err.Error())
+		logger.Log.Error("Failed to search movies: ", err.Error())
+		writeError(w, err)
 		return
 	}
 
-	if len(movies) == 0 {
-		NewErrorResponse(w, http.StatusOK, "The list of movies is empty")
+	movies := make([]*filmoteka.MoviesWithActors, len(list.Movies))
+	for i := range list.Movies {
+		movies[i] = &list.Movies[i].MoviesWithActors
+	}
+
+	if err := h.annotateMovies(r, movies); err != nil {
+		logger.Log.Error("Failed to annotate movies: ", err.Error())
+		writeError(w, err)
 		return
 	}
 
-	response := getMoviesResponse{Data: movies}
+	response := searchMoviesResponse{
+		Data: orEmpty(list.Movies),
+		Meta: &listMeta{
+			Total:  list.Total,
+			Limit:  params.Limit,
+			Offset: params.Offset,
+		},
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
@@ -208,6 +316,141 @@ func (h *Handler) handleGetAllMoviesSortedByDate(w http.ResponseWriter, r *http.
 	}
 }
 
+func parseMovieSearchParams(r *http.Request) (filmoteka.MovieSearchParams, error) {
+	query := r.URL.Query()
+
+	limit, err := queryInt(query, "limit", filmoteka.DefaultListLimit)
+	if err != nil {
+		return filmoteka.MovieSearchParams{}, err
+	}
+
+	offset, err := queryInt(query, "offset", 0)
+	if err != nil {
+		return filmoteka.MovieSearchParams{}, err
+	}
+
+	params := filmoteka.MovieSearchParams{
+		Query:    strings.TrimSpace(query.Get("q")),
+		Actor:    strings.TrimSpace(query.Get("actor")),
+		Director: strings.TrimSpace(query.Get("director")),
+		Limit:    limit,
+		Offset:   offset,
+	}
+	return params, params.Validate()
+}
+
+func parseMovieListParams(r *http.Request, sort, order string) (filmoteka.MovieListParams, error) {
+	page, err := parsePageParams(r.URL.Query(), sort, order)
+	if err != nil {
+		return filmoteka.MovieListParams{}, err
+	}
+
+	query := r.URL.Query()
+	params := filmoteka.MovieListParams{
+		PageParams: page,
+		Title:      strings.TrimSpace(query.Get("q")),
+		Actor:      strings.TrimSpace(query.Get("actor")),
+		Director:   strings.TrimSpace(query.Get("director")),
+		Country:    query.Get("country"),
+		AgeRating:  query.Get("age_rating"),
+	}
+
+	if params.GenreIds, err = queryInts(query, "genre"); err != nil {
+		return params, err
+	}
+
+	if params.MinRuntime, err = queryInt(query, "min_runtime", 0); err != nil {
+		return params, err
+	}
+
+	if params.MaxRuntime, err = queryInt(query, "max_runtime", 0); err != nil {
+		return params, err
+	}
+
+	return params, params.Validate()
+}
+
+// parsePageParams reads the sort, order, limit, offset and cursor query
+// parameters shared by all paginated lists.
+func parsePageParams(query url.Values, sort, order string) (filmoteka.PageParams, error) {
+	params := filmoteka.PageParams{
+		Sort:   sort,
+		Order:  order,
+		Cursor: query.Get("cursor"),
+	}
+
+	if value := query.Get("sort"); value != "" {
+		params.Sort = value
+	}
+
+	if value := query.Get("order"); value != "" {
+		params.Order = strings.ToLower(value)
+	}
+
+	var err error
+	if params.Limit, err = queryInt(query, "limit", filmoteka.DefaultListLimit); err != nil {
+		return params, err
+	}
+
+	if params.Offset, err = queryInt(query, "offset", 0); err != nil {
+		return params, err
+	}
+
+	return params, nil
+}
+
+func queryInt(query url.Values, key string, defaultValue int) (int, error) {
+	value := query.Get(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, filmoteka.Errorf(errMalformedRequest, "invalid %s parameter", key)
+	}
+	return n, nil
+}
+
+func queryFloat(query url.Values, key string, defaultValue float64) (float64, error) {
+	value := query.Get(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, filmoteka.Errorf(errMalformedRequest, "invalid %s parameter", key)
+	}
+	return f, nil
+}
+
+// queryInts reads a parameter that may be repeated. It is nil when absent.
+func queryInts(query url.Values, key string) ([]int, error) {
+	var values []int
+	for _, value := range query[key] {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, filmoteka.Errorf(errMalformedRequest, "invalid %s parameter", key)
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
+
+func queryBool(query url.Values, key string) (bool, error) {
+	value := query.Get(key)
+	if value == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, filmoteka.Errorf(errMalformedRequest, "invalid %s parameter", key)
+	}
+	return b, nil
+}
+
 // @Summary Get Movie By ID
 // @Security ApiKeyAuth
 // @Tags movies
@@ -216,39 +459,31 @@ func (h *Handler) handleGetAllMoviesSortedByDate(w http.ResponseWriter, r *http.
 // @Produce json
 // @Param id path int true "Movie ID"
 // @Success 200 {object} filmoteka.MoviesWithActors
-// @Failure 400 {object} Err
-// @Failure 404 {object} Err
-// @Failure 500 {object} Err
-// @Router /api/movies/{id} [get]
+// @Header 200 {string} ETag "Version of the movie, for If-Match"
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/movies/{id} [get]
 func (h *Handler) handleGetMovieById(w http.ResponseWriter, r *http.Request) {
 
 	logger.Log.Info("Handling Get Movie By ID")
 
-	path := r.URL.Path
-
-	parts := strings.Split(path, "/")
-	if len(parts) < 4 {
-		logger.Log.Error("Missing ID parameter")
-		NewErrorResponse(w, http.StatusBadRequest, "missing id parameter")
-		return
-	}
-
-	idStr := parts[3]
-	id, err := strconv.Atoi(idStr)
+	id, err := pathID(r)
 	if err != nil {
-		logger.Log.Error("Invailed ID parameter: ", err.Error())
-		NewErrorResponse(w, http.StatusBadRequest, "invalid id parameter")
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	movie, err := h.service.MoviesWithActors.GetMovieById(id)
 	if err != nil {
 		logger.Log.Error("Failed to Get Movie By ID: ", err.Error())
-		NewErrorResponse(w, http.StatusInternalServerError, err.Error())
+		writeError(w, err)
 		return
 	}
 
 	response := movie
+	w.Header().Set("ETag", etag(movie.Version))
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
@@ -260,57 +495,56 @@ func (h *Handler) handleGetMovieById(w http.ResponseWriter, r *http.Request) {
 // @Summary Update Movie
 // @Security ApiKeyAuth
 // @Tags movies
-// @Description Update information about Movie
-// @Accept json
+// @Description Update information about Movie. PATCH takes a JSON merge patch, in which null clears a field that can be empty. Send the ETag of a previous read in If-Match to fail with 412 instead of overwriting someone else's change.
+// @Accept json,application/merge-patch+json
 // @Produce json
 // @Param id path int true "Movie ID"
+// @Param If-Match header string false "ETag the update is conditional on"
 // @Param input body filmoteka.UpdateMovies true "Movie information for update"
 // @Success 200 {object} StatusResponse
-// @Failure 400 {object} Err
-// @Failure 404 {object} Err
-// @Failure 500 {object} Err
-// @Router /api/movies/{id} [put]
+// @Header 200 {string} ETag "New version of the movie"
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 412 {object} Problem
+// @Failure 415 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/movies/{id} [patch]
 func (h *Handler) handleUpdateMovie(w http.ResponseWriter, r *http.Request) {
 
 	logger.Log.Info("Handling Update Movie")
 
-	if err := h.checkAdminStatus(w, r); err != nil {
-		logger.Log.Error("Admin status is not available:", err.Error())
-		NewErrorResponse(w, http.StatusForbidden, "This function is only available to the administrator")
-		return
-	}
-
-	path := r.URL.Path
-
-	parts := strings.Split(path, "/")
-	if len(parts) < 4 {
-		logger.Log.Error("Missing ID parameter")
-		NewErrorResponse(w, http.StatusBadRequest, "missing id parameter")
+	id, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	idStr := parts[3]
-	id, err := strconv.Atoi(idStr)
+	version, err := ifMatchVersion(r)
 	if err != nil {
-		logger.Log.Error("Invailid ID parameter: ", err.Error())
-		NewErrorResponse(w, http.StatusBadRequest, "invalid id parameter")
+		logger.Log.Error("Invalid If-Match header: ", err.Error())
+		writeError(w, err)
 		return
 	}
 
 	var input filmoteka.UpdateMovies
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+	if err := decodeUpdate(r, &input); err != nil {
 		logger.Log.Error("Failed to decode request body: ", err.Error())
-		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		writeError(w, err)
 		return
 	}
 
-	if err := h.service.UpdateMovie(id, input); err != nil {
+	newVersion, err := h.service.UpdateMovie(id, input, version)
+	if err != nil {
 		logger.Log.Error("Failed to update movie: ", err.Error())
-		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		writeError(w, err)
 		return
 	}
 
 	response := StatusResponse{Status: "ok"}
+	w.Header().Set("ETag", etag(newVersion))
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
@@ -327,41 +561,25 @@ func (h *Handler) handleUpdateMovie(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path int true "Movie ID"
 // @Success 200 {object} StatusResponse
-// @Failure 400 {object} Err
-// @Failure 404 {object} Err
-// @Failure 500 {object} Err
-// @Router /api/movies/{id} [delete]
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/movies/{id} [delete]
 func (h *Handler) handleDeleteMovie(w http.ResponseWriter, r *http.Request) {
 
 	logger.Log.Info("Handling Delete Movie")
 
-	if err := h.checkAdminStatus(w, r); err != nil {
-		logger.Log.Error("Admin status is not available:", err.Error())
-		NewErrorResponse(w, http.StatusForbidden, "This function is only available to the administrator")
-		return
-	}
-
-	path := r.URL.Path
-
-	parts := strings.Split(path, "/")
-	if len(parts) < 4 {
-		logger.Log.Error("Missing ID parameter")
-		NewErrorResponse(w, http.StatusBadRequest, "missing id parameter")
-		return
-	}
-
-	idStr := parts[3]
-	id, err := strconv.Atoi(idStr)
+	id, err := pathID(r)
 	if err != nil {
-		logger.Log.Error("Failed to decode request body: ", err.Error())
-		NewErrorResponse(w, http.StatusBadRequest, "invalid id parameter")
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	err = h.service.Movies.DeleteMovie(id)
 	if err != nil {
 		logger.Log.Error("Failed to delete movie: ", err.Error())
-		NewErrorResponse(w, http.StatusInternalServerError, err.Error())
+		writeError(w, err)
 		return
 	}
 
@@ -381,14 +599,15 @@ type Search struct {
 // @Summary Search Movie By Fragment of Title
 // @Security ApiKeyAuth
 // @Tags movies
-// @Description Search Movie By Title
+// @Description Search Movie By Title. Deprecated: use /api/v1/movies?q=
 // @Accept json
 // @Produce json
-// @Param input body search true "Search Movie By Fragment Of Title"
+// @Param input body Search true "Search Movie By Fragment Of Title"
 // @Success 200 {object} getMoviesResponse
-// @Failure 400 {object} Err
-// @Failure 404 {object} Err
-// @Failure 500 {object} Err
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Deprecated
 // @Router /api/movies/searchbytitle [post]
 func (h *Handler) handleSearchMoviesByTitle(w http.ResponseWriter, r *http.Request) {
 
@@ -404,16 +623,11 @@ func (h *Handler) handleSearchMoviesByTitle(w http.ResponseWriter, r *http.Reque
 	movies, err := h.service.SearchMoviesByTitle(string(fragmentTitle.Fragment))
 	if err != nil {
 		logger.Log.Error("Failed to search movie by title: ", err.Error())
-		NewErrorResponse(w, http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	if len(movies) == 0 {
-		NewErrorResponse(w, http.StatusOK, "The list of movies is empty")
+		writeError(w, err)
 		return
 	}
 
-	response := getMoviesResponse{Data: movies}
+	response := getMoviesResponse{Data: orEmpty(movies)}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
@@ -425,14 +639,15 @@ func (h *Handler) handleSearchMoviesByTitle(w http.ResponseWriter, r *http.Reque
 // @Summary Search Movie By Fragment Of Actor Name
 // @Security ApiKeyAuth
 // @Tags movies
-// @Description Search Movie By Fragment Of Actor Name
+// @Description Search Movie By Fragment Of Actor Name. Deprecated: use /api/v1/movies?actor=
 // @Accept json
 // @Produce json
-// @Param input body search true "Search Movie By Fragment Of Actor Name"
+// @Param input body Search true "Search Movie By Fragment Of Actor Name"
 // @Success 200 {object} getMoviesResponse
-// @Failure 400 {object} Err
-// @Failure 404 {object} Err
-// @Failure 500 {object} Err
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Deprecated
 // @Router /api/movies/searchbyactor [post]
 func (h *Handler) handleSearchMoviesByActorName(w http.ResponseWriter, r *http.Request) {
 
@@ -448,16 +663,11 @@ func (h *Handler) handleSearchMoviesByActorName(w http.ResponseWriter, r *http.R
 	movies, err := h.service.SearchMovieByActorName(string(fragmentTitle.Fragment))
 	if err != nil {
 		logger.Log.Error("Failed to search movie by actor name: ", err.Error())
-		NewErrorResponse(w, http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	if len(movies) == 0 {
-		NewErrorResponse(w, http.StatusOK, "The list of movies is empty")
+		writeError(w, err)
 		return
 	}
 
-	response := getMoviesResponse{Data: movies}
+	response := getMoviesResponse{Data: orEmpty(movies)}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
@@ -15,31 +15,104 @@ import (
 )
 
 type MoviesWithActors struct {
-	Id          int    `json:"id" db:"id"`
-	Title       string `json:"title" db:"title"`
-	Description string `json:"description" db:"description"`
-	ReleaseDate string `json:"release_date" db:"release_date"`
-	Rating      int    `json:"rating" db:"rating"`
-	Actors      string `json:"actors" db:"actors"`
+	Id          int            `json:"id" db:"id"`
+	Title       string         `json:"title" db:"title"`
+	Description string         `json:"description" db:"description"`
+	ReleaseDate string         `json:"release_date" db:"release_date"`
+	Rating      int            `json:"rating" db:"rating"`
+	Actors      filmoteka.Cast `json:"actors" db:"actors"`
 }
 
 func TestHandler_handleCreateMovie(t *testing.T) {
-	type mockBehavior func(s *mock_service.MockMoviesWithActors, input filmoteka.MoviesWithActors)
+	type mockBehavior func(s *mock_service.MockMovies, input filmoteka.Movies, cast []filmoteka.CastEntry, crew []filmoteka.CrewEntry, genreIDs []int)
 
 	testTable := []struct {
 		name                string
 		inputBody           string
-		inputMovie          filmoteka.MoviesWithActors
+		inputMovie          filmoteka.Movies
+		inputCast           []filmoteka.CastEntry
+		inputCrew           []filmoteka.CrewEntry
+		inputGenreIDs       []int
 		mockBehavior        mockBehavior
 		expectedStatusCode  int
 		expectedRequestBody string
 	}{
 		{
-			name:                "Only for administrator",
-			inputBody:           `{"title":"Dune 2", "description":"New Film", "release_date":"2024-03-07", "rating":"9","actors":"Test"}`,
-			mockBehavior:        func(s *mock_service.MockMoviesWithActors, input filmoteka.MoviesWithActors) {},
-			expectedStatusCode:  403,
-			expectedRequestBody: `{"error":"This function is only available to the administrator"}`,
+			name:      "OK",
+			inputBody: `{"movie":{"title":"Dune 2", "description":"New Film", "release_date":"2024-03-07", "rating":9}, "actorIDs":[1,2]}`,
+			inputMovie: filmoteka.Movies{
+				Title:       "Dune 2",
+				Description: "New Film",
+				ReleaseDate: "2024-03-07",
+				Rating:      9,
+			},
+			inputCast: []filmoteka.CastEntry{{ActorId: 1}, {ActorId: 2}},
+			mockBehavior: func(s *mock_service.MockMovies, input filmoteka.Movies, cast []filmoteka.CastEntry, crew []filmoteka.CrewEntry, genreIDs []int) {
+				s.EXPECT().CreateMovie(input, cast, crew, genreIDs).Return(1, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":1}`,
+		},
+		{
+			name: "With Metadata",
+			inputBody: `{"movie":{"title":"Дюна: Часть вторая", "original_title":"Dune: Part Two", "release_date":"2024-02-29", "rating":9,
+				"country":"US", "runtime":166, "age_rating":"12+"}, "actorIDs":[1], "genreIDs":[6,8]}`,
+			inputMovie: filmoteka.Movies{
+				Title:         "Дюна: Часть вторая",
+				OriginalTitle: "Dune: Part Two",
+				ReleaseDate:   "2024-02-29",
+				Rating:        9,
+				Country:       "US",
+				Runtime:       166,
+				AgeRating:     "12+",
+			},
+			inputCast:     []filmoteka.CastEntry{{ActorId: 1}},
+			inputGenreIDs: []int{6, 8},
+			mockBehavior: func(s *mock_service.MockMovies, input filmoteka.Movies, cast []filmoteka.CastEntry, crew []filmoteka.CrewEntry, genreIDs []int) {
+				s.EXPECT().CreateMovie(input, cast, crew, genreIDs).Return(1, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":1}`,
+		},
+		{
+			name: "Cast Entries",
+			inputBody: `{"movie":{"title":"Дюна: Часть вторая", "release_date":"2024-02-29", "rating":9},
+				"actorIDs":[{"actor_id":1, "character_name":"Пол Атрейдес", "role_type":"lead"}, {"actor_id":2, "character_name":"Чани", "billing_order":2}, 3]}`,
+			inputMovie: filmoteka.Movies{Title: "Дюна: Часть вторая", ReleaseDate: "2024-02-29", Rating: 9},
+			inputCast: []filmoteka.CastEntry{
+				{ActorId: 1, CharacterName: "Пол Атрейдес", RoleType: filmoteka.CastLead},
+				{ActorId: 2, CharacterName: "Чани", BillingOrder: 2},
+				{ActorId: 3},
+			},
+			mockBehavior: func(s *mock_service.MockMovies, input filmoteka.Movies, cast []filmoteka.CastEntry, crew []filmoteka.CrewEntry, genreIDs []int) {
+				s.EXPECT().CreateMovie(input, cast, crew, genreIDs).Return(1, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":1}`,
+		},
+		{
+			name: "Crew",
+			inputBody: `{"movie":{"title":"Темный рыцарь", "release_date":"2008-07-14", "rating":7}, "actorIDs":[11],
+				"crew":[{"person_id":18, "department":"directing", "job":"Режиссёр"}, {"person_id":18, "department":"writing", "job":"Сценарист"}]}`,
+			inputMovie: filmoteka.Movies{Title: "Темный рыцарь", ReleaseDate: "2008-07-14", Rating: 7},
+			inputCast:  []filmoteka.CastEntry{{ActorId: 11}},
+			inputCrew: []filmoteka.CrewEntry{
+				{PersonId: 18, Department: filmoteka.DepartmentDirecting, Job: "Режиссёр"},
+				{PersonId: 18, Department: filmoteka.DepartmentWriting, Job: "Сценарист"},
+			},
+			mockBehavior: func(s *mock_service.MockMovies, input filmoteka.Movies, cast []filmoteka.CastEntry, crew []filmoteka.CrewEntry, genreIDs []int) {
+				s.EXPECT().CreateMovie(input, cast, crew, genreIDs).Return(1, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":1}`,
+		},
+		{
+			name:      "Invalid Body",
+			inputBody: `{"movie":{"title":"Dune 2", "rating":"9"}}`,
+			mockBehavior: func(s *mock_service.MockMovies, input filmoteka.Movies, cast []filmoteka.CastEntry, crew []filmoteka.CrewEntry, genreIDs []int) {
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"json: cannot unmarshal string into Go struct field movieRequest.movie.rating of type int"}`,
 		},
 	}
 	for _, testCase := range testTable {
@@ -48,10 +121,10 @@ func TestHandler_handleCreateMovie(t *testing.T) {
 			c := gomock.NewController(t)
 			defer c.Finish()
 
-			movieService := mock_service.NewMockMoviesWithActors(c)
-			testCase.mockBehavior(movieService, testCase.inputMovie)
+			movieService := mock_service.NewMockMovies(c)
+			testCase.mockBehavior(movieService, testCase.inputMovie, testCase.inputCast, testCase.inputCrew, testCase.inputGenreIDs)
 
-			services := &service.Service{MoviesWithActors: movieService}
+			services := &service.Service{Movies: movieService}
 			handler := NewHandler(services)
 
 			mux := http.NewServeMux()
@@ -79,37 +152,135 @@ func TestHandler_handleGetAllMovies(t *testing.T) {
 
 	testTable := []struct {
 		name                string
+		requestURL          string
 		mockBehavior        mockBehaivior
 		expectedStatusCode  int
 		expectedRequestBody string
 	}{
 		{
-			name: "OK",
+			name:       "OK",
+			requestURL: "/api/movies",
 			mockBehavior: func(s *mock_service.MockMoviesWithActors) {
-				movies := []filmoteka.MoviesWithActors{
-					{
-						Id:          1,
-						Title:       "Dune 2",
-						Description: "New film",
-						ReleaseDate: "2024-03-07",
-						Rating:      9,
-						Actors:      "Zendeya",
+				list := filmoteka.MoviesList{
+					Movies: []filmoteka.MoviesWithActors{
+						{
+							Id:          1,
+							Title:       "Dune 2",
+							Description: "New film",
+							ReleaseDate: "2024-03-07",
+							Rating:      9,
+							Actors:      filmoteka.Cast{{ActorSummary: filmoteka.ActorSummary{Id: 2, FirstName: "Zendeya"}, CastRole: filmoteka.CastRole{CharacterName: "Chani", BillingOrder: 2, RoleType: filmoteka.CastLead}}},
+						},
 					},
+					Total: 1,
 				}
 
-				s.EXPECT().GetMovies().Return(movies, nil)
+				s.EXPECT().GetMovies(filmoteka.MovieListParams{PageParams: filmoteka.PageParams{Sort: "rating", Order: "desc", Limit: 20}}).Return(list, nil)
 			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `{"data":[{"id":1,"title":"Dune 2","description":"New film","release_date":"2024-03-07","rating":9,"actors":"Zendeya"}]}`,
+			expectedRequestBody: `{"data":[{"id":1,"title":"Dune 2","original_title":"","description":"New film","release_date":"2024-03-07","rating":9,"country":"","runtime":0,"age_rating":"","genres":null,"actors":[{"id":2,"first_name":"Zendeya","last_name":"","character_name":"Chani","billing_order":2,"role_type":"lead"}],"crew":null,"user_rating":{"average":0,"votes":0,"distribution":null}}],"meta":{"total":1,"limit":20,"offset":0}}`,
 		},
 		{
-			name: "Empty list",
+			name:       "Sort, order and pagination",
+			requestURL: "/api/movies?sort=title&order=ASC&limit=1&offset=1",
 			mockBehavior: func(s *mock_service.MockMoviesWithActors) {
-				movies := []filmoteka.MoviesWithActors{}
-				s.EXPECT().GetMovies().Return(movies, nil)
+				list := filmoteka.MoviesList{
+					Movies: []filmoteka.MoviesWithActors{
+						{
+							Id:          2,
+							Title:       "The Great Gatsby",
+							Description: "Old film",
+							ReleaseDate: "2014-03-07",
+							Rating:      9,
+							Actors:      filmoteka.Cast{{ActorSummary: filmoteka.ActorSummary{Id: 7, FirstName: "Leonardo", LastName: "DiCaprio"}, CastRole: filmoteka.CastRole{CharacterName: "Jay Gatsby", BillingOrder: 1, RoleType: filmoteka.CastLead}}},
+						},
+					},
+					Total: 2,
+				}
+
+				s.EXPECT().GetMovies(filmoteka.MovieListParams{PageParams: filmoteka.PageParams{Sort: "title", Order: "asc", Limit: 1, Offset: 1}}).Return(list, nil)
 			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `{"error":"The list of movies is empty"}`,
+			expectedRequestBody: `{"data":[{"id":2,"title":"The Great Gatsby","original_title":"","description":"Old film","release_date":"2014-03-07","rating":9,"country":"","runtime":0,"age_rating":"","genres":null,"actors":[{"id":7,"first_name":"Leonardo","last_name":"DiCaprio","character_name":"Jay Gatsby","billing_order":1,"role_type":"lead"}],"crew":null,"user_rating":{"average":0,"votes":0,"distribution":null}}],"meta":{"total":2,"limit":1,"offset":1}}`,
+		},
+		{
+			name:                "Unsupported sort field",
+			requestURL:          "/api/movies?sort=description",
+			mockBehavior:        func(s *mock_service.MockMoviesWithActors) {},
+			expectedStatusCode:  422,
+			expectedRequestBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"sort must be one of: rating, title, release_date, id"}`,
+		},
+		{
+			name:       "Metadata Filters",
+			requestURL: "/api/movies?genre=2&genre=7&country=US&age_rating=16%2B&min_runtime=90&max_runtime=160",
+			mockBehavior: func(s *mock_service.MockMoviesWithActors) {
+				list := filmoteka.MoviesList{
+					Movies: []filmoteka.MoviesWithActors{
+						{
+							Id:            4,
+							Title:         "Темный рыцарь",
+							OriginalTitle: "The Dark Knight",
+							ReleaseDate:   "2008-07-14",
+							Rating:        7,
+							Country:       "US",
+							Runtime:       152,
+							AgeRating:     "16+",
+							Genres:        filmoteka.Genres{{Id: 2, Name: "боевик"}, {Id: 7, Name: "триллер"}},
+							Actors:        filmoteka.Cast{},
+						},
+					},
+					Total: 1,
+				}
+
+				s.EXPECT().GetMovies(filmoteka.MovieListParams{
+					PageParams: filmoteka.PageParams{Sort: "rating", Order: "desc", Limit: 20},
+					GenreIds:   []int{2, 7},
+					Country:    "US",
+					AgeRating:  "16+",
+					MinRuntime: 90,
+					MaxRuntime: 160,
+				}).Return(list, nil)
+			},
+			expectedStatusCode: 200,
+			expectedRequestBody: `{"data":[{"id":4,"title":"Темный рыцарь","original_title":"The Dark Knight","description":"","release_date":"2008-07-14","rating":7,` +
+				`"country":"US","runtime":152,"age_rating":"16+","genres":[{"id":2,"name":"боевик"},{"id":7,"name":"триллер"}],"actors":[],"crew":null,"user_rating":{"average":0,"votes":0,"distribution":null}}],"meta":{"total":1,"limit":20,"offset":0}}`,
+		},
+		{
+			name:                "Invalid Metadata Filters",
+			requestURL:          "/api/movies?country=usa&age_rating=PG",
+			mockBehavior:        func(s *mock_service.MockMoviesWithActors) {},
+			expectedStatusCode:  422,
+			expectedRequestBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"request has invalid fields","errors":[{"field":"country","message":"must be a two letter country code in upper case"},{"field":"age_rating","message":"must be one of: 0+, 6+, 12+, 16+, 18+"}]}`,
+		},
+		{
+			name:                "Malformed Genre",
+			requestURL:          "/api/movies?genre=drama",
+			mockBehavior:        func(s *mock_service.MockMoviesWithActors) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid genre parameter"}`,
+		},
+		{
+			name:                "Limit too large",
+			requestURL:          "/api/movies?limit=1000",
+			mockBehavior:        func(s *mock_service.MockMoviesWithActors) {},
+			expectedStatusCode:  422,
+			expectedRequestBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"limit must be between 1 and 100"}`,
+		},
+		{
+			name:                "Invalid offset",
+			requestURL:          "/api/movies?offset=abc",
+			mockBehavior:        func(s *mock_service.MockMoviesWithActors) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid offset parameter"}`,
+		},
+		{
+			name:       "Empty list",
+			requestURL: "/api/movies",
+			mockBehavior: func(s *mock_service.MockMoviesWithActors) {
+				s.EXPECT().GetMovies(gomock.Any()).Return(filmoteka.MoviesList{}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"data":[],"meta":{"total":0,"limit":20,"offset":0}}`,
 		},
 	}
 	for _, testCase := range testTable {
@@ -127,7 +298,7 @@ func TestHandler_handleGetAllMovies(t *testing.T) {
 			mux := http.NewServeMux()
 			mux.HandleFunc("/api/movies", handler.handleGetAllMovies)
 
-			req := httptest.NewRequest("GET", "/api/movies", nil)
+			req := httptest.NewRequest("GET", testCase.requestURL, nil)
 
 			w := httptest.NewRecorder()
 
@@ -156,38 +327,40 @@ func TestHandler_handleGetAllMoviesSortedByTitle(t *testing.T) {
 		{
 			name: "OK",
 			mockBehavior: func(s *mock_service.MockMoviesWithActors) {
-				movies := []filmoteka.MoviesWithActors{
-					{
-						Id:          1,
-						Title:       "Dune 2",
-						Description: "New film",
-						ReleaseDate: "2024-03-07",
-						Rating:      9,
-						Actors:      "Zendeya",
-					},
-					{
-						Id:          2,
-						Title:       "The Great Gatsby",
-						Description: "Old film",
-						ReleaseDate: "2014-03-07",
-						Rating:      9,
-						Actors:      "Leonardo DiCaprio",
+				list := filmoteka.MoviesList{
+					Movies: []filmoteka.MoviesWithActors{
+						{
+							Id:          1,
+							Title:       "Dune 2",
+							Description: "New film",
+							ReleaseDate: "2024-03-07",
+							Rating:      9,
+							Actors:      filmoteka.Cast{{ActorSummary: filmoteka.ActorSummary{Id: 2, FirstName: "Zendeya"}, CastRole: filmoteka.CastRole{CharacterName: "Chani", BillingOrder: 2, RoleType: filmoteka.CastLead}}},
+						},
+						{
+							Id:          2,
+							Title:       "The Great Gatsby",
+							Description: "Old film",
+							ReleaseDate: "2014-03-07",
+							Rating:      9,
+							Actors:      filmoteka.Cast{{ActorSummary: filmoteka.ActorSummary{Id: 7, FirstName: "Leonardo", LastName: "DiCaprio"}, CastRole: filmoteka.CastRole{CharacterName: "Jay Gatsby", BillingOrder: 1, RoleType: filmoteka.CastLead}}},
+						},
 					},
+					Total: 2,
 				}
 
-				s.EXPECT().GetMoviesSortedByTitle().Return(movies, nil)
+				s.EXPECT().GetMovies(filmoteka.MovieListParams{PageParams: filmoteka.PageParams{Sort: "title", Order: "asc", Limit: 20}}).Return(list, nil)
 			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `{"data":[{"id":1,"title":"Dune 2","description":"New film","release_date":"2024-03-07","rating":9,"actors":"Zendeya"},{"id":2,"title":"The Great Gatsby","description":"Old film","release_date":"2014-03-07","rating":9,"actors":"Leonardo DiCaprio"}]}`,
+			expectedRequestBody: `{"data":[{"id":1,"title":"Dune 2","original_title":"","description":"New film","release_date":"2024-03-07","rating":9,"country":"","runtime":0,"age_rating":"","genres":null,"actors":[{"id":2,"first_name":"Zendeya","last_name":"","character_name":"Chani","billing_order":2,"role_type":"lead"}],"crew":null,"user_rating":{"average":0,"votes":0,"distribution":null}},{"id":2,"title":"The Great Gatsby","original_title":"","description":"Old film","release_date":"2014-03-07","rating":9,"country":"","runtime":0,"age_rating":"","genres":null,"actors":[{"id":7,"first_name":"Leonardo","last_name":"DiCaprio","character_name":"Jay Gatsby","billing_order":1,"role_type":"lead"}],"crew":null,"user_rating":{"average":0,"votes":0,"distribution":null}}],"meta":{"total":2,"limit":20,"offset":0}}`,
 		},
 		{
 			name: "Empty list",
 			mockBehavior: func(s *mock_service.MockMoviesWithActors) {
-				movies := []filmoteka.MoviesWithActors{}
-				s.EXPECT().GetMoviesSortedByTitle().Return(movies, nil)
+				s.EXPECT().GetMovies(gomock.Any()).Return(filmoteka.MoviesList{}, nil)
 			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `{"error":"The list of movies is empty"}`,
+			expectedRequestBody: `{"data":[],"meta":{"total":0,"limit":20,"offset":0}}`,
 		},
 	}
 	for _, testCase := range testTable {
@@ -234,11 +407,10 @@ func TestHandler_handleGetAllMoviesSortedByDate(t *testing.T) {
 		{
 			name: "Empty list",
 			mockBehavior: func(s *mock_service.MockMoviesWithActors) {
-				movies := []filmoteka.MoviesWithActors{}
-				s.EXPECT().GetMoviesSortedByDate().Return(movies, nil)
+				s.EXPECT().GetMovies(filmoteka.MovieListParams{PageParams: filmoteka.PageParams{Sort: "release_date", Order: "asc", Limit: 20}}).Return(filmoteka.MoviesList{}, nil)
 			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `{"error":"The list of movies is empty"}`,
+			expectedRequestBody: `{"data":[],"meta":{"total":0,"limit":20,"offset":0}}`,
 		},
 	}
 	for _, testCase := range testTable {
@@ -294,20 +466,29 @@ func TestHandler_handleGetMovieById(t *testing.T) {
 					Description: "New film",
 					ReleaseDate: "2024-03-07",
 					Rating:      9,
-					Actors:      "Zendeya",
+					Actors:      filmoteka.Cast{{ActorSummary: filmoteka.ActorSummary{Id: 2, FirstName: "Zendeya"}, CastRole: filmoteka.CastRole{CharacterName: "Chani", BillingOrder: 2, RoleType: filmoteka.CastLead}}},
 				}
 				s.EXPECT().GetMovieById(id).Return(movies, nil)
 			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `{"id":1,"title":"Dune 2","description":"New film","release_date":"2024-03-07","rating":9,"actors":"Zendeya"}`,
+			expectedRequestBody: `{"id":1,"title":"Dune 2","original_title":"","description":"New film","release_date":"2024-03-07","rating":9,"country":"","runtime":0,"age_rating":"","genres":null,"actors":[{"id":2,"first_name":"Zendeya","last_name":"","character_name":"Chani","billing_order":2,"role_type":"lead"}],"crew":null,"user_rating":{"average":0,"votes":0,"distribution":null}}`,
 		},
 		{
 			name:       "Invalid ID parameter",
-			requestURL: "/api/movies/",
+			requestURL: "/api/movies/abc",
 			mockBehavior: func(s *mock_service.MockMoviesWithActors, id int) {
 			},
 			expectedStatusCode:  400,
-			expectedRequestBody: `{"error":"invalid id parameter"}`,
+			expectedRequestBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid id parameter"}`,
+		},
+		{
+			name:       "Not Found",
+			requestURL: "/api/movies/1",
+			mockBehavior: func(s *mock_service.MockMoviesWithActors, id int) {
+				s.EXPECT().GetMovieById(id).Return(filmoteka.MoviesWithActors{}, filmoteka.ErrMovieNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"type":"about:blank","title":"Not Found","status":404,"detail":"movie not found"}`,
 		},
 	}
 	for _, testCase := range testTable {
@@ -323,7 +504,82 @@ func TestHandler_handleGetMovieById(t *testing.T) {
 			handler := NewHandler(services)
 
 			mux := http.NewServeMux()
-			mux.HandleFunc("/api/movies/", handler.handleGetMovieById)
+			mux.HandleFunc("GET /api/movies/{id}", handler.handleGetMovieById)
+
+			req := httptest.NewRequest("GET", testCase.requestURL, nil)
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
+
+func TestHandler_handleSearchMovies(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockMoviesWithActors)
+
+	testTable := []struct {
+		name                string
+		requestURL          string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:       "OK",
+			requestURL: "/movies/search?q=%20дюна%20&limit=5",
+			mockBehavior: func(s *mock_service.MockMoviesWithActors) {
+				s.EXPECT().SearchMovies(filmoteka.MovieSearchParams{Query: "дюна", Limit: 5}).Return(filmoteka.MovieSearchList{
+					Movies: []filmoteka.MovieSearchResult{{
+						MoviesWithActors: filmoteka.MoviesWithActors{Id: 1, Title: "Дюна", ReleaseDate: "2021-09-15", Rating: 8, Actors: filmoteka.Cast{}},
+						Rank:             0.6,
+						Headline:         "<mark>Дюна</mark>. ",
+					}},
+					Total: 1,
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"data":[{"id":1,"title":"Дюна","original_title":"","description":"","release_date":"2021-09-15","rating":8,"country":"","runtime":0,"age_rating":"","genres":null,"actors":[],"crew":null,"user_rating":{"average":0,"votes":0,"distribution":null},"rank":0.6,"headline":"\u003cmark\u003eДюна\u003c/mark\u003e. "}],"meta":{"total":1,"limit":5,"offset":0}}`,
+		},
+		{
+			name:       "Nothing Found",
+			requestURL: "/movies/search?q=solaris&actor=%20banionis",
+			mockBehavior: func(s *mock_service.MockMoviesWithActors) {
+				s.EXPECT().SearchMovies(filmoteka.MovieSearchParams{Query: "solaris", Actor: "banionis", Limit: filmoteka.DefaultListLimit}).Return(filmoteka.MovieSearchList{}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"data":[],"meta":{"total":0,"limit":20,"offset":0}}`,
+		},
+		{
+			name:                "Missing Query",
+			requestURL:          "/movies/search?q=%20",
+			mockBehavior:        func(s *mock_service.MockMoviesWithActors) {},
+			expectedStatusCode:  422,
+			expectedRequestBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"q is required"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			movieService := mock_service.NewMockMoviesWithActors(c)
+			testCase.mockBehavior(movieService)
+
+			services := &service.Service{MoviesWithActors: movieService}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /movies/search", handler.handleSearchMovies)
 
 			req := httptest.NewRequest("GET", testCase.requestURL, nil)
 
@@ -365,13 +621,13 @@ func TestHandler_handleSearchMovieByTitle(t *testing.T) {
 						Description: "New film",
 						ReleaseDate: "2024-03-07",
 						Rating:      9,
-						Actors:      "Zendeya",
+						Actors:      filmoteka.Cast{{ActorSummary: filmoteka.ActorSummary{Id: 2, FirstName: "Zendeya"}, CastRole: filmoteka.CastRole{CharacterName: "Chani", BillingOrder: 2, RoleType: filmoteka.CastLead}}},
 					},
 				}
 				s.EXPECT().SearchMoviesByTitle(fragment).Return(movies, nil)
 			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `{"data":[{"id":1,"title":"Dune 2","description":"New film","release_date":"2024-03-07","rating":9,"actors":"Zendeya"}]}`,
+			expectedRequestBody: `{"data":[{"id":1,"title":"Dune 2","original_title":"","description":"New film","release_date":"2024-03-07","rating":9,"country":"","runtime":0,"age_rating":"","genres":null,"actors":[{"id":2,"first_name":"Zendeya","last_name":"","character_name":"Chani","billing_order":2,"role_type":"lead"}],"crew":null,"user_rating":{"average":0,"votes":0,"distribution":null}}]}`,
 		},
 		{
 			name:          "Empty",
@@ -382,7 +638,7 @@ func TestHandler_handleSearchMovieByTitle(t *testing.T) {
 				s.EXPECT().SearchMoviesByTitle(fragment).Return(movies, nil)
 			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `{"error":"The list of movies is empty"}`,
+			expectedRequestBody: `{"data":[]}`,
 		},
 	}
 	for _, testCase := range testTable {
@@ -440,13 +696,13 @@ func TestHandler_handleSearchMovieByActor(t *testing.T) {
 						Description: "New film",
 						ReleaseDate: "2024-03-07",
 						Rating:      9,
-						Actors:      "Zendeya",
+						Actors:      filmoteka.Cast{{ActorSummary: filmoteka.ActorSummary{Id: 2, FirstName: "Zendeya"}, CastRole: filmoteka.CastRole{CharacterName: "Chani", BillingOrder: 2, RoleType: filmoteka.CastLead}}},
 					},
 				}
 				s.EXPECT().SearchMovieByActorName(fragment).Return(movies, nil)
 			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `{"data":[{"id":1,"title":"Dune 2","description":"New film","release_date":"2024-03-07","rating":9,"actors":"Zendeya"}]}`,
+			expectedRequestBody: `{"data":[{"id":1,"title":"Dune 2","original_title":"","description":"New film","release_date":"2024-03-07","rating":9,"country":"","runtime":0,"age_rating":"","genres":null,"actors":[{"id":2,"first_name":"Zendeya","last_name":"","character_name":"Chani","billing_order":2,"role_type":"lead"}],"crew":null,"user_rating":{"average":0,"votes":0,"distribution":null}}]}`,
 		},
 		{
 			name:          "Empty",
@@ -457,7 +713,7 @@ func TestHandler_handleSearchMovieByActor(t *testing.T) {
 				s.EXPECT().SearchMovieByActorName(fragment).Return(movies, nil)
 			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `{"error":"The list of movies is empty"}`,
+			expectedRequestBody: `{"data":[]}`,
 		},
 	}
 	for _, testCase := range testTable {
@@ -493,28 +749,95 @@ func TestHandler_handleSearchMovieByActor(t *testing.T) {
 }
 
 func TestHandler_handleUpdateMovie(t *testing.T) {
-	type mockBehavior func(s *mock_service.MockMovies, id int)
+	type mockBehavior func(s *mock_service.MockMoviesWithActors, id int)
 
 	testTable := []struct {
 		name                string
+		method              string
 		requestURL          string
+		headers             map[string]string
+		inputBody           string
 		mockBehavior        mockBehavior
 		expectedStatusCode  int
 		expectedRequestBody string
+		expectedETag        string
 	}{
 		{
-			name:                "Only for administrator",
+			name:       "OK",
+			method:     "PUT",
+			requestURL: "/movies/update/1",
+			inputBody:  `{}`,
+			mockBehavior: func(s *mock_service.MockMoviesWithActors, id int) {
+				s.EXPECT().UpdateMovie(id, filmoteka.UpdateMovies{}, 0).Return(2, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+			expectedETag:        `"2"`,
+		},
+		{
+			name:       "Merge Patch",
+			method:     "PATCH",
+			requestURL: "/movies/update/1",
+			headers:    map[string]string{"Content-Type": "application/merge-patch+json", "If-Match": `"3"`},
+			inputBody:  `{"description":null,"rating":8}`,
+			mockBehavior: func(s *mock_service.MockMoviesWithActors, id int) {
+				description, rating := "", 8
+				s.EXPECT().UpdateMovie(id, filmoteka.UpdateMovies{Description: &description, Rating: &rating}, 3).Return(4, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+			expectedETag:        `"4"`,
+		},
+		{
+			name:       "Stale Version",
+			method:     "PATCH",
+			requestURL: "/movies/update/1",
+			headers:    map[string]string{"If-Match": `"3"`},
+			inputBody:  `{"rating":8}`,
+			mockBehavior: func(s *mock_service.MockMoviesWithActors, id int) {
+				rating := 8
+				s.EXPECT().UpdateMovie(id, filmoteka.UpdateMovies{Rating: &rating}, 3).Return(0, filmoteka.ErrVersionMismatch)
+			},
+			expectedStatusCode:  412,
+			expectedRequestBody: `{"type":"about:blank","title":"Precondition Failed","status":412,"detail":"resource has changed since it was read"}`,
+		},
+		{
+			name:                "Weak If-Match Never Matches",
+			method:              "PATCH",
 			requestURL:          "/movies/update/1",
-			mockBehavior:        func(s *mock_service.MockMovies, id int) {},
-			expectedStatusCode:  403,
-			expectedRequestBody: `{"error":"This function is only available to the administrator"}`,
+			headers:             map[string]string{"If-Match": `W/"3"`},
+			inputBody:           `{"rating":8}`,
+			mockBehavior:        func(s *mock_service.MockMoviesWithActors, id int) {},
+			expectedStatusCode:  412,
+			expectedRequestBody: `{"type":"about:blank","title":"Precondition Failed","status":412,"detail":"resource has changed since it was read"}`,
+		},
+		{
+			name:                "Null On Required Field",
+			method:              "PATCH",
+			requestURL:          "/movies/update/1",
+			inputBody:           `{"title":null}`,
+			mockBehavior:        func(s *mock_service.MockMoviesWithActors, id int) {},
+			expectedStatusCode:  422,
+			expectedRequestBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"request has invalid fields","errors":[{"field":"title","message":"can't be cleared"}]}`,
+		},
+		{
+			name:                "Unsupported Media Type",
+			method:              "PATCH",
+			requestURL:          "/movies/update/1",
+			headers:             map[string]string{"Content-Type": "text/plain"},
+			inputBody:           `{"rating":8}`,
+			mockBehavior:        func(s *mock_service.MockMoviesWithActors, id int) {},
+			expectedStatusCode:  415,
+			expectedRequestBody: `{"type":"about:blank","title":"Unsupported Media Type","status":415,"detail":"PATCH takes application/merge-patch+json"}`,
 		},
 		{
 			name:                "Missing ID Parameter",
+			method:              "PUT",
 			requestURL:          "/movies/update",
-			mockBehavior:        func(s *mock_service.MockMovies, id int) {},
-			expectedStatusCode:  301,
-			expectedRequestBody: ``,
+			inputBody:           `{}`,
+			mockBehavior:        func(s *mock_service.MockMoviesWithActors, id int) {},
+			expectedStatusCode:  404,
+			expectedRequestBody: `404 page not found`,
 		},
 	}
 	for _, testCase := range testTable {
@@ -523,16 +846,20 @@ func TestHandler_handleUpdateMovie(t *testing.T) {
 			c := gomock.NewController(t)
 			defer c.Finish()
 
-			moviesService := mock_service.NewMockMovies(c)
+			moviesService := mock_service.NewMockMoviesWithActors(c)
 			testCase.mockBehavior(moviesService, 1)
 
-			services := &service.Service{Movies: moviesService}
+			services := &service.Service{MoviesWithActors: moviesService}
 			handler := NewHandler(services)
 
 			mux := http.NewServeMux()
-			mux.HandleFunc("/movies/update/", handler.handleUpdateMovie)
+			mux.HandleFunc("PUT /movies/update/{id}", handler.handleUpdateMovie)
+			mux.HandleFunc("PATCH /movies/update/{id}", handler.handleUpdateMovie)
 
-			req := httptest.NewRequest("PUT", testCase.requestURL, nil)
+			req := httptest.NewRequest(testCase.method, testCase.requestURL, bytes.NewBufferString(testCase.inputBody))
+			for key, value := range testCase.headers {
+				req.Header.Set(key, value)
+			}
 
 			w := httptest.NewRecorder()
 
@@ -544,6 +871,7 @@ func TestHandler_handleUpdateMovie(t *testing.T) {
 			//Asserts
 			assert.Equal(t, testCase.expectedStatusCode, w.Code)
 			assert.Equal(t, expected, actual)
+			assert.Equal(t, testCase.expectedETag, w.Header().Get("ETag"))
 
 		})
 	}
@@ -560,18 +888,20 @@ func TestHandler_handleDeleteMovie(t *testing.T) {
 		expectedRequestBody string
 	}{
 		{
-			name:                "Only for administrator",
-			requestURL:          "/movies/delete/1",
-			mockBehavior:        func(s *mock_service.MockMovies, id int) {},
-			expectedStatusCode:  403,
-			expectedRequestBody: `{"error":"This function is only available to the administrator"}`,
+			name:       "OK",
+			requestURL: "/movies/delete/1",
+			mockBehavior: func(s *mock_service.MockMovies, id int) {
+				s.EXPECT().DeleteMovie(id).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
 		},
 		{
 			name:                "Missing ID Parameter",
 			requestURL:          "/movies/delete",
 			mockBehavior:        func(s *mock_service.MockMovies, id int) {},
-			expectedStatusCode:  301,
-			expectedRequestBody: ``,
+			expectedStatusCode:  404,
+			expectedRequestBody: `404 page not found`,
 		},
 	}
 	for _, testCase := range testTable {
@@ -587,7 +917,7 @@ func TestHandler_handleDeleteMovie(t *testing.T) {
 			handler := NewHandler(services)
 
 			mux := http.NewServeMux()
-			mux.HandleFunc("/movies/delete/", handler.handleDeleteMovie)
+			mux.HandleFunc("DELETE /movies/delete/{id}", handler.handleDeleteMovie)
 
 			req := httptest.NewRequest("DELETE", testCase.requestURL, nil)
 
@@ -0,0 +1,277 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	filmoteka "vk_restAPI"
+	logger "vk_restAPI/logs"
+)
+
+type CreatePersonRequest struct {
+	FirstName   string `json:"first_name" maxLength:"100"`
+	LastName    string `json:"last_name" maxLength:"100"`
+	Gender      string `json:"gender" enums:"male,female,other"`
+	DateOfBirth string `json:"date_of_birth" format:"date"`
+	KnownFor    string `json:"known_for" enums:"acting,directing,writing,production,sound,camera,editing"`
+}
+
+type getPeopleResponse struct {
+	Data []filmoteka.Person `json:"data"`
+	Meta *listMeta          `json:"meta,omitempty"`
+}
+
+// @Summary Create Person
+// @Security ApiKeyAuth
+// @Tags people
+// @Description Create a new person. People known for acting are listed among the actors right away, others once they are cast.
+// @Accept json
+// @Produce json
+// @Param input body CreatePersonRequest true "Person information"
+// @Success 200 {string} string "id"
+// @Failure 400 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/people [post]
+func (h *Handler) handleCreatePerson(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Create Person request")
+
+	var input filmoteka.Person
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logger.Log.Error("Failed to decode request body: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id, err := h.service.People.CreatePerson(input)
+	if err != nil {
+		logger.Log.Error("Failed to create person: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := map[string]interface{}{
+		"id": id,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+// @Summary Get All People
+// @Security ApiKeyAuth
+// @Tags people
+// @Description Get a page of people in front of and behind the camera, ordered by last name.
+// @Description department keeps the people known for it or credited in it.
+// @Accept json
+// @Produce json
+// @Param q query string false "Fragment of the full name"
+// @Param department query string false "Department" Enums(acting, directing, writing, production, sound, camera, editing)
+// @Param limit query int false "Page size" default(20) maximum(100)
+// @Param offset query int false "Number of people to skip" default(0)
+// @Success 200 {object} getPeopleResponse
+// @Failure 400 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/people [get]
+func (h *Handler) handleGetAllPeople(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Get All People")
+
+	params, err := parsePersonListParams(r)
+	if err != nil {
+		logger.Log.Error("Invalid list parameters: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	list, err := h.service.People.GetPeople(params)
+	if err != nil {
+		logger.Log.Error("Failed to Get All People: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := getPeopleResponse{
+		Data: orEmpty(list.People),
+		Meta: &listMeta{
+			Total:  list.Total,
+			Limit:  params.Limit,
+			Offset: params.Offset,
+		},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+func parsePersonListParams(r *http.Request) (filmoteka.PersonListParams, error) {
+	query := r.URL.Query()
+
+	limit, err := queryInt(query, "limit", filmoteka.DefaultListLimit)
+	if err != nil {
+		return filmoteka.PersonListParams{}, err
+	}
+
+	offset, err := queryInt(query, "offset", 0)
+	if err != nil {
+		return filmoteka.PersonListParams{}, err
+	}
+
+	params := filmoteka.PersonListParams{
+		Name:       strings.TrimSpace(query.Get("q")),
+		Department: query.Get("department"),
+		Limit:      limit,
+		Offset:     offset,
+	}
+	return params, params.Validate()
+}
+
+// @Summary Get Person By ID
+// @Security ApiKeyAuth
+// @Tags people
+// @Description Get a person with their filmography: acting credits in movies, the rest in crew.
+// @Accept json
+// @Produce json
+// @Param id path int true "Person ID"
+// @Success 200 {object} filmoteka.PersonWithCredits
+// @Header 200 {string} ETag "Version of the person, for If-Match"
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/people/{id} [get]
+func (h *Handler) handleGetPersonById(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Get Person By ID")
+
+	id, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	person, err := h.service.People.GetPersonById(id)
+	if err != nil {
+		logger.Log.Error("Failed to Get Person By ID: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := person
+	w.Header().Set("ETag", etag(person.Version))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+// @Summary Update Person
+// @Security ApiKeyAuth
+// @Tags people
+// @Description Update information about a person, whether they act or work behind the camera. PATCH takes a JSON merge patch, in which null clears a field that can be empty. Send the ETag of a previous read in If-Match to fail with 412 instead of overwriting someone else's change.
+// @Accept json,application/merge-patch+json
+// @Produce json
+// @Param id path int true "Person ID"
+// @Param If-Match header string false "ETag the update is conditional on"
+// @Param input body filmoteka.UpdatePerson true "Person information for update"
+// @Success 200 {object} StatusResponse
+// @Header 200 {string} ETag "New version of the person"
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 412 {object} Problem
+// @Failure 415 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/people/{id} [patch]
+func (h *Handler) handleUpdatePerson(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Update Person")
+
+	id, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		logger.Log.Error("Invalid If-Match header: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	var input filmoteka.UpdatePerson
+	if err := decodeUpdate(r, &input); err != nil {
+		logger.Log.Error("Failed to decode request body: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	newVersion, err := h.service.People.UpdatePerson(id, input, version)
+	if err != nil {
+		logger.Log.Error("Failed to update person: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := StatusResponse{Status: "ok"}
+	w.Header().Set("ETag", etag(newVersion))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+// @Summary Delete Person by Id
+// @Security ApiKeyAuth
+// @Tags people
+// @Description Delete a person. A person credited in any movie, in the cast or in the crew, is kept with 409.
+// @Accept json
+// @Produce json
+// @Param id path int true "Person ID"
+// @Success 200 {object} StatusResponse
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/people/{id} [delete]
+func (h *Handler) handleDeletePerson(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Delete Person")
+
+	id, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.service.People.DeletePerson(id); err != nil {
+		logger.Log.Error("Failed to delete person: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := StatusResponse{Status: "ok"}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
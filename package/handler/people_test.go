@@ -0,0 +1,358 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	filmoteka "vk_restAPI"
+	"vk_restAPI/package/service"
+	mock_service "vk_restAPI/package/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_handleCreatePerson(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockPeople)
+
+	person := filmoteka.Person{FirstName: "Ханс", LastName: "Циммер", Gender: "male", DateOfBirth: "1957-09-12", KnownFor: "sound"}
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"first_name":"Ханс", "last_name":"Циммер", "gender":"male", "date_of_birth":"1957-09-12", "known_for":"sound"}`,
+			mockBehavior: func(s *mock_service.MockPeople) {
+				s.EXPECT().CreatePerson(person).Return(15, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":15}`,
+		},
+		{
+			name:      "Name Taken",
+			inputBody: `{"first_name":"Ханс", "last_name":"Циммер", "gender":"male", "date_of_birth":"1957-09-12", "known_for":"sound"}`,
+			mockBehavior: func(s *mock_service.MockPeople) {
+				s.EXPECT().CreatePerson(person).Return(0, filmoteka.ErrPersonExists)
+			},
+			expectedStatusCode:  409,
+			expectedRequestBody: `{"type":"about:blank","title":"Conflict","status":409,"detail":"person with the same name already exists"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			peopleService := mock_service.NewMockPeople(c)
+			testCase.mockBehavior(peopleService)
+
+			services := &service.Service{People: peopleService}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /people", handler.handleCreatePerson)
+
+			req := httptest.NewRequest("POST", "/people", bytes.NewBufferString(testCase.inputBody))
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
+
+func TestHandler_handleGetAllPeople(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockPeople)
+
+	testTable := []struct {
+		name                string
+		requestURL          string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:       "Directors",
+			requestURL: "/people?q=%20финч&department=directing",
+			mockBehavior: func(s *mock_service.MockPeople) {
+				s.EXPECT().GetPeople(filmoteka.PersonListParams{Name: "финч", Department: "directing", Limit: 20}).
+					Return(filmoteka.PeopleList{
+						People: []filmoteka.Person{{Id: 19, FirstName: "Дэвид", LastName: "Финчер", Gender: "male", DateOfBirth: "1962-08-28", KnownFor: "directing"}},
+						Total:  1,
+					}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"data":[{"id":19,"first_name":"Дэвид","last_name":"Финчер","gender":"male","date_of_birth":"1962-08-28","known_for":"directing"}],"meta":{"total":1,"limit":20,"offset":0}}`,
+		},
+		{
+			name:       "Empty",
+			requestURL: "/people?offset=40",
+			mockBehavior: func(s *mock_service.MockPeople) {
+				s.EXPECT().GetPeople(filmoteka.PersonListParams{Limit: 20, Offset: 40}).Return(filmoteka.PeopleList{Total: 3}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"data":[],"meta":{"total":3,"limit":20,"offset":40}}`,
+		},
+		{
+			name:                "Unknown Department",
+			requestURL:          "/people?department=catering",
+			mockBehavior:        func(s *mock_service.MockPeople) {},
+			expectedStatusCode:  422,
+			expectedRequestBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"request has invalid fields","errors":[{"field":"department","message":"must be one of: acting, directing, writing, production, sound, camera, editing"}]}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			peopleService := mock_service.NewMockPeople(c)
+			testCase.mockBehavior(peopleService)
+
+			services := &service.Service{People: peopleService}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /people", handler.handleGetAllPeople)
+
+			req := httptest.NewRequest("GET", testCase.requestURL, nil)
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
+
+func TestHandler_handleGetPersonById(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockPeople)
+
+	testTable := []struct {
+		name                string
+		requestURL          string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:       "OK",
+			requestURL: "/people/14",
+			mockBehavior: func(s *mock_service.MockPeople) {
+				s.EXPECT().GetPersonById(14).Return(filmoteka.PersonWithCredits{
+					Person: filmoteka.Person{Id: 14, FirstName: "Дени", LastName: "Вильнёв", Gender: "male", DateOfBirth: "1967-10-03", KnownFor: "directing"},
+					Movies: filmoteka.Appearances{},
+					Crew: filmoteka.CrewCredits{
+						{MovieSummary: filmoteka.MovieSummary{Id: 1, Title: "Дюна: Часть вторая", ReleaseDate: "2024-02-29"}, Department: "directing", Job: "Режиссёр"},
+					},
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedRequestBody: `{"id":14,"first_name":"Дени","last_name":"Вильнёв","gender":"male","date_of_birth":"1967-10-03","known_for":"directing",` +
+				`"movies":[],"crew":[{"id":1,"title":"Дюна: Часть вторая","release_date":"2024-02-29","department":"directing","job":"Режиссёр"}]}`,
+		},
+		{
+			name:       "Not Found",
+			requestURL: "/people/99",
+			mockBehavior: func(s *mock_service.MockPeople) {
+				s.EXPECT().GetPersonById(99).Return(filmoteka.PersonWithCredits{}, filmoteka.ErrPersonNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"type":"about:blank","title":"Not Found","status":404,"detail":"person not found"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			peopleService := mock_service.NewMockPeople(c)
+			testCase.mockBehavior(peopleService)
+
+			services := &service.Service{People: peopleService}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /people/{id}", handler.handleGetPersonById)
+
+			req := httptest.NewRequest("GET", testCase.requestURL, nil)
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
+
+func TestHandler_handleUpdatePerson(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockPeople)
+
+	department := filmoteka.DepartmentWriting
+
+	testTable := []struct {
+		name                string
+		requestURL          string
+		ifMatch             string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedETag        string
+		expectedRequestBody string
+	}{
+		{
+			name:       "OK",
+			requestURL: "/people/14",
+			ifMatch:    `"2"`,
+			mockBehavior: func(s *mock_service.MockPeople) {
+				s.EXPECT().UpdatePerson(14, filmoteka.UpdatePerson{KnownFor: &department}, 2).Return(3, nil)
+			},
+			expectedStatusCode:  200,
+			expectedETag:        `"3"`,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+		{
+			name:       "Stale Version",
+			requestURL: "/people/14",
+			ifMatch:    `"1"`,
+			mockBehavior: func(s *mock_service.MockPeople) {
+				s.EXPECT().UpdatePerson(14, filmoteka.UpdatePerson{KnownFor: &department}, 1).Return(0, filmoteka.ErrVersionMismatch)
+			},
+			expectedStatusCode:  412,
+			expectedRequestBody: `{"type":"about:blank","title":"Precondition Failed","status":412,"detail":"resource has changed since it was read"}`,
+		},
+		{
+			name:       "Not Found",
+			requestURL: "/people/99",
+			mockBehavior: func(s *mock_service.MockPeople) {
+				s.EXPECT().UpdatePerson(99, filmoteka.UpdatePerson{KnownFor: &department}, 0).Return(0, filmoteka.ErrPersonNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"type":"about:blank","title":"Not Found","status":404,"detail":"person not found"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			peopleService := mock_service.NewMockPeople(c)
+			testCase.mockBehavior(peopleService)
+
+			services := &service.Service{People: peopleService}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("PATCH /people/{id}", handler.handleUpdatePerson)
+
+			req := httptest.NewRequest("PATCH", testCase.requestURL, bytes.NewBufferString(`{"known_for":"writing"}`))
+			req.Header.Set("Content-Type", "application/merge-patch+json")
+			if testCase.ifMatch != "" {
+				req.Header.Set("If-Match", testCase.ifMatch)
+			}
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedETag, w.Header().Get("ETag"))
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
+
+func TestHandler_handleDeletePerson(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockPeople)
+
+	testTable := []struct {
+		name                string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mock_service.MockPeople) {
+				s.EXPECT().DeletePerson(14).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+		{
+			name: "Credited",
+			mockBehavior: func(s *mock_service.MockPeople) {
+				s.EXPECT().DeletePerson(14).Return(filmoteka.ErrPersonInUse)
+			},
+			expectedStatusCode:  409,
+			expectedRequestBody: `{"type":"about:blank","title":"Conflict","status":409,"detail":"person is credited in movies"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			peopleService := mock_service.NewMockPeople(c)
+			testCase.mockBehavior(peopleService)
+
+			services := &service.Service{People: peopleService}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("DELETE /people/{id}", handler.handleDeletePerson)
+
+			req := httptest.NewRequest("DELETE", "/people/14", nil)
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
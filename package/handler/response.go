@@ -2,25 +2,111 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	filmoteka "vk_restAPI"
 
 	"github.com/sirupsen/logrus"
 )
 
-type Err struct {
-	Message string `json:"message"`
+// Problem is an RFC 7807 problem details body. Type is left as about:blank,
+// so Title is always the status text.
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// validationProblem reports every invalid field of a request.
+type validationProblem struct {
+	Problem
+	Errors filmoteka.ValidationErrors `json:"errors"`
 }
 
 type StatusResponse struct {
 	Status string `json:"status"`
 }
 
+// errMalformedRequest is the kind of input errors where the request couldn't
+// be read at all, as opposed to values that were read but are not valid.
+var errMalformedRequest = errors.New("malformed request")
+
+// errUnsupportedMediaType is the kind of errors for bodies sent in a format
+// the endpoint doesn't read.
+var errUnsupportedMediaType = errors.New("unsupported media type")
+
+func newProblem(statusCode int, detail string) Problem {
+	return Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(statusCode),
+		Status: statusCode,
+		Detail: detail,
+	}
+}
+
 func NewErrorResponse(w http.ResponseWriter, statusCode int, message string) {
 	logrus.Error(message)
+	writeProblem(w, statusCode, newProblem(statusCode, message))
+}
+
+// writeProblem sends problem, which is a Problem or a struct embedding one
+// to carry extension members.
+func writeProblem(w http.ResponseWriter, statusCode int, problem interface{}) {
+	w.Header().Set("Content-Type", "application/problem+json")
 	w.WriteHeader(statusCode)
-	response := map[string]interface{}{
-		"error": message,
+	json.NewEncoder(w).Encode(problem)
+}
+
+// errorStatus maps an error to the status it is reported with.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, filmoteka.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, filmoteka.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, filmoteka.ErrValidation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, errMalformedRequest), errors.Is(err, filmoteka.ErrInvalidCursor):
+		return http.StatusBadRequest
+	case errors.Is(err, filmoteka.ErrInvalidCredentials), errors.Is(err, filmoteka.ErrInvalidRefreshToken):
+		return http.StatusUnauthorized
+	case errors.Is(err, filmoteka.ErrVersionMismatch):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, errUnsupportedMediaType):
+		return http.StatusUnsupportedMediaType
+	}
+	return http.StatusInternalServerError
+}
+
+// writeError reports err as a problem. Errors of an unknown kind become a
+// 500 whose detail stays in the log, so driver and SQL messages never reach
+// the client.
+func writeError(w http.ResponseWriter, err error) {
+	status := errorStatus(err)
+	if status == http.StatusInternalServerError {
+		logrus.Error(err.Error())
+		writeProblem(w, status, newProblem(status, "internal server error"))
+		return
+	}
+
+	var fields filmoteka.ValidationErrors
+	if errors.As(err, &fields) {
+		logrus.Error(err.Error())
+		writeProblem(w, status, validationProblem{
+			Problem: newProblem(status, "request has invalid fields"),
+			Errors:  fields,
+		})
+		return
+	}
+
+	NewErrorResponse(w, status, err.Error())
+}
+
+// orEmpty makes a nil list encode as [] rather than null.
+func orEmpty[T any](items []T) []T {
+	if items == nil {
+		return []T{}
 	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	return items
 }
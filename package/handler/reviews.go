@@ -0,0 +1,431 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	filmoteka "vk_restAPI"
+	logger "vk_restAPI/logs"
+)
+
+type ReviewRequest struct {
+	Rating int    `json:"rating" minimum:"1" maximum:"10"`
+	Body   string `json:"body" maxLength:"5000"`
+}
+
+type ReviewStatusRequest struct {
+	Status string `json:"status" enums:"published,hidden"`
+}
+
+type getReviewsResponse struct {
+	Data []filmoteka.Review `json:"data"`
+	Meta *listMeta          `json:"meta,omitempty"`
+}
+
+// @Summary Review Movie
+// @Security ApiKeyAuth
+// @Tags reviews
+// @Description Rate a movie from 1 to 10, optionally with a text. Every user reviews a movie once; edit the review through /api/v1/me/reviews/{id} afterwards.
+// @Accept json
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param input body ReviewRequest true "Rating and text"
+// @Success 200 {string} string "id"
+// @Failure 400 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/movies/{id}/reviews [post]
+func (h *Handler) handleCreateReview(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Create Review request")
+
+	movieId, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId, err := getUserId(r)
+	if err != nil {
+		logger.Log.Error("Failed to get user id: ", err.Error())
+		NewErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var input filmoteka.ReviewInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logger.Log.Error("Failed to decode request body: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id, err := h.service.Reviews.CreateReview(movieId, userId, input)
+	if err != nil {
+		logger.Log.Error("Failed to create review: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := map[string]interface{}{
+		"id": id,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+// @Summary Get Movie Reviews
+// @Security ApiKeyAuth
+// @Tags reviews
+// @Description Get a page of the published reviews of a movie, newest first. The aggregated rating is in the movie's user_rating.
+// @Accept json
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param limit query int false "Page size" default(20) maximum(100)
+// @Param offset query int false "Number of reviews to skip" default(0)
+// @Success 200 {object} getReviewsResponse
+// @Failure 400 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/movies/{id}/reviews [get]
+func (h *Handler) handleGetMovieReviews(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Get Movie Reviews")
+
+	movieId, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params, err := parseReviewPage(r)
+	if err != nil {
+		logger.Log.Error("Invalid list parameters: ", err.Error())
+		writeError(w, err)
+		return
+	}
+	params.MovieId = movieId
+	params.Status = filmoteka.ReviewPublished
+
+	h.listReviews(w, params)
+}
+
+// @Summary Get My Reviews
+// @Security ApiKeyAuth
+// @Tags reviews
+// @Description Get a page of the reviews the caller wrote, newest first, hidden ones included.
+// @Accept json
+// @Produce json
+// @Param limit query int false "Page size" default(20) maximum(100)
+// @Param offset query int false "Number of reviews to skip" default(0)
+// @Success 200 {object} getReviewsResponse
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/me/reviews [get]
+func (h *Handler) handleGetMyReviews(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Get My Reviews")
+
+	userId, err := getUserId(r)
+	if err != nil {
+		logger.Log.Error("Failed to get user id: ", err.Error())
+		NewErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	params, err := parseReviewPage(r)
+	if err != nil {
+		logger.Log.Error("Invalid list parameters: ", err.Error())
+		writeError(w, err)
+		return
+	}
+	params.UserId = userId
+
+	h.listReviews(w, params)
+}
+
+// @Summary Get All Reviews
+// @Security ApiKeyAuth
+// @Tags reviews
+// @Description Get a page of every review, newest first, for moderation. status, movie_id and user_id narrow the list down.
+// @Accept json
+// @Produce json
+// @Param status query string false "Review status" Enums(published, hidden)
+// @Param movie_id query int false "Movie ID"
+// @Param user_id query int false "Author ID"
+// @Param limit query int false "Page size" default(20) maximum(100)
+// @Param offset query int false "Number of reviews to skip" default(0)
+// @Success 200 {object} getReviewsResponse
+// @Failure 400 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/reviews [get]
+func (h *Handler) handleGetReviews(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Get All Reviews")
+
+	params, err := parseReviewPage(r)
+	if err != nil {
+		logger.Log.Error("Invalid list parameters: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	query := r.URL.Query()
+	params.Status = query.Get("status")
+
+	if params.MovieId, err = queryInt(query, "movie_id", 0); err != nil {
+		logger.Log.Error("Invalid list parameters: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	if params.UserId, err = queryInt(query, "user_id", 0); err != nil {
+		logger.Log.Error("Invalid list parameters: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	h.listReviews(w, params)
+}
+
+// parseReviewPage reads the page of a review list. The filters are up to
+// the endpoint.
+func parseReviewPage(r *http.Request) (filmoteka.ReviewListParams, error) {
+	query := r.URL.Query()
+
+	limit, err := queryInt(query, "limit", filmoteka.DefaultListLimit)
+	if err != nil {
+		return filmoteka.ReviewListParams{}, err
+	}
+
+	offset, err := queryInt(query, "offset", 0)
+	if err != nil {
+		return filmoteka.ReviewListParams{}, err
+	}
+
+	return filmoteka.ReviewListParams{Limit: limit, Offset: offset}, nil
+}
+
+// listReviews validates params and writes the page of reviews they select.
+func (h *Handler) listReviews(w http.ResponseWriter, params filmoteka.ReviewListParams) {
+	if err := params.Validate(); err != nil {
+		logger.Log.Error("Invalid list parameters: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	list, err := h.service.Reviews.GetReviews(params)
+	if err != nil {
+		logger.Log.Error("Failed to Get Reviews: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := getReviewsResponse{
+		Data: orEmpty(list.Reviews),
+		Meta: &listMeta{
+			Total:  list.Total,
+			Limit:  params.Limit,
+			Offset: params.Offset,
+		},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+// @Summary Update My Review
+// @Security ApiKeyAuth
+// @Tags reviews
+// @Description Edit a review the caller wrote. PATCH takes a JSON merge patch, in which null clears the text. Other users' reviews are reported as not found.
+// @Accept json,application/merge-patch+json
+// @Produce json
+// @Param id path int true "Review ID"
+// @Param input body filmoteka.UpdateReview true "Rating and text"
+// @Success 200 {object} StatusResponse
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 415 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/me/reviews/{id} [patch]
+func (h *Handler) handleUpdateMyReview(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Update My Review")
+
+	id, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId, err := getUserId(r)
+	if err != nil {
+		logger.Log.Error("Failed to get user id: ", err.Error())
+		NewErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var input filmoteka.UpdateReview
+	if err := decodeUpdate(r, &input); err != nil {
+		logger.Log.Error("Failed to decode request body: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	if err := h.service.Reviews.UpdateReview(id, userId, input); err != nil {
+		logger.Log.Error("Failed to update review: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := StatusResponse{Status: "ok"}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+// @Summary Delete My Review
+// @Security ApiKeyAuth
+// @Tags reviews
+// @Description Delete a review the caller wrote, along with its rating.
+// @Accept json
+// @Produce json
+// @Param id path int true "Review ID"
+// @Success 200 {object} StatusResponse
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/me/reviews/{id} [delete]
+func (h *Handler) handleDeleteMyReview(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Delete My Review")
+
+	id, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId, err := getUserId(r)
+	if err != nil {
+		logger.Log.Error("Failed to get user id: ", err.Error())
+		NewErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	h.deleteReview(w, id, userId)
+}
+
+// @Summary Delete Review
+// @Security ApiKeyAuth
+// @Tags reviews
+// @Description Delete any review. Hiding it with PUT /api/v1/reviews/{id}/status keeps it for its author instead.
+// @Accept json
+// @Produce json
+// @Param id path int true "Review ID"
+// @Success 200 {object} StatusResponse
+// @Failure 400 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/reviews/{id} [delete]
+func (h *Handler) handleDeleteReview(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Delete Review")
+
+	id, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.deleteReview(w, id, 0)
+}
+
+// deleteReview deletes a review written by userId, or any review when
+// userId is 0.
+func (h *Handler) deleteReview(w http.ResponseWriter, reviewId, userId int) {
+	if err := h.service.Reviews.DeleteReview(reviewId, userId); err != nil {
+		logger.Log.Error("Failed to delete review: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := StatusResponse{Status: "ok"}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+// @Summary Moderate Review
+// @Security ApiKeyAuth
+// @Tags reviews
+// @Description Hide a review or publish it again. Hidden reviews are only shown to their author and don't count towards the movie's user rating.
+// @Accept json
+// @Produce json
+// @Param id path int true "Review ID"
+// @Param input body ReviewStatusRequest true "New status"
+// @Success 200 {object} StatusResponse
+// @Failure 400 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/reviews/{id}/status [put]
+func (h *Handler) handleSetReviewStatus(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Set Review Status")
+
+	id, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var input filmoteka.ReviewStatus
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logger.Log.Error("Failed to decode request body: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.service.Reviews.SetReviewStatus(id, input); err != nil {
+		logger.Log.Error("Failed to set review status: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := StatusResponse{Status: "ok"}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
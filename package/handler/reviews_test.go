@@ -0,0 +1,355 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	filmoteka "vk_restAPI"
+	"vk_restAPI/package/service"
+	mock_service "vk_restAPI/package/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_handleCreateReview(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockReviews)
+
+	testTable := []struct {
+		name                string
+		requestURL          string
+		inputBody           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:       "OK",
+			requestURL: "/movies/1/reviews",
+			inputBody:  `{"rating":9, "body":"Лучший фильм года"}`,
+			mockBehavior: func(s *mock_service.MockReviews) {
+				s.EXPECT().CreateReview(1, 2, filmoteka.ReviewInput{Rating: 9, Body: "Лучший фильм года"}).Return(5, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":5}`,
+		},
+		{
+			name:       "Already Reviewed",
+			requestURL: "/movies/1/reviews",
+			inputBody:  `{"rating":9}`,
+			mockBehavior: func(s *mock_service.MockReviews) {
+				s.EXPECT().CreateReview(1, 2, filmoteka.ReviewInput{Rating: 9}).Return(0, filmoteka.ErrReviewExists)
+			},
+			expectedStatusCode:  409,
+			expectedRequestBody: `{"type":"about:blank","title":"Conflict","status":409,"detail":"movie is already reviewed, edit the review instead"}`,
+		},
+		{
+			name:       "No Movie",
+			requestURL: "/movies/100/reviews",
+			inputBody:  `{"rating":9}`,
+			mockBehavior: func(s *mock_service.MockReviews) {
+				s.EXPECT().CreateReview(100, 2, filmoteka.ReviewInput{Rating: 9}).Return(0, filmoteka.ErrMovieNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"type":"about:blank","title":"Not Found","status":404,"detail":"movie not found"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			reviews := mock_service.NewMockReviews(c)
+			testCase.mockBehavior(reviews)
+
+			services := &service.Service{Reviews: reviews}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /movies/{id}/reviews", handler.handleCreateReview)
+
+			req := httptest.NewRequest("POST", testCase.requestURL, bytes.NewBufferString(testCase.inputBody))
+			req = req.WithContext(context.WithValue(req.Context(), userCtx, 2))
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
+
+func TestHandler_handleGetMovieReviews(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockReviews)
+
+	created := time.Date(2024, 3, 8, 20, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name                string
+		requestURL          string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:       "Published Only",
+			requestURL: "/movies/1/reviews?limit=1",
+			mockBehavior: func(s *mock_service.MockReviews) {
+				s.EXPECT().GetReviews(filmoteka.ReviewListParams{MovieId: 1, Status: filmoteka.ReviewPublished, Limit: 1}).
+					Return(filmoteka.ReviewsList{
+						Reviews: []filmoteka.Review{{
+							Id: 5, MovieId: 1, MovieTitle: "Дюна 2", UserId: 2, Username: "user", Rating: 9,
+							Body: "Лучший фильм года", Status: "published", CreatedAt: created, UpdatedAt: created,
+						}},
+						Total: 3,
+					}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"data":[{"id":5,"movie_id":1,"movie_title":"Дюна 2","user_id":2,"username":"user","rating":9,"body":"Лучший фильм года","status":"published","created_at":"2024-03-08T20:00:00Z","updated_at":"2024-03-08T20:00:00Z"}],"meta":{"total":3,"limit":1,"offset":0}}`,
+		},
+		{
+			name:       "No Reviews",
+			requestURL: "/movies/1/reviews",
+			mockBehavior: func(s *mock_service.MockReviews) {
+				s.EXPECT().GetReviews(filmoteka.ReviewListParams{MovieId: 1, Status: filmoteka.ReviewPublished, Limit: 20}).
+					Return(filmoteka.ReviewsList{}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"data":[],"meta":{"total":0,"limit":20,"offset":0}}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			reviews := mock_service.NewMockReviews(c)
+			testCase.mockBehavior(reviews)
+
+			services := &service.Service{Reviews: reviews}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /movies/{id}/reviews", handler.handleGetMovieReviews)
+
+			req := httptest.NewRequest("GET", testCase.requestURL, nil)
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
+
+func TestHandler_handleUpdateMyReview(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockReviews)
+
+	rating := 7
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"rating":7}`,
+			mockBehavior: func(s *mock_service.MockReviews) {
+				s.EXPECT().UpdateReview(5, 2, filmoteka.UpdateReview{Rating: &rating}).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+		{
+			name:      "Someone Else's Review",
+			inputBody: `{"rating":7}`,
+			mockBehavior: func(s *mock_service.MockReviews) {
+				s.EXPECT().UpdateReview(5, 2, filmoteka.UpdateReview{Rating: &rating}).Return(filmoteka.ErrReviewNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"type":"about:blank","title":"Not Found","status":404,"detail":"review not found"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			reviews := mock_service.NewMockReviews(c)
+			testCase.mockBehavior(reviews)
+
+			services := &service.Service{Reviews: reviews}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("PATCH /me/reviews/{id}", handler.handleUpdateMyReview)
+
+			req := httptest.NewRequest("PATCH", "/me/reviews/5", bytes.NewBufferString(testCase.inputBody))
+			req = req.WithContext(context.WithValue(req.Context(), userCtx, 2))
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
+
+func TestHandler_handleDeleteReview(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockReviews)
+
+	testTable := []struct {
+		name                string
+		pattern             string
+		handler             func(h *Handler) http.HandlerFunc
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:    "Own Review",
+			pattern: "DELETE /me/reviews/{id}",
+			handler: func(h *Handler) http.HandlerFunc { return h.handleDeleteMyReview },
+			mockBehavior: func(s *mock_service.MockReviews) {
+				s.EXPECT().DeleteReview(5, 2).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+		{
+			name:    "Moderator",
+			pattern: "DELETE /reviews/{id}",
+			handler: func(h *Handler) http.HandlerFunc { return h.handleDeleteReview },
+			mockBehavior: func(s *mock_service.MockReviews) {
+				s.EXPECT().DeleteReview(5, 0).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			reviews := mock_service.NewMockReviews(c)
+			testCase.mockBehavior(reviews)
+
+			services := &service.Service{Reviews: reviews}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc(testCase.pattern, testCase.handler(handler))
+
+			path := strings.TrimPrefix(strings.Replace(testCase.pattern, "{id}", "5", 1), "DELETE ")
+			req := httptest.NewRequest("DELETE", path, nil)
+			req = req.WithContext(context.WithValue(req.Context(), userCtx, 2))
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
+
+func TestHandler_handleSetReviewStatus(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockReviews)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "Hide",
+			inputBody: `{"status":"hidden"}`,
+			mockBehavior: func(s *mock_service.MockReviews) {
+				s.EXPECT().SetReviewStatus(5, filmoteka.ReviewStatus{Status: "hidden"}).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+		{
+			name:      "Unknown Status",
+			inputBody: `{"status":"deleted"}`,
+			mockBehavior: func(s *mock_service.MockReviews) {
+				s.EXPECT().SetReviewStatus(5, filmoteka.ReviewStatus{Status: "deleted"}).
+					Return(filmoteka.ReviewStatus{Status: "deleted"}.Validate())
+			},
+			expectedStatusCode:  422,
+			expectedRequestBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"request has invalid fields","errors":[{"field":"status","message":"must be one of: published, hidden"}]}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			reviews := mock_service.NewMockReviews(c)
+			testCase.mockBehavior(reviews)
+
+			services := &service.Service{Reviews: reviews}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("PUT /reviews/{id}/status", handler.handleSetReviewStatus)
+
+			req := httptest.NewRequest("PUT", "/reviews/5/status", bytes.NewBufferString(testCase.inputBody))
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
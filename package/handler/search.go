@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	filmoteka "vk_restAPI"
+	logger "vk_restAPI/logs"
+)
+
+type fuzzySearchResponse struct {
+	Actors []filmoteka.ActorMatch `json:"actors"`
+	Movies []filmoteka.MovieMatch `json:"movies"`
+}
+
+type autocompleteResponse struct {
+	Data []filmoteka.Suggestion `json:"data"`
+}
+
+// @Summary Fuzzy Search
+// @Security ApiKeyAuth
+// @Tags search
+// @Description Typo tolerant search over actor full names and movie titles, most similar first.
+// @Description similarity is between 0 and 1; names less similar to q than threshold are left out.
+// @Accept json
+// @Produce json
+// @Param q query string true "Search query"
+// @Param threshold query number false "Least similarity of a match" default(0.3) minimum(0) maximum(1)
+// @Param limit query int false "Most actors and most movies to return" default(20) maximum(100)
+// @Success 200 {object} fuzzySearchResponse
+// @Failure 400 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/search [get]
+func (h *Handler) handleFuzzySearch(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Fuzzy Search")
+
+	params, err := parseFuzzySearchParams(r, filmoteka.DefaultListLimit)
+	if err != nil {
+		logger.Log.Error("Invalid search parameters: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	results, err := h.service.FuzzySearch(params)
+	if err != nil {
+		logger.Log.Error("Failed to search: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := fuzzySearchResponse{
+		Actors: orEmpty(results.Actors),
+		Movies: orEmpty(results.Movies),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+// @Summary Autocomplete
+// @Security ApiKeyAuth
+// @Tags search
+// @Description Suggest actor names and movie titles for what has been typed so far, tolerating typos.
+// @Accept json
+// @Produce json
+// @Param q query string true "Text typed so far"
+// @Param threshold query number false "Least similarity of a suggestion" default(0.3) minimum(0) maximum(1)
+// @Param limit query int false "Number of suggestions" default(10) maximum(100)
+// @Success 200 {object} autocompleteResponse
+// @Failure 400 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/search/autocomplete [get]
+func (h *Handler) handleAutocomplete(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Autocomplete")
+
+	params, err := parseFuzzySearchParams(r, filmoteka.DefaultSuggestionLimit)
+	if err != nil {
+		logger.Log.Error("Invalid autocomplete parameters: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	suggestions, err := h.service.Autocomplete(params)
+	if err != nil {
+		logger.Log.Error("Failed to autocomplete: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := autocompleteResponse{Data: orEmpty(suggestions)}
+	//Clients ask again on every keystroke, let them reuse recent answers
+	w.Header().Set("Cache-Control", "private, max-age=60")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+func parseFuzzySearchParams(r *http.Request, defaultLimit int) (filmoteka.FuzzySearchParams, error) {
+	query := r.URL.Query()
+
+	threshold, err := queryFloat(query, "threshold", filmoteka.DefaultSimilarity)
+	if err != nil {
+		return filmoteka.FuzzySearchParams{}, err
+	}
+
+	limit, err := queryInt(query, "limit", defaultLimit)
+	if err != nil {
+		return filmoteka.FuzzySearchParams{}, err
+	}
+
+	params := filmoteka.FuzzySearchParams{
+		Query:     strings.TrimSpace(query.Get("q")),
+		Threshold: threshold,
+		Limit:     limit,
+	}
+	return params, params.Validate()
+}
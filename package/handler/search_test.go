@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	filmoteka "vk_restAPI"
+	"vk_restAPI/package/service"
+	mock_service "vk_restAPI/package/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_handleFuzzySearch(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockSearch)
+
+	testTable := []struct {
+		name                string
+		requestURL          string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:       "OK",
+			requestURL: "/search?q=%D0%A8%D0%B0%D0%BB%D0%B0%D0%BC%D1%8D&threshold=0.4",
+			mockBehavior: func(s *mock_service.MockSearch) {
+				s.EXPECT().FuzzySearch(filmoteka.FuzzySearchParams{Query: "Шаламэ", Threshold: 0.4, Limit: filmoteka.DefaultListLimit}).Return(filmoteka.FuzzySearchResults{
+					Actors: []filmoteka.ActorMatch{{ActorSummary: filmoteka.ActorSummary{Id: 1, FirstName: "Тимоти", LastName: "Шаламе"}, Similarity: 0.71}},
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"actors":[{"id":1,"first_name":"Тимоти","last_name":"Шаламе","similarity":0.71}],"movies":[]}`,
+		},
+		{
+			name:                "Invalid Threshold",
+			requestURL:          "/search?q=dune&threshold=2",
+			mockBehavior:        func(s *mock_service.MockSearch) {},
+			expectedStatusCode:  422,
+			expectedRequestBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"threshold must be greater than 0 and at most 1"}`,
+		},
+		{
+			name:                "Malformed Threshold",
+			requestURL:          "/search?q=dune&threshold=high",
+			mockBehavior:        func(s *mock_service.MockSearch) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid threshold parameter"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			searchService := mock_service.NewMockSearch(c)
+			testCase.mockBehavior(searchService)
+
+			services := &service.Service{Search: searchService}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /search", handler.handleFuzzySearch)
+
+			req := httptest.NewRequest("GET", testCase.requestURL, nil)
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
+
+func TestHandler_handleAutocomplete(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockSearch)
+
+	testTable := []struct {
+		name                string
+		requestURL          string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:       "OK",
+			requestURL: "/search/autocomplete?q=dika",
+			mockBehavior: func(s *mock_service.MockSearch) {
+				s.EXPECT().Autocomplete(filmoteka.FuzzySearchParams{Query: "dika", Threshold: filmoteka.DefaultSimilarity, Limit: filmoteka.DefaultSuggestionLimit}).Return([]filmoteka.Suggestion{
+					{Type: filmoteka.SuggestionActor, Id: 7, Label: "Leonardo DiCaprio", Similarity: 0.8},
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"data":[{"type":"actor","id":7,"label":"Leonardo DiCaprio","similarity":0.8}]}`,
+		},
+		{
+			name:                "Missing Query",
+			requestURL:          "/search/autocomplete",
+			mockBehavior:        func(s *mock_service.MockSearch) {},
+			expectedStatusCode:  422,
+			expectedRequestBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"q is required"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			searchService := mock_service.NewMockSearch(c)
+			testCase.mockBehavior(searchService)
+
+			services := &service.Service{Search: searchService}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /search/autocomplete", handler.handleAutocomplete)
+
+			req := httptest.NewRequest("GET", testCase.requestURL, nil)
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+			if testCase.expectedStatusCode == 200 {
+				assert.Equal(t, "private, max-age=60", w.Header().Get("Cache-Control"))
+			}
+
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	filmoteka "vk_restAPI"
+)
+
+const mergePatchType = "application/merge-patch+json"
+
+// etag renders a resource version as a strong entity tag.
+func etag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// ifMatchVersion reads the version a write is conditional on from If-Match.
+// It is 0, which makes the write unconditional, when the header is absent or
+// "*". Tags that can't be one of ours, weak ones included, never match.
+func ifMatchVersion(r *http.Request) (int, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	unquoted, err := strconv.Unquote(header)
+	if err != nil || !strings.HasPrefix(header, `"`) {
+		return 0, filmoteka.ErrVersionMismatch
+	}
+
+	version, err := strconv.Atoi(unquoted)
+	if err != nil || version <= 0 {
+		return 0, filmoteka.ErrVersionMismatch
+	}
+	return version, nil
+}
+
+// decodeUpdate reads an update body into dst. PATCH takes a JSON merge
+// patch, in which null clears a field, see filmoteka.ApplyMergePatch. The
+// legacy PUT keeps reading plain JSON, where null leaves a field as it is.
+func decodeUpdate(r *http.Request, dst interface{}) error {
+	if r.Method != http.MethodPatch {
+		if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+			return filmoteka.Errorf(errMalformedRequest, "%s", err.Error())
+		}
+		return nil
+	}
+
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != mergePatchType && mediaType != "application/json") {
+			return filmoteka.Errorf(errUnsupportedMediaType, "PATCH takes %s", mergePatchType)
+		}
+	}
+
+	var patch map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil || patch == nil {
+		return filmoteka.Errorf(errMalformedRequest, "merge patch must be a JSON object")
+	}
+
+	return filmoteka.ApplyMergePatch(patch, dst)
+}
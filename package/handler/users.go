@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	filmoteka "vk_restAPI"
+	logger "vk_restAPI/logs"
+)
+
+type setUserRoleInput struct {
+	Role string `json:"role"`
+}
+
+// @Summary Get User Role
+// @Security ApiKeyAuth
+// @Tags users
+// @Description Get the role of a user
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} filmoteka.UserRole
+// @Failure 400 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/users/{id}/role [get]
+func (h *Handler) handleGetUserRole(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Get User Role")
+
+	id, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailed ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	role, err := h.service.Authorization.GetUserRole(id)
+	if err != nil {
+		logger.Log.Error("Failed to get user role:", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(role); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+// @Summary Set User Role
+// @Security ApiKeyAuth
+// @Tags users
+// @Description Change the role of a user
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param input body setUserRoleInput true "viewer, editor or admin"
+// @Success 200 {object} filmoteka.UserRole
+// @Failure 400 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/users/{id}/role [put]
+func (h *Handler) handleSetUserRole(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Set User Role")
+
+	id, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailed ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var input setUserRoleInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logger.Log.Error("Failed to decaode request body:", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !filmoteka.ValidRole(input.Role) {
+		writeError(w, filmoteka.Errorf(filmoteka.ErrValidation, "role must be one of: %s", strings.Join(filmoteka.Roles, ", ")))
+		return
+	}
+
+	// An admin demoting themselves could leave nobody able to manage roles.
+	if userId, _ := getUserId(r); userId == id && input.Role != filmoteka.RoleAdmin {
+		writeError(w, filmoteka.Errorf(filmoteka.ErrValidation, "you can't revoke your own admin role"))
+		return
+	}
+
+	err = h.service.Authorization.SetUserRole(id, input.Role)
+	if err != nil {
+		logger.Log.Error("Failed to set user role:", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(filmoteka.UserRole{UserId: id, Role: input.Role}); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
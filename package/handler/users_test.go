@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	filmoteka "vk_restAPI"
+	"vk_restAPI/package/service"
+	mock_service "vk_restAPI/package/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_handleGetUserRole(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAuthorization)
+
+	testTable := []struct {
+		name                string
+		requestURL          string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:       "OK",
+			requestURL: "/api/users/2/role",
+			mockBehavior: func(s *mock_service.MockAuthorization) {
+				s.EXPECT().GetUserRole(2).Return(filmoteka.UserRole{UserId: 2, Role: "editor"}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"user_id":2,"role":"editor"}`,
+		},
+		{
+			name:       "Not Found",
+			requestURL: "/api/users/9/role",
+			mockBehavior: func(s *mock_service.MockAuthorization) {
+				s.EXPECT().GetUserRole(9).Return(filmoteka.UserRole{}, filmoteka.ErrUserNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"type":"about:blank","title":"Not Found","status":404,"detail":"user not found"}`,
+		},
+		{
+			name:                "Invalid ID",
+			requestURL:          "/api/users/abc/role",
+			mockBehavior:        func(s *mock_service.MockAuthorization) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid id parameter"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockAuthorization(c)
+			testCase.mockBehavior(auth)
+
+			services := &service.Service{Authorization: auth}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/users/{id}/role", handler.handleGetUserRole)
+
+			req := httptest.NewRequest("GET", testCase.requestURL, nil)
+			req = req.WithContext(context.WithValue(req.Context(), userCtx, 1))
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
+
+func TestHandler_handleSetUserRole(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAuthorization)
+
+	testTable := []struct {
+		name                string
+		requestURL          string
+		inputBody           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:       "Grant Admin",
+			requestURL: "/api/users/2/role",
+			inputBody:  `{"role":"admin"}`,
+			mockBehavior: func(s *mock_service.MockAuthorization) {
+				s.EXPECT().SetUserRole(2, "admin").Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"user_id":2,"role":"admin"}`,
+		},
+		{
+			name:       "Change Role",
+			requestURL: "/api/users/2/role",
+			inputBody:  `{"role":"editor"}`,
+			mockBehavior: func(s *mock_service.MockAuthorization) {
+				s.EXPECT().SetUserRole(2, "editor").Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"user_id":2,"role":"editor"}`,
+		},
+		{
+			name:                "Unknown Role",
+			requestURL:          "/api/users/2/role",
+			inputBody:           `{"role":"root"}`,
+			mockBehavior:        func(s *mock_service.MockAuthorization) {},
+			expectedStatusCode:  422,
+			expectedRequestBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"role must be one of: viewer, editor, admin"}`,
+		},
+		{
+			name:                "Revoke Own Admin",
+			requestURL:          "/api/users/1/role",
+			inputBody:           `{"role":"viewer"}`,
+			mockBehavior:        func(s *mock_service.MockAuthorization) {},
+			expectedStatusCode:  422,
+			expectedRequestBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"you can't revoke your own admin role"}`,
+		},
+		{
+			name:       "Not Found",
+			requestURL: "/api/users/9/role",
+			inputBody:  `{"role":"admin"}`,
+			mockBehavior: func(s *mock_service.MockAuthorization) {
+				s.EXPECT().SetUserRole(9, "admin").Return(filmoteka.ErrUserNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"type":"about:blank","title":"Not Found","status":404,"detail":"user not found"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockAuthorization(c)
+			testCase.mockBehavior(auth)
+
+			services := &service.Service{Authorization: auth}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("PUT /api/users/{id}/role", handler.handleSetUserRole)
+
+			req := httptest.NewRequest("PUT", testCase.requestURL, bytes.NewBufferString(testCase.inputBody))
+			req = req.WithContext(context.WithValue(req.Context(), userCtx, 1))
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
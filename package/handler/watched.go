@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	filmoteka "vk_restAPI"
+	logger "vk_restAPI/logs"
+)
+
+type getWatchedResponse struct {
+	Data []filmoteka.WatchedMovie `json:"data"`
+	Meta *listMeta                `json:"meta,omitempty"`
+}
+
+// @Summary Mark Movie Watched
+// @Security ApiKeyAuth
+// @Tags history
+// @Description Add a movie to the caller's history with the date it was watched, today by default, and an optional personal rating from 1 to 10. Marking a watched movie again replaces its date and rating.
+// @Accept json
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param input body filmoteka.WatchedInput true "Date and personal rating"
+// @Success 200 {object} StatusResponse
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/me/watched/{id} [put]
+func (h *Handler) handleMarkWatched(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Mark Watched")
+
+	movieId, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId, err := getUserId(r)
+	if err != nil {
+		logger.Log.Error("Failed to get user id: ", err.Error())
+		NewErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var input filmoteka.WatchedInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logger.Log.Error("Failed to decode request body: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.service.Watched.MarkWatched(userId, movieId, input); err != nil {
+		logger.Log.Error("Failed to mark movie watched: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := StatusResponse{Status: "ok"}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+// @Summary Unmark Movie Watched
+// @Security ApiKeyAuth
+// @Tags history
+// @Description Remove a movie from the caller's history.
+// @Accept json
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Success 200 {object} StatusResponse
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/me/watched/{id} [delete]
+func (h *Handler) handleUnmarkWatched(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Unmark Watched")
+
+	movieId, err := pathID(r)
+	if err != nil {
+		logger.Log.Error("Invailid ID parameter: ", err.Error())
+		NewErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId, err := getUserId(r)
+	if err != nil {
+		logger.Log.Error("Failed to get user id: ", err.Error())
+		NewErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err := h.service.Watched.UnmarkWatched(userId, movieId); err != nil {
+		logger.Log.Error("Failed to unmark movie watched: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := StatusResponse{Status: "ok"}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+// @Summary Get Watched History
+// @Security ApiKeyAuth
+// @Tags history
+// @Description Get a page of the caller's history, latest watched first. watched_after and watched_before are inclusive; q and genre filter like in the movie list.
+// @Accept json
+// @Produce json
+// @Param watched_after query string false "Watched on or after, YYYY-MM-DD"
+// @Param watched_before query string false "Watched on or before, YYYY-MM-DD"
+// @Param min_rating query int false "Least personal rating"
+// @Param q query string false "Fragment of the title"
+// @Param genre query []int false "Genre ID, may be repeated" collectionFormat(multi)
+// @Param limit query int false "Page size" default(20) maximum(100)
+// @Param offset query int false "Number of movies to skip" default(0)
+// @Success 200 {object} getWatchedResponse
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 422 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/me/watched [get]
+func (h *Handler) handleGetWatched(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Get Watched")
+
+	userId, err := getUserId(r)
+	if err != nil {
+		logger.Log.Error("Failed to get user id: ", err.Error())
+		NewErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	params, err := parseWatchedListParams(r)
+	if err != nil {
+		logger.Log.Error("Invalid list parameters: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	list, err := h.service.Watched.GetWatched(userId, params)
+	if err != nil {
+		logger.Log.Error("Failed to Get Watched: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	response := getWatchedResponse{
+		Data: orEmpty(list.Movies),
+		Meta: &listMeta{
+			Total:  list.Total,
+			Limit:  params.Limit,
+			Offset: params.Offset,
+		},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+func parseWatchedListParams(r *http.Request) (filmoteka.WatchedListParams, error) {
+	query := r.URL.Query()
+
+	params := filmoteka.WatchedListParams{
+		WatchedAfter:  query.Get("watched_after"),
+		WatchedBefore: query.Get("watched_before"),
+		Title:         strings.TrimSpace(query.Get("q")),
+	}
+
+	var err error
+	if params.MinRating, err = queryInt(query, "min_rating", 0); err != nil {
+		return params, err
+	}
+
+	if params.GenreIds, err = queryInts(query, "genre"); err != nil {
+		return params, err
+	}
+
+	if params.Limit, err = queryInt(query, "limit", filmoteka.DefaultListLimit); err != nil {
+		return params, err
+	}
+
+	if params.Offset, err = queryInt(query, "offset", 0); err != nil {
+		return params, err
+	}
+
+	return params, nil
+}
+
+// @Summary Get Watched Stats
+// @Security ApiKeyAuth
+// @Tags history
+// @Description Sum the caller's history up: the number of movies watched, the average personal rating, movies watched per year and the actors seen in the most watched movies.
+// @Accept json
+// @Produce json
+// @Success 200 {object} filmoteka.WatchedStats
+// @Failure 401 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/me/watched/stats [get]
+func (h *Handler) handleGetWatchedStats(w http.ResponseWriter, r *http.Request) {
+
+	logger.Log.Info("Handling Get Watched Stats")
+
+	userId, err := getUserId(r)
+	if err != nil {
+		logger.Log.Error("Failed to get user id: ", err.Error())
+		NewErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	stats, err := h.service.Watched.GetWatchedStats(userId)
+	if err != nil {
+		logger.Log.Error("Failed to Get Watched Stats: ", err.Error())
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(stats); err != nil {
+		logger.Log.Error("Failed to encode response", err.Error())
+	}
+}
+
+// annotateMovies fills in the caller's state of every movie when the request
+// has with_state=true. The states of the whole page are read with one query.
+func (h *Handler) annotateMovies(r *http.Request, movies []*filmoteka.MoviesWithActors) error {
+	withState, err := queryBool(r.URL.Query(), "with_state")
+	if err != nil || !withState {
+		return err
+	}
+
+	userId, err := getUserId(r)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]int, len(movies))
+	for i, movie := range movies {
+		ids[i] = movie.Id
+	}
+
+	states, err := h.service.Watched.GetMovieStates(userId, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, movie := range movies {
+		state := states[movie.Id]
+		movie.State = &state
+	}
+	return nil
+}
@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	filmoteka "vk_restAPI"
+	"vk_restAPI/package/service"
+	mock_service "vk_restAPI/package/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_handleMarkWatched(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockWatched)
+
+	testTable := []struct {
+		name                string
+		requestURL          string
+		inputBody           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:       "OK",
+			requestURL: "/me/watched/1",
+			inputBody:  `{"watched_on":"2024-03-08", "personal_rating":9}`,
+			mockBehavior: func(s *mock_service.MockWatched) {
+				s.EXPECT().MarkWatched(2, 1, filmoteka.WatchedInput{WatchedOn: "2024-03-08", PersonalRating: 9}).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+		{
+			name:       "No Movie",
+			requestURL: "/me/watched/100",
+			inputBody:  `{}`,
+			mockBehavior: func(s *mock_service.MockWatched) {
+				s.EXPECT().MarkWatched(2, 100, filmoteka.WatchedInput{}).Return(filmoteka.ErrMovieNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"type":"about:blank","title":"Not Found","status":404,"detail":"movie not found"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			watched := mock_service.NewMockWatched(c)
+			testCase.mockBehavior(watched)
+
+			services := &service.Service{Watched: watched}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("PUT /me/watched/{id}", handler.handleMarkWatched)
+
+			req := httptest.NewRequest("PUT", testCase.requestURL, bytes.NewBufferString(testCase.inputBody))
+			req = req.WithContext(context.WithValue(req.Context(), userCtx, 2))
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
+
+func TestHandler_handleGetWatched(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockWatched)
+
+	testTable := []struct {
+		name                string
+		requestURL          string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:       "Filters",
+			requestURL: "/me/watched?watched_after=2024-01-01&watched_before=2024-12-31&min_rating=8&q=%20дюна%20&genre=1&genre=3",
+			mockBehavior: func(s *mock_service.MockWatched) {
+				s.EXPECT().GetWatched(2, filmoteka.WatchedListParams{
+					WatchedAfter:  "2024-01-01",
+					WatchedBefore: "2024-12-31",
+					MinRating:     8,
+					Title:         "дюна",
+					GenreIds:      []int{1, 3},
+					Limit:         20,
+				}).Return(filmoteka.WatchedList{
+					Movies: []filmoteka.WatchedMovie{{
+						MoviesWithActors: filmoteka.MoviesWithActors{Id: 1, Title: "Дюна 2", ReleaseDate: "2024-02-29", Rating: 9, Actors: filmoteka.Cast{}},
+						WatchedOn:        "2024-03-08",
+						PersonalRating:   9,
+					}},
+					Total: 1,
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"data":[{"id":1,"title":"Дюна 2","original_title":"","description":"","release_date":"2024-02-29","rating":9,"country":"","runtime":0,"age_rating":"","genres":null,"actors":[],"crew":null,"user_rating":{"average":0,"votes":0,"distribution":null},"watched_on":"2024-03-08","personal_rating":9}],"meta":{"total":1,"limit":20,"offset":0}}`,
+		},
+		{
+			name:                "Malformed Genre",
+			requestURL:          "/me/watched?genre=drama",
+			mockBehavior:        func(s *mock_service.MockWatched) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid genre parameter"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			watched := mock_service.NewMockWatched(c)
+			testCase.mockBehavior(watched)
+
+			services := &service.Service{Watched: watched}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /me/watched", handler.handleGetWatched)
+
+			req := httptest.NewRequest("GET", testCase.requestURL, nil)
+			req = req.WithContext(context.WithValue(req.Context(), userCtx, 2))
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			actual := strings.TrimSpace(w.Body.String())
+			expected := strings.TrimSpace(testCase.expectedRequestBody)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+}
+
+func TestHandler_handleGetWatchedStats(t *testing.T) {
+	//Init Deps
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	watched := mock_service.NewMockWatched(c)
+	watched.EXPECT().GetWatchedStats(2).Return(filmoteka.WatchedStats{
+		Total:          3,
+		AverageRating:  8.5,
+		PerYear:        filmoteka.WatchedYears{{Year: 2024, Movies: 2}, {Year: 2023, Movies: 1}},
+		FavoriteActors: filmoteka.FavoriteActors{{ActorSummary: filmoteka.ActorSummary{Id: 2, FirstName: "Тимоти", LastName: "Шаламе"}, Movies: 2}},
+	}, nil)
+
+	services := &service.Service{Watched: watched}
+	handler := NewHandler(services)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /me/watched/stats", handler.handleGetWatchedStats)
+
+	req := httptest.NewRequest("GET", "/me/watched/stats", nil)
+	req = req.WithContext(context.WithValue(req.Context(), userCtx, 2))
+
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	//Asserts
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"total":3,"average_rating":8.5,"per_year":[{"year":2024,"movies":2},{"year":2023,"movies":1}],"favorite_actors":[{"id":2,"first_name":"Тимоти","last_name":"Шаламе","movies":2}]}`,
+		strings.TrimSpace(w.Body.String()))
+}
+
+func TestHandler_annotateMovies(t *testing.T) {
+	type mockBehavior func(m *mock_service.MockMoviesWithActors, s *mock_service.MockWatched)
+
+	params := filmoteka.MovieListParams{PageParams: filmoteka.PageParams{
+		Sort: filmoteka.SortByRating, Order: filmoteka.OrderDesc, Limit: filmoteka.DefaultListLimit,
+	}}
+	movies := func() filmoteka.MoviesList {
+		return filmoteka.MoviesList{Movies: []filmoteka.MoviesWithActors{{Id: 1}, {Id: 3}}, Total: 2}
+	}
+
+	testTable := []struct {
+		name               string
+		requestURL         string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedStates     []string
+	}{
+		{
+			name:       "One Query For The Page",
+			requestURL: "/movies?with_state=true",
+			mockBehavior: func(m *mock_service.MockMoviesWithActors, s *mock_service.MockWatched) {
+				m.EXPECT().GetMovies(params).Return(movies(), nil)
+				s.EXPECT().GetMovieStates(2, []int{1, 3}).Return(map[int]filmoteka.MovieState{
+					1: {Watched: true},
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedStates:     []string{`"state":{"watched":true,"in_watchlist":false}`, `"state":{"watched":false,"in_watchlist":false}`},
+		},
+		{
+			name:       "Not Asked For",
+			requestURL: "/movies",
+			mockBehavior: func(m *mock_service.MockMoviesWithActors, s *mock_service.MockWatched) {
+				m.EXPECT().GetMovies(params).Return(movies(), nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:       "Malformed with_state",
+			requestURL: "/movies?with_state=maybe",
+			mockBehavior: func(m *mock_service.MockMoviesWithActors, s *mock_service.MockWatched) {
+				m.EXPECT().GetMovies(params).Return(movies(), nil)
+			},
+			expectedStatusCode: 400,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			movieService := mock_service.NewMockMoviesWithActors(c)
+			watched := mock_service.NewMockWatched(c)
+			testCase.mockBehavior(movieService, watched)
+
+			services := &service.Service{MoviesWithActors: movieService, Watched: watched}
+			handler := NewHandler(services)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /movies", handler.handleGetAllMovies)
+
+			req := httptest.NewRequest("GET", testCase.requestURL, nil)
+			req = req.WithContext(context.WithValue(req.Context(), userCtx, 2))
+
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			//Asserts
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, len(testCase.expectedStates), strings.Count(w.Body.String(), `"state"`))
+			for _, state := range testCase.expectedStates {
+				assert.Contains(t, w.Body.String(), state)
+			}
+
+		})
+	}
+}
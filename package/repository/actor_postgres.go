@@ -4,45 +4,104 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"log"
 	"strings"
 	filmoteka "vk_restAPI"
-
-	"github.com/jmoiron/sqlx"
 )
 
 type ActorPostgres struct {
-	db *sqlx.DB
+	db Executor
 }
 
-func NewActorPostgres(db *sqlx.DB) *ActorPostgres {
+func NewActorPostgres(db Executor) *ActorPostgres {
 	return &ActorPostgres{db: db}
 }
 
 func (a *ActorPostgres) CreateActor(actor filmoteka.Actors) (int, error) {
 	var id int
 
-	var exisitngID int
+	err := withTx(a.db, func(tx Executor) error {
+		var exisitngID int
 
-	query := fmt.Sprintf("SELECT id FROM %s WHERE first_name=$1 AND last_name=$2", actorsTable)
-	row := a.db.QueryRow(query, actor.FirstName, actor.LastName)
-	if err := row.Scan(&exisitngID); err == nil {
-		return exisitngID, errors.New("actor with the same name already exists")
-	} else if err != sql.ErrNoRows {
-		return 0, err
-	}
+		//Actors are people too, so a crew member with the same name counts
+		query := fmt.Sprintf("SELECT id FROM %s WHERE first_name=$1 AND last_name=$2", peopleTable)
+		row := tx.QueryRow(query, actor.FirstName, actor.LastName)
+		if err := row.Scan(&exisitngID); err == nil {
+			id = exisitngID
+			return filmoteka.ErrActorExists
+		} else if err != sql.ErrNoRows {
+			return err
+		}
+
+		query = fmt.Sprintf("INSERT INTO %s (first_name, last_name, gender, date_of_birth) VALUES ($1, $2, $3, $4) RETURNING id", actorsTable)
+		row = tx.QueryRow(query, actor.FirstName, actor.LastName, actor.Gender, actor.DateOfBirth)
+		return dbError(row.Scan(&id))
+	})
 
-	query = fmt.Sprintf("INSERT INTO %s (first_name, last_name, gender, date_of_birth) VALUES ($1, $2, $3, $4) RETURNING id", actorsTable)
-	row = a.db.QueryRow(query, actor.FirstName, actor.LastName, actor.Gender, actor.DateOfBirth)
+	return id, err
+}
+
+// actorSortKeys whitelists the columns an actor list can be ordered by.
+// Names are ordered by last name first.
+var actorSortKeys = map[string]sortKey{
+	filmoteka.SortById:          {column: "a.id", cast: "int"},
+	filmoteka.SortByName:        {column: "(a.last_name || ' ' || a.first_name)", cast: "text"},
+	filmoteka.SortByDateOfBirth: {column: "a.date_of_birth", cast: "date"},
+}
 
-	if err := row.Scan(&id); err != nil {
-		return 0, err
+func actorSortValue(sort string) func(filmoteka.ActorsWithMovies) (string, int) {
+	return func(actor filmoteka.ActorsWithMovies) (string, int) {
+		switch sort {
+		case filmoteka.SortByName:
+			return actor.LastName + " " + actor.FirstName, actor.Id
+		case filmoteka.SortByDateOfBirth:
+			return actor.DateOfBirth, actor.Id
+		}
+		return "", actor.Id
 	}
-	return id, nil
 }
 
-func (a *ActorPostgres) GetActors() ([]filmoteka.ActorsWithMovies, error) {
-	var actors []filmoteka.ActorsWithMovies
+// GetActors returns a page of actors with their filmographies. Those are
+// built by a subquery per actor rather than GROUP BY: actors is a view, and
+// Postgres only takes ungrouped columns when grouping by a table's key.
+func (a *ActorPostgres) GetActors(params filmoteka.ActorListParams) (filmoteka.ActorsList, error) {
+	var list filmoteka.ActorsList
+
+	keys, err := newKeyset(actorSortKeys, "a.id", params.Sort, params.Order)
+	if err != nil {
+		return list, err
+	}
+
+	var from cursor
+	conditions, args := actorFilters(params)
+
+	//The total counts every actor matching the filters, not just this page
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s a", actorsTable)
+	if len(conditions) > 0 {
+		countQuery += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	countArgs := append([]interface{}{}, args...)
+
+	if params.Cursor != "" {
+		if from, err = decodeCursor(params.Cursor, params.Sort, params.Order); err != nil {
+			return list, err
+		}
+
+		seek, seekArgs := keys.seek(from, len(args)+1)
+		conditions = append(conditions, seek)
+		args = append(args, seekArgs...)
+		params.Offset = 0
+	}
+
+	if err := a.db.Get(&list.Total, countQuery, countArgs...); err != nil {
+		return list, err
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	args = append(args, params.Limit+1, params.Offset)
 
 	query := fmt.Sprintf(`
 		SELECT 
@@ -51,24 +110,71 @@ func (a *ActorPostgres) GetActors() ([]filmoteka.ActorsWithMovies, error) {
 			a.last_name, 
 			a.gender, 
 			TO_CHAR(a.date_of_birth, 'YYYY-MM-DD') AS date_of_birth, 
-			array_agg(m.title) AS movies
+			COALESCE((
+				SELECT json_agg(json_build_object(
+					'id', m.id, 'title', m.title, 'release_date', TO_CHAR(m.release_date, 'YYYY-MM-DD'),
+					'character_name', ma.character_name, 'billing_order', ma.billing_order, 'role_type', ma.role_type
+				) ORDER BY m.release_date, ma.billing_order)
+				FROM %s ma
+				JOIN %s m ON ma.movie_id = m.id
+				WHERE ma.actor_id = a.id
+			), '[]') AS movies
 		FROM 
 			%s a
-		LEFT JOIN 
-			%s ma ON a.id = ma.actor_id
-		LEFT JOIN 
-			%s m ON ma.movie_id = m.id
-		GROUP BY 
-			a.id
-	`, actorsTable, moviesActorsTable, moviesTable)
-
-	err := a.db.Select(&actors, query)
-	if err != nil {
-		return nil, err
+		%s
+		ORDER BY 
+			%s
+		LIMIT $%d OFFSET $%d
+	`, moviesActorsTable, moviesTable, actorsTable, where, keys.orderBy(from.Backward), len(args)-1, len(args))
+
+	var actors []filmoteka.ActorsWithMovies
+	if err := a.db.Select(&actors, query, args...); err != nil {
+		return list, err
 	}
 
-	return actors, nil
+	started := params.Cursor != "" || params.Offset > 0
+	list.Actors, list.NextCursor, list.PrevCursor = buildPage(keys, actors, params.Limit, from, started, actorSortValue(params.Sort))
 
+	return list, nil
+}
+
+// actorFilters turns the list filters into predicates on actors a.
+// Placeholders are numbered from 1 in the order of the returned args. The
+// name fragment is matched against the same expression as the trigram
+// index, which also serves ILIKE.
+func actorFilters(params filmoteka.ActorListParams) ([]string, []interface{}) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+
+	if params.Name != "" {
+		args = append(args, likePattern(params.Name))
+		conditions = append(conditions, fmt.Sprintf(`%s ILIKE $%d ESCAPE '\'`, actorFullName, len(args)))
+	}
+
+	if params.Gender != "" {
+		args = append(args, params.Gender)
+		conditions = append(conditions, fmt.Sprintf("a.gender = $%d", len(args)))
+	}
+
+	if params.BornAfter != "" {
+		args = append(args, params.BornAfter)
+		conditions = append(conditions, fmt.Sprintf("a.date_of_birth >= $%d", len(args)))
+	}
+
+	if params.BornBefore != "" {
+		args = append(args, params.BornBefore)
+		conditions = append(conditions, fmt.Sprintf("a.date_of_birth <= $%d", len(args)))
+	}
+
+	if params.HasMovies != nil {
+		exists := fmt.Sprintf("EXISTS (SELECT 1 FROM %s fma WHERE fma.actor_id = a.id)", moviesActorsTable)
+		if !*params.HasMovies {
+			exists = "NOT " + exists
+		}
+		conditions = append(conditions, exists)
+	}
+
+	return conditions, args
 }
 
 func (a *ActorPostgres) GetActorById(actorId int) (filmoteka.ActorsWithMovies, error) {
@@ -81,33 +187,94 @@ func (a *ActorPostgres) GetActorById(actorId int) (filmoteka.ActorsWithMovies, e
 			a.last_name, 
 			a.gender, 
 			TO_CHAR(a.date_of_birth, 'YYYY-MM-DD') AS date_of_birth, 
-			array_agg(m.title) AS movies
+			a.version, 
+			COALESCE((
+				SELECT json_agg(json_build_object(
+					'id', m.id, 'title', m.title, 'release_date', TO_CHAR(m.release_date, 'YYYY-MM-DD'),
+					'character_name', ma.character_name, 'billing_order', ma.billing_order, 'role_type', ma.role_type
+				) ORDER BY m.release_date, ma.billing_order)
+				FROM %s ma
+				JOIN %s m ON ma.movie_id = m.id
+				WHERE ma.actor_id = a.id
+			), '[]') AS movies
 		FROM 
 			%s a
-		LEFT JOIN 
-			%s ma ON a.id = ma.actor_id
-		LEFT JOIN 
-			%s m ON ma.movie_id = m.id
 		WHERE 
 			a.id=$1
-		GROUP BY 
-			a.id
-	`, actorsTable, moviesActorsTable, moviesTable)
+	`, moviesActorsTable, moviesTable, actorsTable)
 
 	err := a.db.Get(&actor, query, actorId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return actor, filmoteka.ErrActorNotFound
+	}
 	return actor, err
 
 }
 
-func (a *ActorPostgres) DeleteActor(actorId int) error {
+// DeleteActor removes an actor. In restrict mode an actor who still
+// appears in movies, in the cast or in the crew, is kept and ActorInUseError
+// lists those movies; in cascade mode the actor is unlinked from them in the
+// same transaction.
+func (a *ActorPostgres) DeleteActor(actorId int, mode string) error {
+	return withTx(a.db, func(tx Executor) error {
+		//Unlinking the cast may drop a person out of the actors view, so
+		//check it before and delete the person themselves
+		var exists bool
+		query := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE id=$1)", actorsTable)
+		if err := tx.Get(&exists, query, actorId); err != nil {
+			return err
+		}
+		if !exists {
+			return filmoteka.ErrActorNotFound
+		}
+
+		if mode == filmoteka.DeleteModeCascade {
+			query = fmt.Sprintf("DELETE FROM %s WHERE actor_id=$1", moviesActorsTable)
+			if _, err := tx.Exec(query, actorId); err != nil {
+				return err
+			}
+
+			query = fmt.Sprintf("DELETE FROM %s WHERE person_id=$1", moviesCrewTable)
+			if _, err := tx.Exec(query, actorId); err != nil {
+				return err
+			}
+		} else {
+			var movies []filmoteka.MovieSummary
 
-	qurey := fmt.Sprintf("DELETE FROM %s WHERE id=$1", actorsTable)
-	_, err := a.db.Exec(qurey, actorId)
+			query = fmt.Sprintf(`
+				SELECT 
+					m.id, 
+					m.title, 
+					TO_CHAR(m.release_date, 'YYYY-MM-DD') AS release_date
+				FROM 
+					%s m
+				WHERE 
+					m.id IN (
+						SELECT movie_id FROM %s WHERE actor_id=$1
+						UNION
+						SELECT movie_id FROM %s WHERE person_id=$1
+					)
+				ORDER BY 
+					m.release_date
+			`, moviesTable, moviesActorsTable, moviesCrewTable)
+			if err := tx.Select(&movies, query, actorId); err != nil {
+				return err
+			}
 
-	return err
+			if len(movies) > 0 {
+				return &filmoteka.ActorInUseError{ActorId: actorId, Movies: movies}
+			}
+		}
+
+		query = fmt.Sprintf("DELETE FROM %s WHERE id=$1", peopleTable)
+		res, err := tx.Exec(query, actorId)
+		return affectOne(res, err, filmoteka.ErrActorNotFound)
+	})
 }
 
-func (a *ActorPostgres) UpdateActor(actorId int, input filmoteka.UpdateActors) error {
+// UpdateActor updates the given columns of an actor and bumps its version,
+// see updateVersioned.
+func (a *ActorPostgres) UpdateActor(actorId int, input filmoteka.UpdateActors, version int) (int, error) {
 	setValues := make([]string, 0)
 	args := make([]interface{}, 0)
 	argId := 1
@@ -133,17 +300,7 @@ func (a *ActorPostgres) UpdateActor(actorId int, input filmoteka.UpdateActors) e
 	if input.DateOfBirth != nil {
 		setValues = append(setValues, fmt.Sprintf("date_of_birth=$%d", argId))
 		args = append(args, *input.DateOfBirth)
-		argId++
 	}
-	setQuery := strings.Join(setValues, ", ")
-
-	query := fmt.Sprintf("UPDATE %s SET %s WHERE id=$%d", actorsTable, setQuery, argId)
-	args = append(args, actorId)
-
-	log.Printf("updateQuerry: %s", query)
-	log.Printf("args :%v", args)
-
-	_, err := a.db.Exec(query, args...)
-	return err
 
+	return updateVersioned(a.db, actorsTable, actorId, version, setValues, args, filmoteka.ErrActorNotFound)
 }
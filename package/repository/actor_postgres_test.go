@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"regexp"
 	"testing"
 	filmoteka "vk_restAPI"
 
@@ -44,8 +45,9 @@ func TestActorPostgres_CreateActor(t *testing.T) {
 			},
 
 			mockBehaivior: func(args args) {
+				mock.ExpectBegin()
 
-				mock.ExpectQuery("SELECT id FROM actors WHERE first_name=\\$1 AND last_name=\\$2").
+				mock.ExpectQuery("SELECT id FROM people WHERE first_name=\\$1 AND last_name=\\$2").
 					WithArgs("Denis", "Maksimov").
 					WillReturnRows(sqlmock.NewRows([]string{"id"}))
 
@@ -53,7 +55,54 @@ func TestActorPostgres_CreateActor(t *testing.T) {
 
 				mock.ExpectQuery("INSERT INTO actors").
 					WithArgs(args.atctor.FirstName, args.atctor.LastName, args.atctor.Gender, args.atctor.DateOfBirth).WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Duplicate name",
+			args: args{
+				atctor: filmoteka.Actors{
+					Id:          1,
+					FirstName:   "Denis",
+					LastName:    "Maksimov",
+					Gender:      "male",
+					DateOfBirth: "1996-06-20",
+				},
 			},
+
+			mockBehaivior: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT id FROM people WHERE first_name=\\$1 AND last_name=\\$2").
+					WithArgs("Denis", "Maksimov").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "Duplicate crew member",
+			args: args{
+				atctor: filmoteka.Actors{
+					Id:        14,
+					FirstName: "Дени",
+					LastName:  "Вильнёв",
+					Gender:    "male",
+				},
+			},
+
+			mockBehaivior: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT id FROM people WHERE first_name=\\$1 AND last_name=\\$2").
+					WithArgs("Дени", "Вильнёв").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(14))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
 		},
 	}
 	for _, testCase := range testTable {
@@ -62,11 +111,13 @@ func TestActorPostgres_CreateActor(t *testing.T) {
 
 			got, err := actorRepo.CreateActor(testCase.args.atctor)
 			if testCase.wantErr {
-				assert.Error(t, err)
+				assert.ErrorIs(t, err, filmoteka.ErrActorExists)
+				assert.Equal(t, testCase.args.atctor.Id, got)
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, testCase.args.atctor.Id, got)
 			}
+			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 
@@ -84,7 +135,7 @@ func TestActorPostgres_GetActorById(t *testing.T) {
 
 	//Ecpected result
 	rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "gender", "date_of_birth", "movies"}).
-		AddRow(1, "Denis", "Maksimov", "Male", "1996-06-20", "Movie 1, Movie 2")
+		AddRow(1, "Denis", "Maksimov", "Male", "1996-06-20", `[{"id":1,"title":"Movie 1","release_date":"2000-01-01"},{"id":2,"title":"Movie 2","release_date":"2001-01-01"}]`)
 
 	mock.ExpectQuery("^SELECT (.+) FROM actors a (.+)$").WithArgs(1).WillReturnRows(rows)
 
@@ -102,7 +153,7 @@ func TestActorPostgres_GetActorById(t *testing.T) {
 	assert.Equal(t, "Maksimov", actor.LastName)
 	assert.Equal(t, "Male", actor.Gender)
 	assert.Equal(t, "1996-06-20", actor.DateOfBirth)
-	assert.Equal(t, "Movie 1, Movie 2", actor.Movies)
+	assert.Equal(t, filmoteka.Appearances{{MovieSummary: filmoteka.MovieSummary{Id: 1, Title: "Movie 1", ReleaseDate: "2000-01-01"}}, {MovieSummary: filmoteka.MovieSummary{Id: 2, Title: "Movie 2", ReleaseDate: "2001-01-01"}}}, actor.Movies)
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -117,13 +168,19 @@ func TestActorPostgres_GetActors(t *testing.T) {
 
 	repo := NewActorPostgres(sqlx.NewDb(mockDB, "sqlmock"))
 
-	//Ecpected result
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM actors")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	//Ecpected result, the second row is the look-ahead one
 	rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "gender", "date_of_birth", "movies"}).
-		AddRow(1, "Denis", "Maksimov", "Male", "1996-06-20", "Movie 1, Movie 2")
+		AddRow(1, "Denis", "Maksimov", "Male", "1996-06-20", `[{"id":1,"title":"Movie 1","release_date":"2000-01-01"},{"id":2,"title":"Movie 2","release_date":"2001-01-01"}]`).
+		AddRow(2, "Ivan", "Ivanov", "Male", "1990-01-01", `[{"id":3,"title":"Movie 3","release_date":"2002-01-01"}]`)
 
-	mock.ExpectQuery("^SELECT (.+) FROM actors a (.+)$").WillReturnRows(rows)
+	mock.ExpectQuery("^SELECT (.+) FROM actors a ORDER BY a.id ASC LIMIT \\$1 OFFSET \\$2$").
+		WithArgs(2, 0).WillReturnRows(rows)
 
-	actors, err := repo.GetActors()
+	params := filmoteka.ActorListParams{PageParams: filmoteka.PageParams{Sort: "id", Order: "asc", Limit: 1}}
+	list, err := repo.GetActors(params)
 
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
@@ -132,35 +189,193 @@ func TestActorPostgres_GetActors(t *testing.T) {
 
 	assert.NoError(t, err)
 
-	assert.Equal(t, 1, len(actors))
-	assert.Equal(t, "Denis", actors[0].FirstName)
-	assert.Equal(t, "Maksimov", actors[0].LastName)
-	assert.Equal(t, "Male", actors[0].Gender)
-	assert.Equal(t, "1996-06-20", actors[0].DateOfBirth)
-	assert.Equal(t, "Movie 1, Movie 2", actors[0].Movies)
+	assert.Equal(t, 2, list.Total)
+	assert.Equal(t, 1, len(list.Actors))
+	assert.Equal(t, "Denis", list.Actors[0].FirstName)
+	assert.Equal(t, "Maksimov", list.Actors[0].LastName)
+	assert.Equal(t, "Male", list.Actors[0].Gender)
+	assert.Equal(t, "1996-06-20", list.Actors[0].DateOfBirth)
+	assert.Equal(t, filmoteka.Appearances{{MovieSummary: filmoteka.MovieSummary{Id: 1, Title: "Movie 1", ReleaseDate: "2000-01-01"}}, {MovieSummary: filmoteka.MovieSummary{Id: 2, Title: "Movie 2", ReleaseDate: "2001-01-01"}}}, list.Actors[0].Movies)
+	assert.NotEmpty(t, list.NextCursor)
+	assert.Empty(t, list.PrevCursor)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	//Following next_cursor seeks past the last actor of the first page
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM actors")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	rows = sqlmock.NewRows([]string{"id", "first_name", "last_name", "gender", "date_of_birth", "movies"}).
+		AddRow(2, "Ivan", "Ivanov", "Male", "1990-01-01", `[{"id":3,"title":"Movie 3","release_date":"2002-01-01"}]`)
+
+	mock.ExpectQuery("^SELECT (.+) FROM actors a WHERE a.id > \\$1 ORDER BY a.id ASC LIMIT \\$2 OFFSET \\$3$").
+		WithArgs(1, 2, 0).WillReturnRows(rows)
+
+	params.Cursor = list.NextCursor
+	list, err = repo.GetActors(params)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(list.Actors))
+	assert.Equal(t, 2, list.Actors[0].Id)
+	assert.Empty(t, list.NextCursor)
+	assert.NotEmpty(t, list.PrevCursor)
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestActorPostgres_DeleteActor(t *testing.T) {
+func TestActorPostgres_GetActorsWithFilters(t *testing.T) {
+
 	mockDB, mock, err := sqlmock.New()
 	if err != nil {
-		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+		t.Fatalf("ar error '%s' wasn't expected when opening a stub db connection", err)
 	}
 	defer mockDB.Close()
 
 	repo := NewActorPostgres(sqlx.NewDb(mockDB, "sqlmock"))
 
-	actorID := 1
-	query := "DELETE FROM actors WHERE id=\\$1"
-	mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, 1)).WillReturnError(nil)
+	keys, _ := newKeyset(actorSortKeys, "a.id", filmoteka.SortByName, filmoteka.OrderAsc)
+	noMovies := false
 
-	err = repo.DeleteActor(actorID)
+	//Filters narrow the total as well as the page
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM actors a WHERE (a.first_name || ' ' || a.last_name) ILIKE $1 ESCAPE '\\' AND a.gender = $2 AND a.date_of_birth >= $3 AND a.date_of_birth <= $4 AND NOT EXISTS (SELECT 1 FROM moviesactors fma WHERE fma.actor_id = a.id)")).
+		WithArgs("%smith%", "male", "1960-01-01", "1990-12-31").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
 
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-		return
-	}
+	rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "gender", "date_of_birth", "movies"}).
+		AddRow(6, "Jaden", "Smith", "male", "1998-07-08", "[]")
+	mock.ExpectQuery(regexp.QuoteMeta("AND ((a.last_name || ' ' || a.first_name), a.id) > ($5::text, $6) ORDER BY (a.last_name || ' ' || a.first_name) ASC, a.id ASC LIMIT $7 OFFSET $8")).
+		WithArgs("%smith%", "male", "1960-01-01", "1990-12-31", "Smith Will", 5, 2, 0).WillReturnRows(rows)
+
+	list, err := repo.GetActors(filmoteka.ActorListParams{
+		PageParams: filmoteka.PageParams{
+			Sort:   filmoteka.SortByName,
+			Order:  filmoteka.OrderAsc,
+			Limit:  1,
+			Cursor: keys.cursor("Smith Will", 5, false),
+		},
+		Name:       "smith",
+		Gender:     "male",
+		BornAfter:  "1960-01-01",
+		BornBefore: "1990-12-31",
+		HasMovies:  &noMovies,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, list.Total)
+	assert.Equal(t, 1, len(list.Actors))
+	assert.Equal(t, "Jaden", list.Actors[0].FirstName)
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestActorPostgres_DeleteActor(t *testing.T) {
+	testTable := []struct {
+		name         string
+		mode         string
+		mockBehavior func(mock sqlmock.Sqlmock)
+		wantErrIs    error
+		wantMovies   []filmoteka.MovieSummary
+	}{
+		{
+			name: "Restrict without movies",
+			mode: filmoteka.DeleteModeRestrict,
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM actors WHERE id=$1)")).
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectQuery("^SELECT (.+) FROM movies m WHERE m.id IN \\( SELECT movie_id FROM moviesactors WHERE actor_id=\\$1 UNION SELECT movie_id FROM moviescrew WHERE person_id=\\$1 \\) (.+)$").
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "title", "release_date"}))
+				mock.ExpectExec("DELETE FROM people WHERE id=\\$1").
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Restrict with movies",
+			mode: filmoteka.DeleteModeRestrict,
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM actors WHERE id=$1)")).
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectQuery("^SELECT (.+) FROM movies m WHERE m.id IN \\( SELECT movie_id FROM moviesactors WHERE actor_id=\\$1 UNION SELECT movie_id FROM moviescrew WHERE person_id=\\$1 \\) (.+)$").
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "title", "release_date"}).
+					AddRow(1, "Dune", "2021-09-03"))
+				mock.ExpectRollback()
+			},
+			wantErrIs:  filmoteka.ErrConflict,
+			wantMovies: []filmoteka.MovieSummary{{Id: 1, Title: "Dune", ReleaseDate: "2021-09-03"}},
+		},
+		{
+			name: "Cascade",
+			mode: filmoteka.DeleteModeCascade,
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM actors WHERE id=$1)")).
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectExec("DELETE FROM moviesactors WHERE actor_id=\\$1").
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("DELETE FROM moviescrew WHERE person_id=\\$1").
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM people WHERE id=\\$1").
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			//A director who was cast is an actor only through the cast, so
+			//they leave the actors view once it's unlinked
+			name: "Cascade non-acting person with cast credits",
+			mode: filmoteka.DeleteModeCascade,
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM actors WHERE id=$1)")).
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectExec("DELETE FROM moviesactors WHERE actor_id=\\$1").
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM moviescrew WHERE person_id=\\$1").
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("DELETE FROM people WHERE id=\\$1").
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Not an actor",
+			mode: filmoteka.DeleteModeCascade,
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM actors WHERE id=$1)")).
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectRollback()
+			},
+			wantErrIs: filmoteka.ErrActorNotFound,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+			}
+			defer mockDB.Close()
+
+			repo := NewActorPostgres(sqlx.NewDb(mockDB, "sqlmock"))
+			testCase.mockBehavior(mock)
+
+			err = repo.DeleteActor(1, testCase.mode)
+			if testCase.wantErrIs != nil {
+				assert.ErrorIs(t, err, testCase.wantErrIs)
+			} else {
+				assert.NoError(t, err)
+			}
+			if testCase.wantMovies != nil {
+				var inUse *filmoteka.ActorInUseError
+				if assert.ErrorAs(t, err, &inUse) {
+					assert.Equal(t, testCase.wantMovies, inUse.Movies)
+				}
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -1,43 +1,64 @@
 package repository
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	filmoteka "vk_restAPI"
-
-	"github.com/jmoiron/sqlx"
 )
 
 type AuthPostgres struct {
-	db *sqlx.DB
+	db Executor
 }
 
-func NewAuthPostgres(db *sqlx.DB) *AuthPostgres {
+func NewAuthPostgres(db Executor) *AuthPostgres {
 	return &AuthPostgres{db: db}
 }
 
 func (a *AuthPostgres) CreateUser(user filmoteka.User) (int, error) {
 	var id int
-	qurey := fmt.Sprintf("INSERT INTO %s (username, password_hash, is_admin) values ($1, $2, $3) RETURNING id", userTable)
+	qurey := fmt.Sprintf("INSERT INTO %s (username, password_hash, role) values ($1, $2, $3) RETURNING id", userTable)
 
-	row := a.db.QueryRow(qurey, user.Username, user.Password, user.Is_admin)
+	row := a.db.QueryRow(qurey, user.Username, user.Password, user.Role)
 	if err := row.Scan(&id); err != nil {
-		return 0, err
+		if isUniqueViolation(err) {
+			return 0, filmoteka.ErrUsernameTaken
+		}
+		return 0, dbError(err)
 	}
 	return id, nil
 }
 
-func (a *AuthPostgres) GetUser(username, password string) (filmoteka.User, error) {
+// GetUser returns the user together with the stored password hash, which
+// the service layer verifies.
+func (a *AuthPostgres) GetUser(username string) (filmoteka.User, error) {
 	var user filmoteka.User
-	query := fmt.Sprintf("SELECT id FROM %s WHERE username=$1 AND password_hash=$2", userTable)
-	err := a.db.Get(&user, query, username, password)
+	query := fmt.Sprintf("SELECT id, username, password_hash, role FROM %s WHERE username=$1", userTable)
+	err := a.db.Get(&user, query, username)
 
 	return user, err
 }
 
-func (a *AuthPostgres) GetUserStatus(id int) (bool, error) {
-	var isAdmin bool
-	query := fmt.Sprintf("SELECT is_admin FROM %s WHERE id=$1", userTable)
-	err := a.db.Get(&isAdmin, query, id)
+func (a *AuthPostgres) UpdatePasswordHash(id int, passwordHash string) error {
+	query := fmt.Sprintf("UPDATE %s SET password_hash=$1 WHERE id=$2", userTable)
+	_, err := a.db.Exec(query, passwordHash, id)
+
+	return err
+}
+
+func (a *AuthPostgres) GetUserRole(id int) (string, error) {
+	var role string
+	query := fmt.Sprintf("SELECT role FROM %s WHERE id=$1", userTable)
+	err := a.db.Get(&role, query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return role, filmoteka.ErrUserNotFound
+	}
+
+	return role, err
+}
 
-	return isAdmin, err
+func (a *AuthPostgres) SetUserRole(id int, role string) error {
+	query := fmt.Sprintf("UPDATE %s SET role=$1 WHERE id=$2", userTable)
+	res, err := a.db.Exec(query, role, id)
+	return affectOne(res, err, filmoteka.ErrUserNotFound)
 }
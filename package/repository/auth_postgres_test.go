@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"database/sql"
+	"errors"
 	"testing"
 	filmoteka "vk_restAPI"
 
@@ -38,7 +40,7 @@ func TestActorPostgres_CreateUser(t *testing.T) {
 					Id:       1,
 					Username: "username",
 					Password: "password",
-					Is_admin: true,
+					Role:     "admin",
 				},
 			},
 
@@ -47,7 +49,7 @@ func TestActorPostgres_CreateUser(t *testing.T) {
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(args.user.Id)
 
 				mock.ExpectQuery("INSERT INTO users").
-					WithArgs(args.user.Username, args.user.Password, args.user.Is_admin).WillReturnRows(rows)
+					WithArgs(args.user.Username, args.user.Password, args.user.Role).WillReturnRows(rows)
 			},
 		},
 	}
@@ -94,26 +96,42 @@ func TestActorPostgres_GetUser(t *testing.T) {
 			args: args{
 				user: filmoteka.User{
 					Id:       1,
-					Username: "",
-					Password: "",
-					Is_admin: false,
+					Username: "username",
+					Password: "$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$a2V5",
+					Role:     "viewer",
 				},
 			},
 
 			mockBehaivior: func(args args) {
 
-				rows := sqlmock.NewRows([]string{"id"}).AddRow(args.user.Id)
+				rows := sqlmock.NewRows([]string{"id", "username", "password_hash", "role"}).
+					AddRow(args.user.Id, args.user.Username, args.user.Password, args.user.Role)
+
+				mock.ExpectQuery("SELECT id, username, password_hash, role FROM users WHERE username=\\$1").
+					WithArgs(args.user.Username).WillReturnRows(rows)
+			},
+		},
+		{
+			name: "Not Found",
+			args: args{
+				user: filmoteka.User{
+					Username: "unknown",
+				},
+			},
+
+			mockBehaivior: func(args args) {
 
-				mock.ExpectQuery("SELECT id FROM users WHERE username=\\$1 AND password_hash=\\$2").
-					WithArgs(args.user.Username, args.user.Password).WillReturnRows(rows)
+				mock.ExpectQuery("SELECT id, username, password_hash, role FROM users WHERE username=\\$1").
+					WithArgs(args.user.Username).WillReturnError(sql.ErrNoRows)
 			},
+			wantErr: true,
 		},
 	}
 	for _, testCase := range testTable {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mockBehaivior(testCase.args)
 
-			got, err := authRepo.GetUser(testCase.args.user.Username, testCase.args.user.Password)
+			got, err := authRepo.GetUser(testCase.args.user.Username)
 			if testCase.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -125,7 +143,7 @@ func TestActorPostgres_GetUser(t *testing.T) {
 
 }
 
-func TestActorPostgres_GetUserStatus(t *testing.T) {
+func TestActorPostgres_GetUserRole(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
@@ -154,15 +172,15 @@ func TestActorPostgres_GetUserStatus(t *testing.T) {
 					Id:       1,
 					Username: "",
 					Password: "",
-					Is_admin: true,
+					Role:     "admin",
 				},
 			},
 
 			mockBehaivior: func(args args) {
 
-				rows := sqlmock.NewRows([]string{"id"}).AddRow(args.user.Id)
+				rows := sqlmock.NewRows([]string{"role"}).AddRow(args.user.Role)
 
-				mock.ExpectQuery("SELECT is_admin FROM users WHERE id=\\$1").
+				mock.ExpectQuery("SELECT role FROM users WHERE id=\\$1").
 					WithArgs(args.user.Id).WillReturnRows(rows)
 			},
 		},
@@ -171,13 +189,135 @@ func TestActorPostgres_GetUserStatus(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mockBehaivior(testCase.args)
 
-			got, err := authRepo.GetUserStatus(testCase.args.user.Id)
+			got, err := authRepo.GetUserRole(testCase.args.user.Id)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.args.user.Role, got)
+			}
+		})
+	}
+
+}
+
+func TestActorPostgres_UpdatePasswordHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	authRepo := NewAuthPostgres(sqlxDB)
+
+	type args struct {
+		id           int
+		passwordHash string
+	}
+	type mockBehaivior func(args args)
+
+	testTable := []struct {
+		name          string
+		mockBehaivior mockBehaivior
+		args          args
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				id:           1,
+				passwordHash: "$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$a2V5",
+			},
+
+			mockBehaivior: func(args args) {
+				mock.ExpectExec("UPDATE users SET password_hash=\\$1 WHERE id=\\$2").
+					WithArgs(args.passwordHash, args.id).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Failure",
+			args: args{
+				id:           1,
+				passwordHash: "$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$a2V5",
+			},
+
+			mockBehaivior: func(args args) {
+				mock.ExpectExec("UPDATE users SET password_hash=\\$1 WHERE id=\\$2").
+					WithArgs(args.passwordHash, args.id).WillReturnError(errors.New("some error"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehaivior(testCase.args)
+
+			err := authRepo.UpdatePasswordHash(testCase.args.id, testCase.args.passwordHash)
 			if testCase.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, testCase.args.user.Is_admin, got)
 			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+}
+
+func TestActorPostgres_SetUserRole(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	authRepo := NewAuthPostgres(sqlxDB)
+
+	type args struct {
+		id   int
+		role string
+	}
+	type mockBehaivior func(args args)
+
+	testTable := []struct {
+		name          string
+		mockBehaivior mockBehaivior
+		args          args
+		wantErr       error
+	}{
+		{
+			name: "OK",
+			args: args{id: 2, role: "editor"},
+			mockBehaivior: func(args args) {
+				mock.ExpectExec("UPDATE users SET role=\\$1 WHERE id=\\$2").
+					WithArgs(args.role, args.id).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Not Found",
+			args: args{id: 9, role: "viewer"},
+			mockBehaivior: func(args args) {
+				mock.ExpectExec("UPDATE users SET role=\\$1 WHERE id=\\$2").
+					WithArgs(args.role, args.id).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: filmoteka.ErrUserNotFound,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehaivior(testCase.args)
+
+			err := authRepo.SetUserRole(testCase.args.id, testCase.args.role)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 
@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	filmoteka "vk_restAPI"
+
+	"github.com/lib/pq"
+)
+
+const collectionKindIndex = "collections_user_kind_idx"
+
+type CollectionPostgres struct {
+	db Executor
+}
+
+func NewCollectionPostgres(db Executor) *CollectionPostgres {
+	return &CollectionPostgres{db: db}
+}
+
+// collectionColumns selects a collection c with its owner u and the number
+// of its movies.
+var collectionColumns = fmt.Sprintf(`c.id, c.user_id, u.username, c.name, c.kind, c.slug, c.created_at,
+			(SELECT COUNT(*) FROM %s ci WHERE ci.collection_id = c.id) AS movies`, collectionItemsTable)
+
+// collectionError translates the unique violations of collections.
+func collectionError(err error) error {
+	switch {
+	case isUniqueViolationOf(err, collectionKindIndex):
+		return filmoteka.ErrCollectionKindUsed
+	case isUniqueViolation(err):
+		return filmoteka.ErrCollectionExists
+	}
+	return dbError(err)
+}
+
+// CreateCollection adds a collection of userId. slug is blank for a private
+// collection.
+func (r *CollectionPostgres) CreateCollection(userId int, input filmoteka.CollectionInput, slug string) (int, error) {
+	var id int
+
+	query := fmt.Sprintf("INSERT INTO %s (user_id, name, kind, slug) VALUES ($1, $2, $3, $4) RETURNING id", collectionsTable)
+	if err := r.db.QueryRow(query, userId, input.Name, input.Kind, slug).Scan(&id); err != nil {
+		return 0, collectionError(err)
+	}
+	return id, nil
+}
+
+// GetCollections returns the collections of userId: the watchlist, the
+// favorites, then the custom ones by name.
+func (r *CollectionPostgres) GetCollections(userId int) ([]filmoteka.Collection, error) {
+	var collections []filmoteka.Collection
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s c
+		JOIN %s u ON c.user_id = u.id
+		WHERE c.user_id = $1
+		ORDER BY CASE c.kind WHEN '%s' THEN 0 WHEN '%s' THEN 1 ELSE 2 END, c.name, c.id
+	`, collectionColumns, collectionsTable, userTable, filmoteka.CollectionWatchlist, filmoteka.CollectionFavorites)
+
+	if err := r.db.Select(&collections, query, userId); err != nil {
+		return nil, err
+	}
+	return collections, nil
+}
+
+// GetCollection returns a collection of userId. Someone else's collection
+// is reported as not found, shared or not.
+func (r *CollectionPostgres) GetCollection(collectionId, userId int) (filmoteka.Collection, error) {
+	return r.getCollection("c.id = $1 AND c.user_id = $2", collectionId, userId)
+}
+
+// GetSharedCollection returns the collection shared under slug.
+func (r *CollectionPostgres) GetSharedCollection(slug string) (filmoteka.Collection, error) {
+	return r.getCollection("c.slug = $1 AND c.slug <> ''", slug)
+}
+
+func (r *CollectionPostgres) getCollection(condition string, args ...interface{}) (filmoteka.Collection, error) {
+	var collection filmoteka.Collection
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s c
+		JOIN %s u ON c.user_id = u.id
+		WHERE %s
+	`, collectionColumns, collectionsTable, userTable, condition)
+
+	err := r.db.Get(&collection, query, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return collection, filmoteka.ErrCollectionNotFound
+	}
+	return collection, err
+}
+
+// UpdateCollection renames and shares or unshares a collection of userId.
+// slug is only stored when a private collection is shared.
+func (r *CollectionPostgres) UpdateCollection(collectionId, userId int, input filmoteka.UpdateCollection, slug string) error {
+	setValues := make([]string, 0)
+	args := make([]interface{}, 0)
+
+	if input.Name != nil {
+		args = append(args, *input.Name)
+		setValues = append(setValues, fmt.Sprintf("name=$%d", len(args)))
+	}
+
+	if input.Public != nil && *input.Public {
+		args = append(args, slug)
+		setValues = append(setValues, fmt.Sprintf("slug=CASE WHEN slug = '' THEN $%d ELSE slug END", len(args)))
+	} else if input.Public != nil {
+		setValues = append(setValues, "slug=''")
+	}
+
+	args = append(args, collectionId, userId)
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE id=$%d AND user_id=$%d",
+		collectionsTable, strings.Join(setValues, ", "), len(args)-1, len(args))
+
+	res, err := r.db.Exec(query, args...)
+	if err != nil {
+		return collectionError(err)
+	}
+	return affectOne(res, nil, filmoteka.ErrCollectionNotFound)
+}
+
+func (r *CollectionPostgres) DeleteCollection(collectionId, userId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id=$1 AND user_id=$2", collectionsTable)
+	res, err := r.db.Exec(query, collectionId, userId)
+	return affectOne(res, err, filmoteka.ErrCollectionNotFound)
+}
+
+// GetCollectionMovies returns a page of the movies of a collection in its
+// order. The caller checks who may see the collection.
+func (r *CollectionPostgres) GetCollectionMovies(collectionId int, params filmoteka.CollectionMoviesParams) (filmoteka.CollectionMoviesList, error) {
+	var list filmoteka.CollectionMoviesList
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE collection_id=$1", collectionItemsTable)
+	if err := r.db.Get(&list.Total, countQuery, collectionId); err != nil {
+		return list, err
+	}
+
+	query := movieQuery{
+		from:       fmt.Sprintf("%s ci JOIN %s m ON m.id = ci.movie_id", collectionItemsTable, moviesTable),
+		columns:    []string{"ci.added_at"},
+		conditions: []string{"ci.collection_id = $1"},
+		orderBy:    "ci.position, ci.added_at, m.id",
+		limit:      "LIMIT $2 OFFSET $3",
+	}.String()
+
+	if err := r.db.Select(&list.Movies, query, collectionId, params.Limit, params.Offset); err != nil {
+		return list, err
+	}
+
+	return list, nil
+}
+
+// AddCollectionMovie appends a movie to a collection of userId.
+func (r *CollectionPostgres) AddCollectionMovie(collectionId, userId, movieId int) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (collection_id, movie_id, position)
+		SELECT c.id, $3, COALESCE(MAX(ci.position), 0) + 1
+		FROM %s c
+		LEFT JOIN %s ci ON ci.collection_id = c.id
+		WHERE c.id = $1 AND c.user_id = $2
+		GROUP BY c.id
+	`, collectionItemsTable, collectionsTable, collectionItemsTable)
+
+	res, err := r.db.Exec(query, collectionId, userId, movieId)
+	switch {
+	case isUniqueViolation(err):
+		return filmoteka.ErrMovieInCollection
+	case isForeignKeyViolation(err):
+		return filmoteka.ErrMovieNotFound
+	}
+	return affectOne(res, err, filmoteka.ErrCollectionNotFound)
+}
+
+// RemoveCollectionMovie takes a movie out of a collection of userId. The
+// movies after it keep their positions.
+func (r *CollectionPostgres) RemoveCollectionMovie(collectionId, userId, movieId int) error {
+	query := fmt.Sprintf(`
+		DELETE FROM %s ci
+		USING %s c
+		WHERE ci.collection_id = c.id AND c.id = $1 AND c.user_id = $2 AND ci.movie_id = $3
+	`, collectionItemsTable, collectionsTable)
+
+	res, err := r.db.Exec(query, collectionId, userId, movieId)
+	return affectOne(res, err, filmoteka.ErrCollectionMovieNotFound)
+}
+
+// ReorderCollection puts the movies of a collection of userId in the order
+// of movieIds, which must list each of them exactly once.
+func (r *CollectionPostgres) ReorderCollection(collectionId, userId int, movieIds []int) error {
+	return withTx(r.db, func(tx Executor) error {
+		var count int
+
+		query := fmt.Sprintf(`
+			SELECT COUNT(ci.movie_id)
+			FROM %s c
+			LEFT JOIN %s ci ON ci.collection_id = c.id
+			WHERE c.id = $1 AND c.user_id = $2
+			GROUP BY c.id
+		`, collectionsTable, collectionItemsTable)
+
+		err := tx.Get(&count, query, collectionId, userId)
+		if errors.Is(err, sql.ErrNoRows) {
+			return filmoteka.ErrCollectionNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		if count != len(movieIds) {
+			return filmoteka.ErrCollectionOrder
+		}
+
+		//A movie listed twice is updated once, so the counts don't match
+		query = fmt.Sprintf(`
+			UPDATE %s ci
+			SET position = o.position
+			FROM unnest($2::int[]) WITH ORDINALITY AS o (movie_id, position)
+			WHERE ci.collection_id = $1 AND ci.movie_id = o.movie_id
+		`, collectionItemsTable)
+
+		res, err := tx.Exec(query, collectionId, pq.Array(movieIds))
+		if err != nil {
+			return dbError(err)
+		}
+
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if int(rows) != len(movieIds) {
+			return filmoteka.ErrCollectionOrder
+		}
+		return nil
+	})
+}
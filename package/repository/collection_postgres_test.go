@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+	filmoteka "vk_restAPI"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionPostgres_CreateCollection(t *testing.T) {
+	type mockBehavior func(mock sqlmock.Sqlmock)
+
+	input := filmoteka.CollectionInput{Name: "Посмотреть позже", Kind: filmoteka.CollectionWatchlist}
+	insert := regexp.QuoteMeta("INSERT INTO collections (user_id, name, kind, slug) VALUES ($1, $2, $3, $4) RETURNING id")
+
+	testTable := []struct {
+		name          string
+		mockBehavior  mockBehavior
+		expectedId    int
+		expectedError error
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(insert).WithArgs(2, "Посмотреть позже", "watchlist", "").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+			},
+			expectedId: 4,
+		},
+		{
+			name: "Second Watchlist",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(insert).WithArgs(2, "Посмотреть позже", "watchlist", "").
+					WillReturnError(&pq.Error{Code: "23505", Constraint: "collections_user_kind_idx"})
+			},
+			expectedError: filmoteka.ErrCollectionKindUsed,
+		},
+		{
+			name: "Name Taken",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(insert).WithArgs(2, "Посмотреть позже", "watchlist", "").
+					WillReturnError(&pq.Error{Code: "23505", Constraint: "collections_user_name_key"})
+			},
+			expectedError: filmoteka.ErrCollectionExists,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+			}
+			defer db.Close()
+
+			repo := NewCollectionPostgres(sqlx.NewDb(db, "sqlmock"))
+			testCase.mockBehavior(mock)
+
+			id, err := repo.CreateCollection(2, input, "")
+
+			if testCase.expectedError != nil {
+				assert.ErrorIs(t, err, testCase.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expectedId, id)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCollectionPostgres_UpdateCollection(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCollectionPostgres(sqlx.NewDb(db, "sqlmock"))
+
+	name, public := "Лучшее", true
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE collections SET name=$1, slug=CASE WHEN slug = '' THEN $2 ELSE slug END WHERE id=$3 AND user_id=$4")).
+		WithArgs("Лучшее", "c2x5ZzEyMzQ1Njc4", 4, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.UpdateCollection(4, 2, filmoteka.UpdateCollection{Name: &name, Public: &public}, "c2x5ZzEyMzQ1Njc4")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCollectionPostgres_GetCollectionMovies(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCollectionPostgres(sqlx.NewDb(db, "sqlmock"))
+
+	added := time.Date(2024, 3, 8, 20, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM collectionitems WHERE collection_id=$1")).
+		WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	query := strings.Replace(movieProjection, "cr.crew, ur.user_rating ", "cr.crew, ur.user_rating, ci.added_at ", 1)
+	query = strings.Replace(query, "FROM movies m LEFT JOIN LATERAL", "FROM collectionitems ci JOIN movies m ON m.id = ci.movie_id LEFT JOIN LATERAL", 1)
+	mock.ExpectQuery(regexp.QuoteMeta(query+" WHERE ci.collection_id = $1 ORDER BY ci.position, ci.added_at, m.id LIMIT $2 OFFSET $3")).
+		WithArgs(4, 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "release_date", "rating", "added_at"}).
+			AddRow(1, "Дюна 2", "2024-02-29", 9, added))
+
+	list, err := repo.GetCollectionMovies(4, filmoteka.CollectionMoviesParams{Limit: 20})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, list.Total)
+	assert.Equal(t, []filmoteka.CollectionMovie{{
+		MoviesWithActors: filmoteka.MoviesWithActors{Id: 1, Title: "Дюна 2", ReleaseDate: "2024-02-29", Rating: 9},
+		AddedAt:          added,
+	}}, list.Movies)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCollectionPostgres_AddCollectionMovie(t *testing.T) {
+	type mockBehavior func(mock sqlmock.Sqlmock)
+
+	insert := regexp.QuoteMeta("INSERT INTO collectionitems (collection_id, movie_id, position) " +
+		"SELECT c.id, $3, COALESCE(MAX(ci.position), 0) + 1 FROM collections c " +
+		"LEFT JOIN collectionitems ci ON ci.collection_id = c.id WHERE c.id = $1 AND c.user_id = $2 GROUP BY c.id")
+
+	testTable := []struct {
+		name          string
+		mockBehavior  mockBehavior
+		expectedError error
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(insert).WithArgs(4, 2, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Already Added",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(insert).WithArgs(4, 2, 1).WillReturnError(&pq.Error{Code: "23505"})
+			},
+			expectedError: filmoteka.ErrMovieInCollection,
+		},
+		{
+			name: "No Movie",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(insert).WithArgs(4, 2, 1).WillReturnError(&pq.Error{Code: "23503"})
+			},
+			expectedError: filmoteka.ErrMovieNotFound,
+		},
+		{
+			name: "Someone Else's Collection",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(insert).WithArgs(4, 2, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedError: filmoteka.ErrCollectionNotFound,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+			}
+			defer db.Close()
+
+			repo := NewCollectionPostgres(sqlx.NewDb(db, "sqlmock"))
+			testCase.mockBehavior(mock)
+
+			err = repo.AddCollectionMovie(4, 2, 1)
+
+			if testCase.expectedError != nil {
+				assert.ErrorIs(t, err, testCase.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCollectionPostgres_ReorderCollection(t *testing.T) {
+	type mockBehavior func(mock sqlmock.Sqlmock)
+
+	count := regexp.QuoteMeta("SELECT COUNT(ci.movie_id) FROM collections c LEFT JOIN collectionitems ci ON ci.collection_id = c.id " +
+		"WHERE c.id = $1 AND c.user_id = $2 GROUP BY c.id")
+	update := regexp.QuoteMeta("UPDATE collectionitems ci SET position = o.position " +
+		"FROM unnest($2::int[]) WITH ORDINALITY AS o (movie_id, position) WHERE ci.collection_id = $1 AND ci.movie_id = o.movie_id")
+
+	testTable := []struct {
+		name          string
+		movieIds      []int
+		mockBehavior  mockBehavior
+		expectedError error
+	}{
+		{
+			name:     "OK",
+			movieIds: []int{3, 1, 2},
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(count).WithArgs(4, 2).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+				mock.ExpectExec(update).WithArgs(4, pq.Array([]int{3, 1, 2})).WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:     "Movie Missing",
+			movieIds: []int{3, 1},
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(count).WithArgs(4, 2).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+				mock.ExpectRollback()
+			},
+			expectedError: filmoteka.ErrCollectionOrder,
+		},
+		{
+			name:     "Movie Listed Twice",
+			movieIds: []int{3, 3, 1},
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(count).WithArgs(4, 2).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+				mock.ExpectExec(update).WithArgs(4, pq.Array([]int{3, 3, 1})).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectRollback()
+			},
+			expectedError: filmoteka.ErrCollectionOrder,
+		},
+		{
+			name:     "Someone Else's Collection",
+			movieIds: []int{1},
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(count).WithArgs(4, 2).WillReturnRows(sqlmock.NewRows([]string{"count"}))
+				mock.ExpectRollback()
+			},
+			expectedError: filmoteka.ErrCollectionNotFound,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+			}
+			defer db.Close()
+
+			repo := NewCollectionPostgres(sqlx.NewDb(db, "sqlmock"))
+			testCase.mockBehavior(mock)
+
+			err = repo.ReorderCollection(4, 2, testCase.movieIds)
+
+			if testCase.expectedError != nil {
+				assert.ErrorIs(t, err, testCase.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	filmoteka "vk_restAPI"
+)
+
+// sortKey is a whitelisted column a list can be ordered and seeked by.
+// cast is applied to the cursor value, which always travels as text.
+type sortKey struct {
+	column string
+	cast   string
+}
+
+// cursor is the decoded form of an opaque page token. It remembers the
+// sort it was issued for, so it can't be replayed against another order.
+type cursor struct {
+	Sort     string `json:"s"`
+	Order    string `json:"o"`
+	Value    string `json:"v,omitempty"`
+	Id       int    `json:"i"`
+	Backward bool   `json:"b,omitempty"`
+}
+
+func encodeCursor(c cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(token, sort, order string) (cursor, error) {
+	var c cursor
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, filmoteka.ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, filmoteka.ErrInvalidCursor
+	}
+
+	if c.Sort != sort || c.Order != order {
+		return c, fmt.Errorf("%w: issued for a different sort order", filmoteka.ErrInvalidCursor)
+	}
+
+	return c, nil
+}
+
+// keyset builds ORDER BY and seek predicates for keyset pagination.
+// Rows are always tie-broken on idColumn so the order is total.
+type keyset struct {
+	sort     string
+	order    string
+	key      sortKey
+	idColumn string
+}
+
+func newKeyset(keys map[string]sortKey, idColumn, sort, order string) (keyset, error) {
+	key, ok := keys[sort]
+	if !ok {
+		return keyset{}, fmt.Errorf("unsupported sort field: %s", sort)
+	}
+
+	if order != filmoteka.OrderAsc && order != filmoteka.OrderDesc {
+		return keyset{}, fmt.Errorf("unsupported sort order: %s", order)
+	}
+
+	return keyset{sort: sort, order: order, key: key, idColumn: idColumn}, nil
+}
+
+// descending reports the effective direction; backward pages are read
+// in reverse and flipped back afterwards.
+func (k keyset) descending(backward bool) bool {
+	return (k.order == filmoteka.OrderDesc) != backward
+}
+
+func (k keyset) orderBy(backward bool) string {
+	direction := "ASC"
+	if k.descending(backward) {
+		direction = "DESC"
+	}
+
+	if k.key.column == k.idColumn {
+		return fmt.Sprintf("%s %s", k.idColumn, direction)
+	}
+	return fmt.Sprintf("%s %s, %s %s", k.key.column, direction, k.idColumn, direction)
+}
+
+// seek returns a predicate selecting rows after c in the read direction.
+// Placeholders are numbered from argId.
+func (k keyset) seek(c cursor, argId int) (string, []interface{}) {
+	op := ">"
+	if k.descending(c.Backward) {
+		op = "<"
+	}
+
+	if k.key.column == k.idColumn {
+		return fmt.Sprintf("%s %s $%d", k.idColumn, op, argId), []interface{}{c.Id}
+	}
+
+	return fmt.Sprintf("(%s, %s) %s ($%d::%s, $%d)", k.key.column, k.idColumn, op, argId, k.key.cast, argId+1),
+		[]interface{}{c.Value, c.Id}
+}
+
+func (k keyset) cursor(value string, id int, backward bool) string {
+	return encodeCursor(cursor{Sort: k.sort, Order: k.order, Value: value, Id: id, Backward: backward})
+}
+
+// buildPage trims the look-ahead row fetched past limit, restores natural
+// order for backward pages and issues cursors for the neighbouring pages.
+// started tells whether the page was reached by skipping earlier rows.
+func buildPage[T any](k keyset, rows []T, limit int, from cursor, started bool, key func(T) (string, int)) ([]T, string, string) {
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	if from.Backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	if len(rows) == 0 {
+		return rows, "", ""
+	}
+
+	var next, prev string
+	if (from.Backward && started) || (!from.Backward && hasMore) {
+		value, id := key(rows[len(rows)-1])
+		next = k.cursor(value, id, false)
+	}
+
+	if (from.Backward && hasMore) || (!from.Backward && started) {
+		value, id := key(rows[0])
+		prev = k.cursor(value, id, true)
+	}
+
+	return rows, next, prev
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"testing"
+	filmoteka "vk_restAPI"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyset_orderBy(t *testing.T) {
+	testTable := []struct {
+		name     string
+		sort     string
+		order    string
+		backward bool
+		expected string
+		wantErr  bool
+	}{
+		{name: "Rating desc", sort: "rating", order: "desc", expected: "m.rating DESC, m.id DESC"},
+		{name: "Title asc", sort: "title", order: "asc", expected: "m.title ASC, m.id ASC"},
+		{name: "Release date backward", sort: "release_date", order: "asc", backward: true, expected: "m.release_date DESC, m.id DESC"},
+		{name: "Id only", sort: "id", order: "desc", expected: "m.id DESC"},
+		{name: "Unknown field", sort: "description", order: "asc", wantErr: true},
+		{name: "Unknown order", sort: "title", order: "sideways", wantErr: true},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			keys, err := newKeyset(movieSortKeys, "m.id", testCase.sort, testCase.order)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expected, keys.orderBy(testCase.backward))
+			}
+		})
+	}
+}
+
+func TestKeyset_seek(t *testing.T) {
+	testTable := []struct {
+		name         string
+		sort         string
+		order        string
+		from         cursor
+		expected     string
+		expectedArgs []interface{}
+	}{
+		{
+			name:         "Forward desc",
+			sort:         "rating",
+			order:        "desc",
+			from:         cursor{Value: "7", Id: 3},
+			expected:     "(m.rating, m.id) < ($1::int, $2)",
+			expectedArgs: []interface{}{"7", 3},
+		},
+		{
+			name:         "Backward asc",
+			sort:         "release_date",
+			order:        "asc",
+			from:         cursor{Value: "2000-01-01", Id: 3, Backward: true},
+			expected:     "(m.release_date, m.id) < ($1::date, $2)",
+			expectedArgs: []interface{}{"2000-01-01", 3},
+		},
+		{
+			name:         "Id only",
+			sort:         "id",
+			order:        "asc",
+			from:         cursor{Id: 3},
+			expected:     "m.id > $1",
+			expectedArgs: []interface{}{3},
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			keys, err := newKeyset(movieSortKeys, "m.id", testCase.sort, testCase.order)
+			assert.NoError(t, err)
+
+			clause, args := keys.seek(testCase.from, 1)
+			assert.Equal(t, testCase.expected, clause)
+			assert.Equal(t, testCase.expectedArgs, args)
+		})
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	keys, _ := newKeyset(movieSortKeys, "m.id", "title", "asc")
+	token := keys.cursor("Dune", 1, false)
+
+	c, err := decodeCursor(token, "title", "asc")
+	assert.NoError(t, err)
+	assert.Equal(t, cursor{Sort: "title", Order: "asc", Value: "Dune", Id: 1}, c)
+
+	_, err = decodeCursor(token, "title", "desc")
+	assert.ErrorIs(t, err, filmoteka.ErrInvalidCursor)
+
+	_, err = decodeCursor("not a cursor", "title", "asc")
+	assert.ErrorIs(t, err, filmoteka.ErrInvalidCursor)
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	filmoteka "vk_restAPI"
+
+	"github.com/lib/pq"
+)
+
+// Postgres error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgNotNullViolation    = "23502"
+	pgCheckViolation      = "23514"
+	pgDataException       = "22"
+)
+
+// dbError translates constraint and data errors into domain errors. The
+// messages are generic on purpose: the driver's text names tables and
+// constraints, which must not reach clients. Other errors are returned as is.
+func dbError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	switch {
+	case pqErr.Code == pgUniqueViolation:
+		return filmoteka.Errorf(filmoteka.ErrConflict, "resource already exists")
+	case pqErr.Code == pgForeignKeyViolation:
+		return filmoteka.Errorf(filmoteka.ErrConflict, "referenced resource does not exist or is still in use")
+	case pqErr.Code == pgNotNullViolation, pqErr.Code == pgCheckViolation:
+		return filmoteka.Errorf(filmoteka.ErrValidation, "value violates a constraint")
+	case pqErr.Code.Class() == pgDataException:
+		return filmoteka.Errorf(filmoteka.ErrValidation, "invalid value")
+	}
+
+	return err
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pgUniqueViolation
+}
+
+// isUniqueViolationOf tells apart the unique constraints of a table.
+func isUniqueViolationOf(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pgUniqueViolation && pqErr.Constraint == constraint
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pgForeignKeyViolation
+}
+
+// affectOne turns an UPDATE or DELETE that matched no row into notFound.
+func affectOne(res sql.Result, err error, notFound error) error {
+	if err != nil {
+		return dbError(err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return notFound
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	filmoteka "vk_restAPI"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDbError(t *testing.T) {
+	testTable := []struct {
+		name     string
+		err      error
+		wantKind error
+	}{
+		{
+			name:     "Unique Violation",
+			err:      &pq.Error{Code: "23505", Message: `duplicate key value violates unique constraint "actors_pkey"`},
+			wantKind: filmoteka.ErrConflict,
+		},
+		{
+			name:     "Foreign Key Violation",
+			err:      &pq.Error{Code: "23503", Message: `insert or update on table "moviesactors" violates foreign key constraint`},
+			wantKind: filmoteka.ErrConflict,
+		},
+		{
+			name:     "Check Violation",
+			err:      &pq.Error{Code: "23514", Message: `new row for relation "movies" violates check constraint "movies_rating_check"`},
+			wantKind: filmoteka.ErrValidation,
+		},
+		{
+			name:     "Invalid Date",
+			err:      &pq.Error{Code: "22007", Message: `invalid input syntax for type date: "tomorrow"`},
+			wantKind: filmoteka.ErrValidation,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := dbError(testCase.err)
+
+			assert.ErrorIs(t, err, testCase.wantKind)
+			assert.NotContains(t, err.Error(), `"`)
+		})
+	}
+
+	//Errors that aren't constraint or data errors pass through untouched
+	other := errors.New("connection reset")
+	assert.Equal(t, other, dbError(other))
+	assert.Nil(t, dbError(nil))
+}
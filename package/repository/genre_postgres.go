@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	filmoteka "vk_restAPI"
+)
+
+type GenrePostgres struct {
+	db Executor
+}
+
+func NewGenrePostgres(db Executor) *GenrePostgres {
+	return &GenrePostgres{db: db}
+}
+
+func (g *GenrePostgres) CreateGenre(genre filmoteka.Genre) (int, error) {
+	var id int
+
+	query := fmt.Sprintf("INSERT INTO %s (name) VALUES ($1) RETURNING id", genresTable)
+	if err := g.db.QueryRow(query, genre.Name).Scan(&id); err != nil {
+		if isUniqueViolation(err) {
+			return 0, filmoteka.ErrGenreExists
+		}
+		return 0, dbError(err)
+	}
+	return id, nil
+}
+
+// GetGenres returns every genre in alphabetical order. There are few of
+// them, so the list is not paged.
+func (g *GenrePostgres) GetGenres() ([]filmoteka.Genre, error) {
+	var genres []filmoteka.Genre
+
+	query := fmt.Sprintf("SELECT id, name FROM %s ORDER BY name", genresTable)
+	if err := g.db.Select(&genres, query); err != nil {
+		return nil, err
+	}
+	return genres, nil
+}
+
+func (g *GenrePostgres) GetGenreById(genreId int) (filmoteka.Genre, error) {
+	var genre filmoteka.Genre
+
+	query := fmt.Sprintf("SELECT id, name FROM %s WHERE id=$1", genresTable)
+	err := g.db.Get(&genre, query, genreId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return genre, filmoteka.ErrGenreNotFound
+	}
+	return genre, err
+}
+
+func (g *GenrePostgres) UpdateGenre(genreId int, genre filmoteka.Genre) error {
+	query := fmt.Sprintf("UPDATE %s SET name=$1 WHERE id=$2", genresTable)
+	res, err := g.db.Exec(query, genre.Name, genreId)
+	if isUniqueViolation(err) {
+		return filmoteka.ErrGenreExists
+	}
+	return affectOne(res, err, filmoteka.ErrGenreNotFound)
+}
+
+// DeleteGenre removes the genre from every movie along with the genre itself.
+func (g *GenrePostgres) DeleteGenre(genreId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id=$1", genresTable)
+	res, err := g.db.Exec(query, genreId)
+	return affectOne(res, err, filmoteka.ErrGenreNotFound)
+}
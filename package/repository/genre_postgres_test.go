@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	filmoteka "vk_restAPI"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenrePostgres_CreateGenre(t *testing.T) {
+	type mockBehavior func(mock sqlmock.Sqlmock)
+
+	testTable := []struct {
+		name          string
+		mockBehavior  mockBehavior
+		expectedId    int
+		expectedError error
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO genres (name) VALUES ($1) RETURNING id")).
+					WithArgs("вестерн").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+			},
+			expectedId: 9,
+		},
+		{
+			name: "Name Taken",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO genres (name) VALUES ($1) RETURNING id")).
+					WithArgs("вестерн").WillReturnError(&pq.Error{Code: pgUniqueViolation})
+			},
+			expectedError: filmoteka.ErrGenreExists,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+			}
+			defer db.Close()
+
+			repo := NewGenrePostgres(sqlx.NewDb(db, "sqlmock"))
+			testCase.mockBehavior(mock)
+
+			id, err := repo.CreateGenre(filmoteka.Genre{Name: "вестерн"})
+
+			if testCase.expectedError != nil {
+				assert.ErrorIs(t, err, testCase.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expectedId, id)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGenrePostgres_UpdateGenre(t *testing.T) {
+	type mockBehavior func(mock sqlmock.Sqlmock)
+
+	testTable := []struct {
+		name          string
+		mockBehavior  mockBehavior
+		expectedError error
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE genres SET name=$1 WHERE id=$2")).
+					WithArgs("фэнтези", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE genres SET name=$1 WHERE id=$2")).
+					WithArgs("фэнтези", 1).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedError: filmoteka.ErrGenreNotFound,
+		},
+		{
+			name: "Name Taken",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE genres SET name=$1 WHERE id=$2")).
+					WithArgs("фэнтези", 1).WillReturnError(&pq.Error{Code: pgUniqueViolation})
+			},
+			expectedError: filmoteka.ErrGenreExists,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+			}
+			defer db.Close()
+
+			repo := NewGenrePostgres(sqlx.NewDb(db, "sqlmock"))
+			testCase.mockBehavior(mock)
+
+			err = repo.UpdateGenre(1, filmoteka.Genre{Name: "фэнтези"})
+
+			if testCase.expectedError != nil {
+				assert.ErrorIs(t, err, testCase.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
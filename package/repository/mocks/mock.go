@@ -0,0 +1,1121 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	reflect "reflect"
+	vk_restAPI "vk_restAPI"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAuthorization is a mock of Authorization interface.
+type MockAuthorization struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthorizationMockRecorder
+}
+
+// MockAuthorizationMockRecorder is the mock recorder for MockAuthorization.
+type MockAuthorizationMockRecorder struct {
+	mock *MockAuthorization
+}
+
+// NewMockAuthorization creates a new mock instance.
+func NewMockAuthorization(ctrl *gomock.Controller) *MockAuthorization {
+	mock := &MockAuthorization{ctrl: ctrl}
+	mock.recorder = &MockAuthorizationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthorization) EXPECT() *MockAuthorizationMockRecorder {
+	return m.recorder
+}
+
+// CreateUser mocks base method.
+func (m *MockAuthorization) CreateUser(user vk_restAPI.User) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", user)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockAuthorizationMockRecorder) CreateUser(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockAuthorization)(nil).CreateUser), user)
+}
+
+// GetUser mocks base method.
+func (m *MockAuthorization) GetUser(username string) (vk_restAPI.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", username)
+	ret0, _ := ret[0].(vk_restAPI.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockAuthorizationMockRecorder) GetUser(username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockAuthorization)(nil).GetUser), username)
+}
+
+// GetUserRole mocks base method.
+func (m *MockAuthorization) GetUserRole(id int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRole", id)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRole indicates an expected call of GetUserRole.
+func (mr *MockAuthorizationMockRecorder) GetUserRole(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRole", reflect.TypeOf((*MockAuthorization)(nil).GetUserRole), id)
+}
+
+// SetUserRole mocks base method.
+func (m *MockAuthorization) SetUserRole(id int, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserRole", id, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserRole indicates an expected call of SetUserRole.
+func (mr *MockAuthorizationMockRecorder) SetUserRole(id, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserRole", reflect.TypeOf((*MockAuthorization)(nil).SetUserRole), id, role)
+}
+
+// UpdatePasswordHash mocks base method.
+func (m *MockAuthorization) UpdatePasswordHash(id int, passwordHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePasswordHash", id, passwordHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePasswordHash indicates an expected call of UpdatePasswordHash.
+func (mr *MockAuthorizationMockRecorder) UpdatePasswordHash(id, passwordHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePasswordHash", reflect.TypeOf((*MockAuthorization)(nil).UpdatePasswordHash), id, passwordHash)
+}
+
+// MockRefreshTokens is a mock of RefreshTokens interface.
+type MockRefreshTokens struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefreshTokensMockRecorder
+}
+
+// MockRefreshTokensMockRecorder is the mock recorder for MockRefreshTokens.
+type MockRefreshTokensMockRecorder struct {
+	mock *MockRefreshTokens
+}
+
+// NewMockRefreshTokens creates a new mock instance.
+func NewMockRefreshTokens(ctrl *gomock.Controller) *MockRefreshTokens {
+	mock := &MockRefreshTokens{ctrl: ctrl}
+	mock.recorder = &MockRefreshTokensMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRefreshTokens) EXPECT() *MockRefreshTokensMockRecorder {
+	return m.recorder
+}
+
+// CreateRefreshToken mocks base method.
+func (m *MockRefreshTokens) CreateRefreshToken(token vk_restAPI.RefreshToken) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefreshToken", token)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRefreshToken indicates an expected call of CreateRefreshToken.
+func (mr *MockRefreshTokensMockRecorder) CreateRefreshToken(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefreshToken", reflect.TypeOf((*MockRefreshTokens)(nil).CreateRefreshToken), token)
+}
+
+// GetRefreshToken mocks base method.
+func (m *MockRefreshTokens) GetRefreshToken(tokenHash string) (vk_restAPI.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefreshToken", tokenHash)
+	ret0, _ := ret[0].(vk_restAPI.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefreshToken indicates an expected call of GetRefreshToken.
+func (mr *MockRefreshTokensMockRecorder) GetRefreshToken(tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshToken", reflect.TypeOf((*MockRefreshTokens)(nil).GetRefreshToken), tokenHash)
+}
+
+// RevokeRefreshTokenFamily mocks base method.
+func (m *MockRefreshTokens) RevokeRefreshTokenFamily(familyId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRefreshTokenFamily", familyId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRefreshTokenFamily indicates an expected call of RevokeRefreshTokenFamily.
+func (mr *MockRefreshTokensMockRecorder) RevokeRefreshTokenFamily(familyId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRefreshTokenFamily", reflect.TypeOf((*MockRefreshTokens)(nil).RevokeRefreshTokenFamily), familyId)
+}
+
+// RotateRefreshToken mocks base method.
+func (m *MockRefreshTokens) RotateRefreshToken(id, replacedBy int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateRefreshToken", id, replacedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RotateRefreshToken indicates an expected call of RotateRefreshToken.
+func (mr *MockRefreshTokensMockRecorder) RotateRefreshToken(id, replacedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateRefreshToken", reflect.TypeOf((*MockRefreshTokens)(nil).RotateRefreshToken), id, replacedBy)
+}
+
+// MockActors is a mock of Actors interface.
+type MockActors struct {
+	ctrl     *gomock.Controller
+	recorder *MockActorsMockRecorder
+}
+
+// MockActorsMockRecorder is the mock recorder for MockActors.
+type MockActorsMockRecorder struct {
+	mock *MockActors
+}
+
+// NewMockActors creates a new mock instance.
+func NewMockActors(ctrl *gomock.Controller) *MockActors {
+	mock := &MockActors{ctrl: ctrl}
+	mock.recorder = &MockActorsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockActors) EXPECT() *MockActorsMockRecorder {
+	return m.recorder
+}
+
+// CreateActor mocks base method.
+func (m *MockActors) CreateActor(actor vk_restAPI.Actors) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateActor", actor)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateActor indicates an expected call of CreateActor.
+func (mr *MockActorsMockRecorder) CreateActor(actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateActor", reflect.TypeOf((*MockActors)(nil).CreateActor), actor)
+}
+
+// DeleteActor mocks base method.
+func (m *MockActors) DeleteActor(actorId int, mode string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteActor", actorId, mode)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteActor indicates an expected call of DeleteActor.
+func (mr *MockActorsMockRecorder) DeleteActor(actorId, mode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteActor", reflect.TypeOf((*MockActors)(nil).DeleteActor), actorId, mode)
+}
+
+// UpdateActor mocks base method.
+func (m *MockActors) UpdateActor(actorId int, input vk_restAPI.UpdateActors, version int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateActor", actorId, input, version)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateActor indicates an expected call of UpdateActor.
+func (mr *MockActorsMockRecorder) UpdateActor(actorId, input, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateActor", reflect.TypeOf((*MockActors)(nil).UpdateActor), actorId, input, version)
+}
+
+// MockMovies is a mock of Movies interface.
+type MockMovies struct {
+	ctrl     *gomock.Controller
+	recorder *MockMoviesMockRecorder
+}
+
+// MockMoviesMockRecorder is the mock recorder for MockMovies.
+type MockMoviesMockRecorder struct {
+	mock *MockMovies
+}
+
+// NewMockMovies creates a new mock instance.
+func NewMockMovies(ctrl *gomock.Controller) *MockMovies {
+	mock := &MockMovies{ctrl: ctrl}
+	mock.recorder = &MockMoviesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMovies) EXPECT() *MockMoviesMockRecorder {
+	return m.recorder
+}
+
+// CreateMovie mocks base method.
+func (m *MockMovies) CreateMovie(movie vk_restAPI.Movies) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMovie", movie)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMovie indicates an expected call of CreateMovie.
+func (mr *MockMoviesMockRecorder) CreateMovie(movie interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMovie", reflect.TypeOf((*MockMovies)(nil).CreateMovie), movie)
+}
+
+// DeleteMovie mocks base method.
+func (m *MockMovies) DeleteMovie(movieId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMovie", movieId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMovie indicates an expected call of DeleteMovie.
+func (mr *MockMoviesMockRecorder) DeleteMovie(movieId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMovie", reflect.TypeOf((*MockMovies)(nil).DeleteMovie), movieId)
+}
+
+// SetMovieActors mocks base method.
+func (m *MockMovies) SetMovieActors(movieId int, cast []vk_restAPI.CastEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMovieActors", movieId, cast)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMovieActors indicates an expected call of SetMovieActors.
+func (mr *MockMoviesMockRecorder) SetMovieActors(movieId, cast interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMovieActors", reflect.TypeOf((*MockMovies)(nil).SetMovieActors), movieId, cast)
+}
+
+// SetMovieCrew mocks base method.
+func (m *MockMovies) SetMovieCrew(movieId int, crew []vk_restAPI.CrewEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMovieCrew", movieId, crew)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMovieCrew indicates an expected call of SetMovieCrew.
+func (mr *MockMoviesMockRecorder) SetMovieCrew(movieId, crew interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMovieCrew", reflect.TypeOf((*MockMovies)(nil).SetMovieCrew), movieId, crew)
+}
+
+// SetMovieGenres mocks base method.
+func (m *MockMovies) SetMovieGenres(movieId int, genreIDs []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMovieGenres", movieId, genreIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMovieGenres indicates an expected call of SetMovieGenres.
+func (mr *MockMoviesMockRecorder) SetMovieGenres(movieId, genreIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMovieGenres", reflect.TypeOf((*MockMovies)(nil).SetMovieGenres), movieId, genreIDs)
+}
+
+// MockGenres is a mock of Genres interface.
+type MockGenres struct {
+	ctrl     *gomock.Controller
+	recorder *MockGenresMockRecorder
+}
+
+// MockGenresMockRecorder is the mock recorder for MockGenres.
+type MockGenresMockRecorder struct {
+	mock *MockGenres
+}
+
+// NewMockGenres creates a new mock instance.
+func NewMockGenres(ctrl *gomock.Controller) *MockGenres {
+	mock := &MockGenres{ctrl: ctrl}
+	mock.recorder = &MockGenresMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGenres) EXPECT() *MockGenresMockRecorder {
+	return m.recorder
+}
+
+// CreateGenre mocks base method.
+func (m *MockGenres) CreateGenre(genre vk_restAPI.Genre) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGenre", genre)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGenre indicates an expected call of CreateGenre.
+func (mr *MockGenresMockRecorder) CreateGenre(genre interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGenre", reflect.TypeOf((*MockGenres)(nil).CreateGenre), genre)
+}
+
+// DeleteGenre mocks base method.
+func (m *MockGenres) DeleteGenre(genreId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGenre", genreId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGenre indicates an expected call of DeleteGenre.
+func (mr *MockGenresMockRecorder) DeleteGenre(genreId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGenre", reflect.TypeOf((*MockGenres)(nil).DeleteGenre), genreId)
+}
+
+// GetGenreById mocks base method.
+func (m *MockGenres) GetGenreById(genreId int) (vk_restAPI.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGenreById", genreId)
+	ret0, _ := ret[0].(vk_restAPI.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGenreById indicates an expected call of GetGenreById.
+func (mr *MockGenresMockRecorder) GetGenreById(genreId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenreById", reflect.TypeOf((*MockGenres)(nil).GetGenreById), genreId)
+}
+
+// GetGenres mocks base method.
+func (m *MockGenres) GetGenres() ([]vk_restAPI.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGenres")
+	ret0, _ := ret[0].([]vk_restAPI.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGenres indicates an expected call of GetGenres.
+func (mr *MockGenresMockRecorder) GetGenres() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenres", reflect.TypeOf((*MockGenres)(nil).GetGenres))
+}
+
+// UpdateGenre mocks base method.
+func (m *MockGenres) UpdateGenre(genreId int, genre vk_restAPI.Genre) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGenre", genreId, genre)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateGenre indicates an expected call of UpdateGenre.
+func (mr *MockGenresMockRecorder) UpdateGenre(genreId, genre interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGenre", reflect.TypeOf((*MockGenres)(nil).UpdateGenre), genreId, genre)
+}
+
+// MockPeople is a mock of People interface.
+type MockPeople struct {
+	ctrl     *gomock.Controller
+	recorder *MockPeopleMockRecorder
+}
+
+// MockPeopleMockRecorder is the mock recorder for MockPeople.
+type MockPeopleMockRecorder struct {
+	mock *MockPeople
+}
+
+// NewMockPeople creates a new mock instance.
+func NewMockPeople(ctrl *gomock.Controller) *MockPeople {
+	mock := &MockPeople{ctrl: ctrl}
+	mock.recorder = &MockPeopleMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPeople) EXPECT() *MockPeopleMockRecorder {
+	return m.recorder
+}
+
+// CreatePerson mocks base method.
+func (m *MockPeople) CreatePerson(person vk_restAPI.Person) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePerson", person)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePerson indicates an expected call of CreatePerson.
+func (mr *MockPeopleMockRecorder) CreatePerson(person interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePerson", reflect.TypeOf((*MockPeople)(nil).CreatePerson), person)
+}
+
+// DeletePerson mocks base method.
+func (m *MockPeople) DeletePerson(personId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePerson", personId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePerson indicates an expected call of DeletePerson.
+func (mr *MockPeopleMockRecorder) DeletePerson(personId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePerson", reflect.TypeOf((*MockPeople)(nil).DeletePerson), personId)
+}
+
+// GetPeople mocks base method.
+func (m *MockPeople) GetPeople(params vk_restAPI.PersonListParams) (vk_restAPI.PeopleList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPeople", params)
+	ret0, _ := ret[0].(vk_restAPI.PeopleList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPeople indicates an expected call of GetPeople.
+func (mr *MockPeopleMockRecorder) GetPeople(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPeople", reflect.TypeOf((*MockPeople)(nil).GetPeople), params)
+}
+
+// GetPersonById mocks base method.
+func (m *MockPeople) GetPersonById(personId int) (vk_restAPI.PersonWithCredits, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPersonById", personId)
+	ret0, _ := ret[0].(vk_restAPI.PersonWithCredits)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPersonById indicates an expected call of GetPersonById.
+func (mr *MockPeopleMockRecorder) GetPersonById(personId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPersonById", reflect.TypeOf((*MockPeople)(nil).GetPersonById), personId)
+}
+
+// UpdatePerson mocks base method.
+func (m *MockPeople) UpdatePerson(personId int, input vk_restAPI.UpdatePerson, version int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePerson", personId, input, version)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePerson indicates an expected call of UpdatePerson.
+func (mr *MockPeopleMockRecorder) UpdatePerson(personId, input, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePerson", reflect.TypeOf((*MockPeople)(nil).UpdatePerson), personId, input, version)
+}
+
+// MockReviews is a mock of Reviews interface.
+type MockReviews struct {
+	ctrl     *gomock.Controller
+	recorder *MockReviewsMockRecorder
+}
+
+// MockReviewsMockRecorder is the mock recorder for MockReviews.
+type MockReviewsMockRecorder struct {
+	mock *MockReviews
+}
+
+// NewMockReviews creates a new mock instance.
+func NewMockReviews(ctrl *gomock.Controller) *MockReviews {
+	mock := &MockReviews{ctrl: ctrl}
+	mock.recorder = &MockReviewsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReviews) EXPECT() *MockReviewsMockRecorder {
+	return m.recorder
+}
+
+// CreateReview mocks base method.
+func (m *MockReviews) CreateReview(movieId, userId int, input vk_restAPI.ReviewInput) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReview", movieId, userId, input)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReview indicates an expected call of CreateReview.
+func (mr *MockReviewsMockRecorder) CreateReview(movieId, userId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReview", reflect.TypeOf((*MockReviews)(nil).CreateReview), movieId, userId, input)
+}
+
+// DeleteReview mocks base method.
+func (m *MockReviews) DeleteReview(reviewId, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReview", reviewId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReview indicates an expected call of DeleteReview.
+func (mr *MockReviewsMockRecorder) DeleteReview(reviewId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReview", reflect.TypeOf((*MockReviews)(nil).DeleteReview), reviewId, userId)
+}
+
+// GetReviews mocks base method.
+func (m *MockReviews) GetReviews(params vk_restAPI.ReviewListParams) (vk_restAPI.ReviewsList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviews", params)
+	ret0, _ := ret[0].(vk_restAPI.ReviewsList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviews indicates an expected call of GetReviews.
+func (mr *MockReviewsMockRecorder) GetReviews(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviews", reflect.TypeOf((*MockReviews)(nil).GetReviews), params)
+}
+
+// SetReviewStatus mocks base method.
+func (m *MockReviews) SetReviewStatus(reviewId int, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReviewStatus", reviewId, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetReviewStatus indicates an expected call of SetReviewStatus.
+func (mr *MockReviewsMockRecorder) SetReviewStatus(reviewId, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReviewStatus", reflect.TypeOf((*MockReviews)(nil).SetReviewStatus), reviewId, status)
+}
+
+// UpdateReview mocks base method.
+func (m *MockReviews) UpdateReview(reviewId, userId int, input vk_restAPI.UpdateReview) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReview", reviewId, userId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateReview indicates an expected call of UpdateReview.
+func (mr *MockReviewsMockRecorder) UpdateReview(reviewId, userId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReview", reflect.TypeOf((*MockReviews)(nil).UpdateReview), reviewId, userId, input)
+}
+
+// MockCollections is a mock of Collections interface.
+type MockCollections struct {
+	ctrl     *gomock.Controller
+	recorder *MockCollectionsMockRecorder
+}
+
+// MockCollectionsMockRecorder is the mock recorder for MockCollections.
+type MockCollectionsMockRecorder struct {
+	mock *MockCollections
+}
+
+// NewMockCollections creates a new mock instance.
+func NewMockCollections(ctrl *gomock.Controller) *MockCollections {
+	mock := &MockCollections{ctrl: ctrl}
+	mock.recorder = &MockCollectionsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCollections) EXPECT() *MockCollectionsMockRecorder {
+	return m.recorder
+}
+
+// AddCollectionMovie mocks base method.
+func (m *MockCollections) AddCollectionMovie(collectionId, userId, movieId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCollectionMovie", collectionId, userId, movieId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddCollectionMovie indicates an expected call of AddCollectionMovie.
+func (mr *MockCollectionsMockRecorder) AddCollectionMovie(collectionId, userId, movieId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCollectionMovie", reflect.TypeOf((*MockCollections)(nil).AddCollectionMovie), collectionId, userId, movieId)
+}
+
+// CreateCollection mocks base method.
+func (m *MockCollections) CreateCollection(userId int, input vk_restAPI.CollectionInput, slug string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCollection", userId, input, slug)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCollection indicates an expected call of CreateCollection.
+func (mr *MockCollectionsMockRecorder) CreateCollection(userId, input, slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCollection", reflect.TypeOf((*MockCollections)(nil).CreateCollection), userId, input, slug)
+}
+
+// DeleteCollection mocks base method.
+func (m *MockCollections) DeleteCollection(collectionId, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCollection", collectionId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCollection indicates an expected call of DeleteCollection.
+func (mr *MockCollectionsMockRecorder) DeleteCollection(collectionId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCollection", reflect.TypeOf((*MockCollections)(nil).DeleteCollection), collectionId, userId)
+}
+
+// GetCollection mocks base method.
+func (m *MockCollections) GetCollection(collectionId, userId int) (vk_restAPI.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollection", collectionId, userId)
+	ret0, _ := ret[0].(vk_restAPI.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollection indicates an expected call of GetCollection.
+func (mr *MockCollectionsMockRecorder) GetCollection(collectionId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollection", reflect.TypeOf((*MockCollections)(nil).GetCollection), collectionId, userId)
+}
+
+// GetCollectionMovies mocks base method.
+func (m *MockCollections) GetCollectionMovies(collectionId int, params vk_restAPI.CollectionMoviesParams) (vk_restAPI.CollectionMoviesList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollectionMovies", collectionId, params)
+	ret0, _ := ret[0].(vk_restAPI.CollectionMoviesList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollectionMovies indicates an expected call of GetCollectionMovies.
+func (mr *MockCollectionsMockRecorder) GetCollectionMovies(collectionId, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollectionMovies", reflect.TypeOf((*MockCollections)(nil).GetCollectionMovies), collectionId, params)
+}
+
+// GetCollections mocks base method.
+func (m *MockCollections) GetCollections(userId int) ([]vk_restAPI.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollections", userId)
+	ret0, _ := ret[0].([]vk_restAPI.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollections indicates an expected call of GetCollections.
+func (mr *MockCollectionsMockRecorder) GetCollections(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollections", reflect.TypeOf((*MockCollections)(nil).GetCollections), userId)
+}
+
+// GetSharedCollection mocks base method.
+func (m *MockCollections) GetSharedCollection(slug string) (vk_restAPI.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedCollection", slug)
+	ret0, _ := ret[0].(vk_restAPI.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedCollection indicates an expected call of GetSharedCollection.
+func (mr *MockCollectionsMockRecorder) GetSharedCollection(slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedCollection", reflect.TypeOf((*MockCollections)(nil).GetSharedCollection), slug)
+}
+
+// RemoveCollectionMovie mocks base method.
+func (m *MockCollections) RemoveCollectionMovie(collectionId, userId, movieId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCollectionMovie", collectionId, userId, movieId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveCollectionMovie indicates an expected call of RemoveCollectionMovie.
+func (mr *MockCollectionsMockRecorder) RemoveCollectionMovie(collectionId, userId, movieId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCollectionMovie", reflect.TypeOf((*MockCollections)(nil).RemoveCollectionMovie), collectionId, userId, movieId)
+}
+
+// ReorderCollection mocks base method.
+func (m *MockCollections) ReorderCollection(collectionId, userId int, movieIds []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReorderCollection", collectionId, userId, movieIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReorderCollection indicates an expected call of ReorderCollection.
+func (mr *MockCollectionsMockRecorder) ReorderCollection(collectionId, userId, movieIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderCollection", reflect.TypeOf((*MockCollections)(nil).ReorderCollection), collectionId, userId, movieIds)
+}
+
+// UpdateCollection mocks base method.
+func (m *MockCollections) UpdateCollection(collectionId, userId int, input vk_restAPI.UpdateCollection, slug string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCollection", collectionId, userId, input, slug)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCollection indicates an expected call of UpdateCollection.
+func (mr *MockCollectionsMockRecorder) UpdateCollection(collectionId, userId, input, slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCollection", reflect.TypeOf((*MockCollections)(nil).UpdateCollection), collectionId, userId, input, slug)
+}
+
+// MockWatched is a mock of Watched interface.
+type MockWatched struct {
+	ctrl     *gomock.Controller
+	recorder *MockWatchedMockRecorder
+}
+
+// MockWatchedMockRecorder is the mock recorder for MockWatched.
+type MockWatchedMockRecorder struct {
+	mock *MockWatched
+}
+
+// NewMockWatched creates a new mock instance.
+func NewMockWatched(ctrl *gomock.Controller) *MockWatched {
+	mock := &MockWatched{ctrl: ctrl}
+	mock.recorder = &MockWatchedMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWatched) EXPECT() *MockWatchedMockRecorder {
+	return m.recorder
+}
+
+// GetMovieStates mocks base method.
+func (m *MockWatched) GetMovieStates(userId int, movieIds []int) (map[int]vk_restAPI.MovieState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMovieStates", userId, movieIds)
+	ret0, _ := ret[0].(map[int]vk_restAPI.MovieState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMovieStates indicates an expected call of GetMovieStates.
+func (mr *MockWatchedMockRecorder) GetMovieStates(userId, movieIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovieStates", reflect.TypeOf((*MockWatched)(nil).GetMovieStates), userId, movieIds)
+}
+
+// GetWatched mocks base method.
+func (m *MockWatched) GetWatched(userId int, params vk_restAPI.WatchedListParams) (vk_restAPI.WatchedList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatched", userId, params)
+	ret0, _ := ret[0].(vk_restAPI.WatchedList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWatched indicates an expected call of GetWatched.
+func (mr *MockWatchedMockRecorder) GetWatched(userId, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatched", reflect.TypeOf((*MockWatched)(nil).GetWatched), userId, params)
+}
+
+// GetWatchedStats mocks base method.
+func (m *MockWatched) GetWatchedStats(userId int) (vk_restAPI.WatchedStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatchedStats", userId)
+	ret0, _ := ret[0].(vk_restAPI.WatchedStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWatchedStats indicates an expected call of GetWatchedStats.
+func (mr *MockWatchedMockRecorder) GetWatchedStats(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatchedStats", reflect.TypeOf((*MockWatched)(nil).GetWatchedStats), userId)
+}
+
+// MarkWatched mocks base method.
+func (m *MockWatched) MarkWatched(userId, movieId int, input vk_restAPI.WatchedInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkWatched", userId, movieId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkWatched indicates an expected call of MarkWatched.
+func (mr *MockWatchedMockRecorder) MarkWatched(userId, movieId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWatched", reflect.TypeOf((*MockWatched)(nil).MarkWatched), userId, movieId, input)
+}
+
+// UnmarkWatched mocks base method.
+func (m *MockWatched) UnmarkWatched(userId, movieId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnmarkWatched", userId, movieId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnmarkWatched indicates an expected call of UnmarkWatched.
+func (mr *MockWatchedMockRecorder) UnmarkWatched(userId, movieId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnmarkWatched", reflect.TypeOf((*MockWatched)(nil).UnmarkWatched), userId, movieId)
+}
+
+// MockActorsWithMovies is a mock of ActorsWithMovies interface.
+type MockActorsWithMovies struct {
+	ctrl     *gomock.Controller
+	recorder *MockActorsWithMoviesMockRecorder
+}
+
+// MockActorsWithMoviesMockRecorder is the mock recorder for MockActorsWithMovies.
+type MockActorsWithMoviesMockRecorder struct {
+	mock *MockActorsWithMovies
+}
+
+// NewMockActorsWithMovies creates a new mock instance.
+func NewMockActorsWithMovies(ctrl *gomock.Controller) *MockActorsWithMovies {
+	mock := &MockActorsWithMovies{ctrl: ctrl}
+	mock.recorder = &MockActorsWithMoviesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockActorsWithMovies) EXPECT() *MockActorsWithMoviesMockRecorder {
+	return m.recorder
+}
+
+// GetActorById mocks base method.
+func (m *MockActorsWithMovies) GetActorById(actorId int) (vk_restAPI.ActorsWithMovies, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActorById", actorId)
+	ret0, _ := ret[0].(vk_restAPI.ActorsWithMovies)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActorById indicates an expected call of GetActorById.
+func (mr *MockActorsWithMoviesMockRecorder) GetActorById(actorId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorById", reflect.TypeOf((*MockActorsWithMovies)(nil).GetActorById), actorId)
+}
+
+// GetActors mocks base method.
+func (m *MockActorsWithMovies) GetActors(params vk_restAPI.ActorListParams) (vk_restAPI.ActorsList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActors", params)
+	ret0, _ := ret[0].(vk_restAPI.ActorsList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActors indicates an expected call of GetActors.
+func (mr *MockActorsWithMoviesMockRecorder) GetActors(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActors", reflect.TypeOf((*MockActorsWithMovies)(nil).GetActors), params)
+}
+
+// MockMoviesWithActors is a mock of MoviesWithActors interface.
+type MockMoviesWithActors struct {
+	ctrl     *gomock.Controller
+	recorder *MockMoviesWithActorsMockRecorder
+}
+
+// MockMoviesWithActorsMockRecorder is the mock recorder for MockMoviesWithActors.
+type MockMoviesWithActorsMockRecorder struct {
+	mock *MockMoviesWithActors
+}
+
+// NewMockMoviesWithActors creates a new mock instance.
+func NewMockMoviesWithActors(ctrl *gomock.Controller) *MockMoviesWithActors {
+	mock := &MockMoviesWithActors{ctrl: ctrl}
+	mock.recorder = &MockMoviesWithActorsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMoviesWithActors) EXPECT() *MockMoviesWithActorsMockRecorder {
+	return m.recorder
+}
+
+// GetMovieById mocks base method.
+func (m *MockMoviesWithActors) GetMovieById(movieId int) (vk_restAPI.MoviesWithActors, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMovieById", movieId)
+	ret0, _ := ret[0].(vk_restAPI.MoviesWithActors)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMovieById indicates an expected call of GetMovieById.
+func (mr *MockMoviesWithActorsMockRecorder) GetMovieById(movieId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovieById", reflect.TypeOf((*MockMoviesWithActors)(nil).GetMovieById), movieId)
+}
+
+// GetMovies mocks base method.
+func (m *MockMoviesWithActors) GetMovies(params vk_restAPI.MovieListParams) (vk_restAPI.MoviesList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMovies", params)
+	ret0, _ := ret[0].(vk_restAPI.MoviesList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMovies indicates an expected call of GetMovies.
+func (mr *MockMoviesWithActorsMockRecorder) GetMovies(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovies", reflect.TypeOf((*MockMoviesWithActors)(nil).GetMovies), params)
+}
+
+// SearchMovieByActorName mocks base method.
+func (m *MockMoviesWithActors) SearchMovieByActorName(fragment string) ([]vk_restAPI.MoviesWithActors, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchMovieByActorName", fragment)
+	ret0, _ := ret[0].([]vk_restAPI.MoviesWithActors)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchMovieByActorName indicates an expected call of SearchMovieByActorName.
+func (mr *MockMoviesWithActorsMockRecorder) SearchMovieByActorName(fragment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchMovieByActorName", reflect.TypeOf((*MockMoviesWithActors)(nil).SearchMovieByActorName), fragment)
+}
+
+// SearchMovies mocks base method.
+func (m *MockMoviesWithActors) SearchMovies(params vk_restAPI.MovieSearchParams) (vk_restAPI.MovieSearchList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchMovies", params)
+	ret0, _ := ret[0].(vk_restAPI.MovieSearchList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchMovies indicates an expected call of SearchMovies.
+func (mr *MockMoviesWithActorsMockRecorder) SearchMovies(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchMovies", reflect.TypeOf((*MockMoviesWithActors)(nil).SearchMovies), params)
+}
+
+// SearchMoviesByTitle mocks base method.
+func (m *MockMoviesWithActors) SearchMoviesByTitle(fragment string) ([]vk_restAPI.MoviesWithActors, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchMoviesByTitle", fragment)
+	ret0, _ := ret[0].([]vk_restAPI.MoviesWithActors)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchMoviesByTitle indicates an expected call of SearchMoviesByTitle.
+func (mr *MockMoviesWithActorsMockRecorder) SearchMoviesByTitle(fragment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchMoviesByTitle", reflect.TypeOf((*MockMoviesWithActors)(nil).SearchMoviesByTitle), fragment)
+}
+
+// UpdateMovie mocks base method.
+func (m *MockMoviesWithActors) UpdateMovie(movieId int, input vk_restAPI.UpdateMovies, version int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMovie", movieId, input, version)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMovie indicates an expected call of UpdateMovie.
+func (mr *MockMoviesWithActorsMockRecorder) UpdateMovie(movieId, input, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMovie", reflect.TypeOf((*MockMoviesWithActors)(nil).UpdateMovie), movieId, input, version)
+}
+
+// MockSearch is a mock of Search interface.
+type MockSearch struct {
+	ctrl     *gomock.Controller
+	recorder *MockSearchMockRecorder
+}
+
+// MockSearchMockRecorder is the mock recorder for MockSearch.
+type MockSearchMockRecorder struct {
+	mock *MockSearch
+}
+
+// NewMockSearch creates a new mock instance.
+func NewMockSearch(ctrl *gomock.Controller) *MockSearch {
+	mock := &MockSearch{ctrl: ctrl}
+	mock.recorder = &MockSearchMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSearch) EXPECT() *MockSearchMockRecorder {
+	return m.recorder
+}
+
+// Autocomplete mocks base method.
+func (m *MockSearch) Autocomplete(params vk_restAPI.FuzzySearchParams) ([]vk_restAPI.Suggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Autocomplete", params)
+	ret0, _ := ret[0].([]vk_restAPI.Suggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Autocomplete indicates an expected call of Autocomplete.
+func (mr *MockSearchMockRecorder) Autocomplete(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Autocomplete", reflect.TypeOf((*MockSearch)(nil).Autocomplete), params)
+}
+
+// FuzzySearch mocks base method.
+func (m *MockSearch) FuzzySearch(params vk_restAPI.FuzzySearchParams) (vk_restAPI.FuzzySearchResults, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FuzzySearch", params)
+	ret0, _ := ret[0].(vk_restAPI.FuzzySearchResults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FuzzySearch indicates an expected call of FuzzySearch.
+func (mr *MockSearchMockRecorder) FuzzySearch(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FuzzySearch", reflect.TypeOf((*MockSearch)(nil).FuzzySearch), params)
+}
@@ -4,287 +4,575 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"log"
+	"strconv"
 	"strings"
 	filmoteka "vk_restAPI"
 
-	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type MoviePostgres struct {
-	db *sqlx.DB
+	db Executor
 }
 
-func NewMoviePostgres(db *sqlx.DB) *MoviePostgres {
+func NewMoviePostgres(db Executor) *MoviePostgres {
 	return &MoviePostgres{db: db}
 }
 
-func (m *MoviePostgres) CreateMovie(movie filmoteka.Movies, actorIDs []int) (int, error) {
+func (m *MoviePostgres) CreateMovie(movie filmoteka.Movies) (int, error) {
 	var id int
 
-	var exisitngID int
+	err := withTx(m.db, func(tx Executor) error {
+		var exisitngID int
 
-	query := fmt.Sprintf("SELECT id FROM %s WHERE title = $1 AND description = $2", moviesTable)
-	row := m.db.QueryRow(query, movie.Title, movie.Description)
-	if err := row.Scan(&exisitngID); err == nil {
-		return exisitngID, errors.New("movie with the same parameters already exists")
-	} else if err != sql.ErrNoRows {
-		return 0, err
+		query := fmt.Sprintf("SELECT id FROM %s WHERE title = $1 AND description = $2", moviesTable)
+		row := tx.QueryRow(query, movie.Title, movie.Description)
+		if err := row.Scan(&exisitngID); err == nil {
+			id = exisitngID
+			return filmoteka.ErrMovieExists
+		} else if err != sql.ErrNoRows {
+			return err
+		}
+
+		query = fmt.Sprintf(`INSERT INTO %s (title, original_title, description, release_date, rating, country, runtime, age_rating)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`, moviesTable)
+		row = tx.QueryRow(query, movie.Title, movie.OriginalTitle, movie.Description, movie.ReleaseDate, movie.Rating,
+			movie.Country, movie.Runtime, movie.AgeRating)
+		return dbError(row.Scan(&id))
+	})
+
+	return id, err
+}
+
+// SetMovieActors replaces the cast of a movie. Unknown person IDs fail
+// the whole update with a validation error listing them, and an actor
+// listed twice keeps their first entry. Entries are billed by their position in cast
+// unless they set BillingOrder. Anyone may be cast, which makes them an
+// actor.
+func (m *MoviePostgres) SetMovieActors(movieId int, cast []filmoteka.CastEntry) error {
+	actorIDs := make([]int64, 0, len(cast))
+	characters := make([]string, 0, len(cast))
+	billing := make([]int64, 0, len(cast))
+	roleTypes := make([]string, 0, len(cast))
+
+	for i, entry := range cast {
+		if entry.BillingOrder == 0 {
+			entry.BillingOrder = i + 1
+		}
+		if entry.RoleType == "" {
+			entry.RoleType = filmoteka.CastSupporting
+		}
+
+		actorIDs = append(actorIDs, int64(entry.ActorId))
+		characters = append(characters, entry.CharacterName)
+		billing = append(billing, int64(entry.BillingOrder))
+		roleTypes = append(roleTypes, entry.RoleType)
 	}
 
-	query = fmt.Sprintf("INSERT INTO %s (title, description, release_date, rating) VALUES ($1, $2, $3, $4) RETURNING id", moviesTable)
-	row = m.db.QueryRow(query, movie.Title, movie.Description, movie.ReleaseDate, movie.Rating)
-	if err := row.Scan(&id); err != nil {
-		return 0, err
+	return withTx(m.db, func(tx Executor) error {
+		query := fmt.Sprintf("DELETE FROM %s WHERE movie_id=$1", moviesActorsTable)
+		if _, err := tx.Exec(query, movieId); err != nil {
+			return err
+		}
+
+		query = fmt.Sprintf(`
+			INSERT INTO %s (movie_id, actor_id, character_name, billing_order, role_type)
+			SELECT DISTINCT ON (c.actor_id) $1, c.actor_id, c.character_name, c.billing_order, c.role_type
+			FROM unnest($2::int[], $3::text[], $4::int[], $5::text[]) WITH ORDINALITY
+				AS c (actor_id, character_name, billing_order, role_type, position)
+			JOIN %s a ON a.id = c.actor_id
+			ORDER BY c.actor_id, c.position
+		`, moviesActorsTable, peopleTable)
+		res, err := tx.Exec(query, movieId, pq.Array(actorIDs), pq.Array(characters), pq.Array(billing), pq.Array(roleTypes))
+		if err != nil {
+			return dbError(err)
+		}
+		return requireKnown(tx, res, len(distinct(actorIDs)), peopleTable, "actor", actorIDs)
+	})
+}
+
+// requireKnown checks that an INSERT ... SELECT of a setter wrote want
+// rows. The SELECT joins the referenced table, so fewer rows mean some of
+// ids aren't in it; they are looked up and reported as a validation error.
+func requireKnown(tx Executor, res sql.Result, want int, table, name string, ids []int64) error {
+	inserted, err := res.RowsAffected()
+	if err != nil {
+		return err
 	}
+	if int(inserted) == want {
+		return nil
+	}
+
+	var unknown []int64
+	query := fmt.Sprintf(`
+		SELECT DISTINCT c.id
+		FROM unnest($1::int[]) AS c (id)
+		WHERE NOT EXISTS (SELECT 1 FROM %s t WHERE t.id = c.id)
+		ORDER BY c.id
+	`, table)
+	if err := tx.Select(&unknown, query, pq.Array(ids)); err != nil {
+		return err
+	}
+
+	list := make([]string, 0, len(unknown))
+	for _, id := range unknown {
+		list = append(list, strconv.FormatInt(id, 10))
+	}
+	return filmoteka.Errorf(filmoteka.ErrValidation, "unknown %s ids: %s", name, strings.Join(list, ", "))
+}
+
+// distinct returns ids without repeats, in the order they first appear.
+func distinct(ids []int64) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	unique := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// SetMovieCrew replaces the crew of a movie. Like with the cast, unknown
+// person IDs fail the update, and a job listed twice is credited once.
+func (m *MoviePostgres) SetMovieCrew(movieId int, crew []filmoteka.CrewEntry) error {
+	personIDs := make([]int64, 0, len(crew))
+	departments := make([]string, 0, len(crew))
+	jobs := make([]string, 0, len(crew))
+	credits := make(map[filmoteka.CrewEntry]bool, len(crew))
+
+	for _, entry := range crew {
+		personIDs = append(personIDs, int64(entry.PersonId))
+		departments = append(departments, entry.Department)
+		jobs = append(jobs, entry.Job)
+		credits[entry] = true
+	}
+
+	return withTx(m.db, func(tx Executor) error {
+		query := fmt.Sprintf("DELETE FROM %s WHERE movie_id=$1", moviesCrewTable)
+		if _, err := tx.Exec(query, movieId); err != nil {
+			return err
+		}
 
-	for _, actroID := range actorIDs {
-		query = fmt.Sprintf("INSERT INTO %s (movie_id, actor_id) VALUES ($1, $2)", moviesActorsTable)
-		_, err := m.db.Exec(query, id, actroID)
+		query = fmt.Sprintf(`
+			INSERT INTO %s (movie_id, person_id, department, job)
+			SELECT DISTINCT $1::int, c.person_id, c.department, c.job
+			FROM unnest($2::int[], $3::text[], $4::text[]) AS c (person_id, department, job)
+			JOIN %s p ON p.id = c.person_id
+		`, moviesCrewTable, peopleTable)
+		res, err := tx.Exec(query, movieId, pq.Array(personIDs), pq.Array(departments), pq.Array(jobs))
 		if err != nil {
-			return 0, err
+			return dbError(err)
 		}
+		return requireKnown(tx, res, len(credits), peopleTable, "person", personIDs)
+	})
+}
+
+// SetMovieGenres replaces the genres of a movie. Like with the cast,
+// unknown genre IDs fail the update.
+func (m *MoviePostgres) SetMovieGenres(movieId int, genreIDs []int) error {
+	ids := make([]int64, 0, len(genreIDs))
+	for _, id := range genreIDs {
+		ids = append(ids, int64(id))
 	}
 
-	return id, nil
+	return withTx(m.db, func(tx Executor) error {
+		query := fmt.Sprintf("DELETE FROM %s WHERE movie_id=$1", moviesGenresTable)
+		if _, err := tx.Exec(query, movieId); err != nil {
+			return err
+		}
+
+		query = fmt.Sprintf("INSERT INTO %s (movie_id, genre_id) SELECT $1, id FROM %s WHERE id = ANY($2)", moviesGenresTable, genresTable)
+		res, err := tx.Exec(query, movieId, pq.Array(ids))
+		if err != nil {
+			return dbError(err)
+		}
+		return requireKnown(tx, res, len(distinct(ids)), genresTable, "genre", ids)
+	})
 }
 
-func (m *MoviePostgres) GetMovies() ([]filmoteka.MoviesWithActors, error) {
-	var movies []filmoteka.MoviesWithActors
-	query := fmt.Sprintf(`
-    SELECT 
-        m.id, 
-        m.title, 
-        m.description, 
-        TO_CHAR (m.release_date, 'YYYY-MM-DD') AS release_date, 
-        m.rating, 
-        array_agg(a.first_name || ' ' || a.last_name) AS actors
-    FROM 
-        %s m
-    LEFT JOIN 
-        %s ma ON m.id = ma.movie_id
-    LEFT JOIN 
-        %s a ON ma.actor_id = a.id
-    GROUP BY 
-        m.id, m.title, m.description, m.release_date, m.rating
-	ORDER BY 
-		m.rating DESC
-`, moviesTable, moviesActorsTable, actorsTable)
-	err := m.db.Select(&movies, query)
-	return movies, err
+// movieSortKeys whitelists the columns a movie list can be ordered by,
+// so user input never reaches the ORDER BY clause directly.
+var movieSortKeys = map[string]sortKey{
+	filmoteka.SortByRating:      {column: "m.rating", cast: "int"},
+	filmoteka.SortByTitle:       {column: "m.title", cast: "text"},
+	filmoteka.SortByReleaseDate: {column: "m.release_date", cast: "date"},
+	filmoteka.SortById:          {column: "m.id", cast: "int"},
+}
 
+func movieSortValue(sort string) func(filmoteka.MoviesWithActors) (string, int) {
+	return func(movie filmoteka.MoviesWithActors) (string, int) {
+		switch sort {
+		case filmoteka.SortByRating:
+			return strconv.Itoa(movie.Rating), movie.Id
+		case filmoteka.SortByTitle:
+			return movie.Title, movie.Id
+		case filmoteka.SortByReleaseDate:
+			return movie.ReleaseDate, movie.Id
+		}
+		return "", movie.Id
+	}
 }
 
-func (m *MoviePostgres) GetMoviesSortedByTitle() ([]filmoteka.MoviesWithActors, error) {
+func (m *MoviePostgres) GetMovies(params filmoteka.MovieListParams) (filmoteka.MoviesList, error) {
+	var list filmoteka.MoviesList
+
+	keys, err := newKeyset(movieSortKeys, "m.id", params.Sort, params.Order)
+	if err != nil {
+		return list, err
+	}
+
+	var from cursor
+	conditions, args := movieFilters(params, nil)
+
+	//The total counts every movie matching the filters, not just this page
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s m", moviesTable)
+	if len(conditions) > 0 {
+		countQuery += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	countArgs := append([]interface{}{}, args...)
+
+	if params.Cursor != "" {
+		if from, err = decodeCursor(params.Cursor, params.Sort, params.Order); err != nil {
+			return list, err
+		}
+
+		seek, seekArgs := keys.seek(from, len(args)+1)
+		conditions = append(conditions, seek)
+		args = append(args, seekArgs...)
+		params.Offset = 0
+	}
+
+	if err := m.db.Get(&list.Total, countQuery, countArgs...); err != nil {
+		return list, err
+	}
+
+	//One extra row tells whether there is a page after this one
+	args = append(args, params.Limit+1, params.Offset)
+
+	query := movieQuery{
+		conditions: conditions,
+		orderBy:    keys.orderBy(from.Backward),
+		limit:      fmt.Sprintf("LIMIT $%d OFFSET $%d", len(args)-1, len(args)),
+	}.String()
+
 	var movies []filmoteka.MoviesWithActors
-	query := fmt.Sprintf(`
-    SELECT 
-        m.id, 
-        m.title, 
-        m.description, 
-        TO_CHAR (m.release_date, 'YYYY-MM-DD') AS release_date, 	
-        m.rating, 
-        array_agg(a.first_name || ' ' || a.last_name) AS actors
-    FROM 
-        %s m
-    LEFT JOIN 
-        %s ma ON m.id = ma.movie_id
-    LEFT JOIN 
-        %s a ON ma.actor_id = a.id
-    GROUP BY 
-        m.id, m.title, m.description, m.release_date, m.rating
-	ORDER BY 
-		m.title ASC
-`, moviesTable, moviesActorsTable, actorsTable)
-	err := m.db.Select(&movies, query)
-	return movies, err
+	if err := m.db.Select(&movies, query, args...); err != nil {
+		return list, err
+	}
+
+	started := params.Cursor != "" || params.Offset > 0
+	list.Movies, list.NextCursor, list.PrevCursor = buildPage(keys, movies, params.Limit, from, started, movieSortValue(params.Sort))
 
+	return list, nil
 }
 
-func (m *MoviePostgres) GetMoviesSortedByDate() ([]filmoteka.MoviesWithActors, error) {
-	var movies []filmoteka.MoviesWithActors
-	query := fmt.Sprintf(`
-    SELECT 
-        m.id, 
-        m.title, 
-        m.description, 
-        TO_CHAR (m.release_date, 'YYYY-MM-DD') AS release_date, 			 
-        m.rating, 
-        array_agg(a.first_name || ' ' || a.last_name) AS actors
-    FROM 
-        %s m
-    LEFT JOIN 
-        %s ma ON m.id = ma.movie_id
-    LEFT JOIN 
-        %s a ON ma.actor_id = a.id
-    GROUP BY 
-        m.id, m.title, m.description, m.release_date, m.rating
-	ORDER BY 
-		m.release_date ASC
-`, moviesTable, moviesActorsTable, actorsTable)
-	err := m.db.Select(&movies, query)
-	return movies, err
+// likeEscaper escapes the LIKE wildcards and the escape character itself.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
 
+// likePattern matches fragment anywhere in a LIKE or ILIKE that declares
+// ESCAPE '\'. Wildcards typed by the client match themselves, so "_" finds
+// an underscore rather than every row.
+func likePattern(fragment string) string {
+	return "%" + likeEscaper.Replace(fragment) + "%"
 }
 
-func (m *MoviePostgres) GetMovieById(movieId int) (filmoteka.MoviesWithActors, error) {
-	var movie filmoteka.MoviesWithActors
-	query := fmt.Sprintf(`
-    SELECT 
-        m.id, 
-        m.title, 
-        m.description, 
-        TO_CHAR (m.release_date, 'YYYY-MM-DD') AS release_date, 
-        m.rating, 
-        array_agg(a.first_name || ' ' || a.last_name) AS actors
-    FROM 
-        %s m
-    LEFT JOIN 
-        %s ma ON m.id = ma.movie_id
-    LEFT JOIN 
-        %s a ON ma.actor_id = a.id
-	WHERE 
-		m.id=$1
-    GROUP BY 
-        m.id, m.title, m.description, m.release_date, m.rating
-`, moviesTable, moviesActorsTable, actorsTable)
-	err := m.db.Get(&movie, query, movieId)
-	return movie, err
+// movieFilters turns the list filters into predicates on movies m. The
+// filter values are appended to args and numbered after the ones already
+// there. Every filter may be combined with the others.
+func movieFilters(params filmoteka.MovieListParams, args []interface{}) ([]string, []interface{}) {
+	conditions := make([]string, 0)
+
+	if params.Title != "" {
+		args = append(args, likePattern(params.Title))
+		conditions = append(conditions, fmt.Sprintf(`LOWER(m.title) LIKE LOWER($%d) ESCAPE '\'`, len(args)))
+	}
+
+	if params.Actor != "" {
+		args = append(args, likePattern(params.Actor))
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM %s fma JOIN %s fa ON fma.actor_id = fa.id
+			WHERE fma.movie_id = m.id AND (LOWER(fa.first_name) LIKE LOWER($%d) ESCAPE '\' OR LOWER(fa.last_name) LIKE LOWER($%d) ESCAPE '\'))`,
+			moviesActorsTable, peopleTable, len(args), len(args)))
+	}
+
+	if params.Director != "" {
+		args = append(args, likePattern(params.Director))
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM %s fmc JOIN %s fp ON fmc.person_id = fp.id
+			WHERE fmc.movie_id = m.id AND fmc.department = '%s' AND (LOWER(fp.first_name) LIKE LOWER($%d) ESCAPE '\' OR LOWER(fp.last_name) LIKE LOWER($%d) ESCAPE '\'))`,
+			moviesCrewTable, peopleTable, filmoteka.DepartmentDirecting, len(args), len(args)))
+	}
+
+	if params.ActorId != 0 {
+		args = append(args, params.ActorId)
+		conditions = append(conditions, fmt.Sprintf("EXISTS (SELECT 1 FROM %s fma WHERE fma.movie_id = m.id AND fma.actor_id = $%d)", moviesActorsTable, len(args)))
+	}
+
+	if len(params.GenreIds) > 0 {
+		args = append(args, pq.Array(params.GenreIds))
+		conditions = append(conditions, fmt.Sprintf("EXISTS (SELECT 1 FROM %s fmg WHERE fmg.movie_id = m.id AND fmg.genre_id = ANY($%d))", moviesGenresTable, len(args)))
+	}
+
+	if params.Country != "" {
+		args = append(args, params.Country)
+		conditions = append(conditions, fmt.Sprintf("m.country = $%d", len(args)))
+	}
+
+	if params.AgeRating != "" {
+		args = append(args, params.AgeRating)
+		conditions = append(conditions, fmt.Sprintf("m.age_rating = $%d", len(args)))
+	}
 
+	//Unknown runtimes are stored as 0 and never match a runtime range
+	if params.MinRuntime > 0 {
+		args = append(args, params.MinRuntime)
+		conditions = append(conditions, fmt.Sprintf("m.runtime >= $%d", len(args)))
+	}
+
+	if params.MaxRuntime > 0 {
+		args = append(args, params.MaxRuntime)
+		conditions = append(conditions, fmt.Sprintf("m.runtime BETWEEN 1 AND $%d", len(args)))
+	}
+
+	return conditions, args
 }
 
-func (m *MoviePostgres) DeleteMovie(movieId int) error {
+// movieQuery builds a SELECT returning movies in the one shape every read
+// shares: the movie's own columns, release_date as YYYY-MM-DD, the genres
+// ordered by name, the cast in billing order, the crew grouped by
+// department and the user rating. Genres, cast, crew and user rating are
+// each aggregated per movie in a lateral subquery LEFT JOINed to m, so
+// movies without them are kept and filters on m don't trim them.
+type movieQuery struct {
+	// with is an optional WITH clause the query starts with.
+	with string
+	// from is the FROM clause, movies m by default. It may join other
+	// relations, whose columns can then be selected with columns.
+	from       string
+	columns    []string
+	conditions []string
+	orderBy    string
+	limit      string
+}
 
-	tx, err := m.db.Begin()
-	if err != nil {
-		return err
+func (q movieQuery) String() string {
+	from := q.from
+	if from == "" {
+		from = moviesTable + " m"
 	}
-	defer tx.Rollback()
 
-	query := fmt.Sprintf("DELETE FROM %s WHERE movie_id=$1", moviesActorsTable)
-	if _, err := m.db.Exec(query, movieId); err != nil {
-		return err
+	columns := append([]string{
+		"m.id",
+		"m.title",
+		"m.original_title",
+		"m.description",
+		"TO_CHAR(m.release_date, 'YYYY-MM-DD') AS release_date",
+		"m.rating",
+		"m.country",
+		"m.runtime",
+		"m.age_rating",
+		"m.version",
+		"g.genres",
+		"c.actors",
+		"cr.crew",
+		"ur.user_rating",
+	}, q.columns...)
+
+	query := fmt.Sprintf(`%s
+		SELECT %s
+		FROM %s
+		LEFT JOIN LATERAL (
+			SELECT COALESCE(json_agg(json_build_object('id', gr.id, 'name', gr.name) ORDER BY gr.name), '[]') AS genres
+			FROM %s mg
+			JOIN %s gr ON mg.genre_id = gr.id
+			WHERE mg.movie_id = m.id
+		) g ON true
+		LEFT JOIN LATERAL (
+			SELECT COALESCE(
+				json_agg(json_build_object(
+					'id', a.id, 'first_name', a.first_name, 'last_name', a.last_name,
+					'character_name', ma.character_name, 'billing_order', ma.billing_order, 'role_type', ma.role_type
+				) ORDER BY ma.billing_order, a.id),
+				'[]') AS actors
+			FROM %s ma
+			JOIN %s a ON ma.actor_id = a.id
+			WHERE ma.movie_id = m.id
+		) c ON true
+		LEFT JOIN LATERAL (
+			SELECT COALESCE(json_object_agg(d.department, d.members ORDER BY d.department), '{}') AS crew
+			FROM (
+				SELECT mc.department, json_agg(json_build_object(
+					'id', p.id, 'first_name', p.first_name, 'last_name', p.last_name, 'job', mc.job
+				) ORDER BY mc.job, p.id) AS members
+				FROM %s mc
+				JOIN %s p ON mc.person_id = p.id
+				WHERE mc.movie_id = m.id
+				GROUP BY mc.department
+			) d
+		) cr ON true
+		LEFT JOIN LATERAL (
+			SELECT json_build_object(
+				'average', COALESCE(ROUND(SUM(rd.score * rd.votes)::numeric / NULLIF(SUM(rd.votes), 0), 1), 0),
+				'votes', SUM(rd.votes),
+				'distribution', json_agg(json_build_object('score', rd.score, 'votes', rd.votes) ORDER BY rd.score)
+			) AS user_rating
+			FROM (
+				SELECT s.score, COUNT(rv.id) AS votes
+				FROM generate_series(1, 10) AS s (score)
+				LEFT JOIN %s rv ON rv.movie_id = m.id AND rv.rating = s.score AND rv.status = '%s'
+				GROUP BY s.score
+			) rd
+		) ur ON true`, q.with, strings.Join(columns, ", "), from, moviesGenresTable, genresTable, moviesActorsTable, peopleTable,
+		moviesCrewTable, peopleTable, reviewsTable, filmoteka.ReviewPublished)
+
+	if len(q.conditions) > 0 {
+		query += "\n\t\tWHERE " + strings.Join(q.conditions, " AND ")
 	}
+	if q.orderBy != "" {
+		query += "\n\t\tORDER BY " + q.orderBy
+	}
+	if q.limit != "" {
+		query += "\n\t\t" + q.limit
+	}
+	return query
+}
 
-	query = fmt.Sprintf("DELETE FROM %s WHERE id=$1", moviesTable)
-	if _, err = m.db.Exec(query, movieId); err != nil {
-		return err
+func (m *MoviePostgres) GetMovieById(movieId int) (filmoteka.MoviesWithActors, error) {
+	var movie filmoteka.MoviesWithActors
+
+	query := movieQuery{conditions: []string{"m.id=$1"}}.String()
+	err := m.db.Get(&movie, query, movieId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return movie, filmoteka.ErrMovieNotFound
 	}
+	return movie, err
 
-	return nil
 }
 
-func (m *MoviePostgres) UpdateMovie(movieId int, input filmoteka.UpdateMovies) error {
+func (m *MoviePostgres) DeleteMovie(movieId int) error {
+	return withTx(m.db, func(tx Executor) error {
+		query := fmt.Sprintf("DELETE FROM %s WHERE movie_id=$1", moviesActorsTable)
+		if _, err := tx.Exec(query, movieId); err != nil {
+			return err
+		}
+
+		query = fmt.Sprintf("DELETE FROM %s WHERE id=$1", moviesTable)
+		res, err := tx.Exec(query, movieId)
+		return affectOne(res, err, filmoteka.ErrMovieNotFound)
+	})
+}
+
+// UpdateMovie updates the movie's own columns and bumps its version, see
+// updateVersioned. The cast and the genres are replaced separately with
+// SetMovieActors and SetMovieGenres, but the version is bumped even when no
+// column changes, since they are part of the movie.
+func (m *MoviePostgres) UpdateMovie(movieId int, input filmoteka.UpdateMovies, version int) (int, error) {
 	setValue := make([]string, 0)
 	args := make([]interface{}, 0)
-	argId := 1
+
+	set := func(column string, value interface{}) {
+		args = append(args, value)
+		setValue = append(setValue, fmt.Sprintf("%s=$%d", column, len(args)))
+	}
 
 	if input.Title != nil {
-		setValue = append(setValue, fmt.Sprintf("title=$%d", argId))
-		args = append(args, *input.Title)
-		argId++
+		set("title", *input.Title)
+	}
+
+	if input.OriginalTitle != nil {
+		set("original_title", *input.OriginalTitle)
 	}
 
 	if input.Description != nil {
-		setValue = append(setValue, fmt.Sprintf("description=$%d", argId))
-		args = append(args, *input.Description)
-		argId++
+		set("description", *input.Description)
 	}
 
 	if input.ReleaseDate != nil {
-		setValue = append(setValue, fmt.Sprintf("release_date=$%d", argId))
-		args = append(args, *input.ReleaseDate)
-		argId++
+		set("release_date", *input.ReleaseDate)
 	}
 
 	if input.Rating != nil {
-		setValue = append(setValue, fmt.Sprintf("rating=$%d", argId))
-		args = append(args, *input.Rating)
-		argId++
+		set("rating", *input.Rating)
 	}
 
-	if input.Actors != nil {
-
-		deleteQuery := fmt.Sprintf("DELETE FROM %s WHERE movie_id=$1", moviesActorsTable)
-		_, err := m.db.Exec(deleteQuery, movieId)
-		if err != nil {
-			return err
-		}
+	if input.Country != nil {
+		set("country", *input.Country)
+	}
 
-		for _, actorID := range *input.Actors {
-			insertQuery := fmt.Sprintf("INSERT INTO %s (actor_id, movie_id) VALUES ($1, $2)", moviesActorsTable)
-			_, err := m.db.Exec(insertQuery, actorID, movieId)
-			if err != nil {
-				return err
-			}
-		}
+	if input.Runtime != nil {
+		set("runtime", *input.Runtime)
 	}
 
-	setQuery := strings.Join(setValue, ", ")
+	if input.AgeRating != nil {
+		set("age_rating", *input.AgeRating)
+	}
 
-	query := fmt.Sprintf("UPDATE %s SET %s WHERE id=$%d", moviesTable, setQuery, argId)
-	args = append(args, movieId)
+	return updateVersioned(m.db, moviesTable, movieId, version, setValue, args, filmoteka.ErrMovieNotFound)
+}
 
-	log.Printf("updateQuerry: %s", query)
-	log.Printf("args :%v", args)
+// searchConfig is the text search configuration of the movies.search
+// column. Queries must be parsed with the same one to match its lexemes.
+const searchConfig = "russian"
 
-	_, err := m.db.Exec(query, args...)
-	return err
+// SearchMovies runs a full-text query against the title and description of
+// every movie, best ranked first, optionally limited to movies with an
+// actor matching params.Actor or a director matching params.Director.
+// Headlines are only built for the page.
+func (m *MoviePostgres) SearchMovies(params filmoteka.MovieSearchParams) (filmoteka.MovieSearchList, error) {
+	var list filmoteka.MovieSearchList
 
-}
+	conditions, args := movieFilters(filmoteka.MovieListParams{Actor: params.Actor, Director: params.Director}, []interface{}{params.Query})
+	conditions = append([]string{fmt.Sprintf("m.search @@ websearch_to_tsquery('%s', $1)", searchConfig)}, conditions...)
+	where := strings.Join(conditions, " AND ")
 
-func (m *MoviePostgres) SearchMoviesByTitle(fragment string) ([]filmoteka.MoviesWithActors, error) {
-	var movies []filmoteka.MoviesWithActors
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s m WHERE %s", moviesTable, where)
+	if err := m.db.Get(&list.Total, countQuery, args...); err != nil {
+		return list, err
+	}
 
-	query := fmt.Sprintf(`
-		SELECT 
-			m.*,
-			array_agg(concat(a.first_name, ' ', a.last_name)) AS actors
-		FROM 
-			%s m
-		INNER JOIN 
-			%s ma ON m.id = ma.movie_id
-		INNER JOIN 
-			%s a ON ma.actor_id = a.id
-		WHERE 
-			LOWER(m.title) LIKE LOWER($1)
-		GROUP BY 
-			m.id
-	`, moviesTable, moviesActorsTable, actorsTable)
-
-	fragment = "%" + fragment + "%"
-
-	err := m.db.Select(&movies, query, fragment)
-	if err != nil {
-		return nil, errors.New("sql error")
+	args = append(args, params.Limit, params.Offset)
+
+	query := movieQuery{
+		with: fmt.Sprintf(`WITH found AS (
+			SELECT m.id, ts_rank(m.search, websearch_to_tsquery('%s', $1)) AS rank
+			FROM %s m
+			WHERE %s
+			ORDER BY rank DESC, m.id
+			LIMIT $%d OFFSET $%d
+		)`, searchConfig, moviesTable, where, len(args)-1, len(args)),
+		from: fmt.Sprintf("found f JOIN %s m ON m.id = f.id", moviesTable),
+		columns: []string{
+			"f.rank",
+			fmt.Sprintf("ts_headline('%[1]s', m.title || '. ' || m.description, websearch_to_tsquery('%[1]s', $1), 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2') AS headline", searchConfig),
+		},
+		orderBy: "f.rank DESC, m.id",
+	}.String()
+
+	if err := m.db.Select(&list.Movies, query, args...); err != nil {
+		return list, err
 	}
 
-	return movies, nil
+	return list, nil
+}
+
+func (m *MoviePostgres) SearchMoviesByTitle(fragment string) ([]filmoteka.MoviesWithActors, error) {
+	return m.searchMovies(filmoteka.MovieListParams{Title: fragment})
 }
 
 func (m *MoviePostgres) SearchMovieByActorName(fragment string) ([]filmoteka.MoviesWithActors, error) {
+	return m.searchMovies(filmoteka.MovieListParams{Actor: fragment})
+}
+
+// searchMovies returns every movie matching the filters of params, unpaged.
+func (m *MoviePostgres) searchMovies(params filmoteka.MovieListParams) ([]filmoteka.MoviesWithActors, error) {
 	var movies []filmoteka.MoviesWithActors
 
-	query := fmt.Sprintf(`
-		SELECT 
-			m.*,
-			array_agg(concat(a.first_name, ' ', a.last_name)) AS actors
-		FROM 
-			%s m
-		INNER JOIN 
-			%s ma ON m.id = ma.movie_id
-		INNER JOIN 
-			%s a ON ma.actor_id = a.id
-		WHERE 
-			LOWER(a.first_name) LIKE LOWER($1) OR LOWER(a.last_name) LIKE LOWER($1)
-		GROUP BY 
-			m.id
-	`, moviesTable, moviesActorsTable, actorsTable)
-
-	fragment = "%" + fragment + "%"
-
-	err := m.db.Select(&movies, query, fragment)
-	if err != nil {
+	conditions, args := movieFilters(params, nil)
+	query := movieQuery{conditions: conditions, orderBy: "m.id"}.String()
+
+	if err := m.db.Select(&movies, query, args...); err != nil {
 		return nil, err
 	}
 
@@ -1,15 +1,41 @@
 package repository
 
 import (
+	"encoding/json"
+	"errors"
 	"regexp"
 	"testing"
 	filmoteka "vk_restAPI"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
+// movieProjection is the start of every query built by movieQuery.
+const movieProjection = "SELECT m.id, m.title, m.original_title, m.description, TO_CHAR(m.release_date, 'YYYY-MM-DD') AS release_date, m.rating, " +
+	"m.country, m.runtime, m.age_rating, m.version, g.genres, c.actors, cr.crew, ur.user_rating " +
+	"FROM movies m LEFT JOIN LATERAL ( " +
+	"SELECT COALESCE(json_agg(json_build_object('id', gr.id, 'name', gr.name) ORDER BY gr.name), '[]') AS genres " +
+	"FROM moviesgenres mg JOIN genres gr ON mg.genre_id = gr.id WHERE mg.movie_id = m.id ) g ON true " +
+	"LEFT JOIN LATERAL ( SELECT COALESCE( " +
+	"json_agg(json_build_object( 'id', a.id, 'first_name', a.first_name, 'last_name', a.last_name, " +
+	"'character_name', ma.character_name, 'billing_order', ma.billing_order, 'role_type', ma.role_type " +
+	") ORDER BY ma.billing_order, a.id), '[]') AS actors " +
+	"FROM moviesactors ma JOIN people a ON ma.actor_id = a.id WHERE ma.movie_id = m.id ) c ON true " +
+	"LEFT JOIN LATERAL ( SELECT COALESCE(json_object_agg(d.department, d.members ORDER BY d.department), '{}') AS crew " +
+	"FROM ( SELECT mc.department, json_agg(json_build_object( 'id', p.id, 'first_name', p.first_name, 'last_name', p.last_name, 'job', mc.job " +
+	") ORDER BY mc.job, p.id) AS members FROM moviescrew mc JOIN people p ON mc.person_id = p.id WHERE mc.movie_id = m.id " +
+	"GROUP BY mc.department ) d ) cr ON true " +
+	"LEFT JOIN LATERAL ( SELECT json_build_object( " +
+	"'average', COALESCE(ROUND(SUM(rd.score * rd.votes)::numeric / NULLIF(SUM(rd.votes), 0), 1), 0), " +
+	"'votes', SUM(rd.votes), " +
+	"'distribution', json_agg(json_build_object('score', rd.score, 'votes', rd.votes) ORDER BY rd.score) " +
+	") AS user_rating FROM ( SELECT s.score, COUNT(rv.id) AS votes FROM generate_series(1, 10) AS s (score) " +
+	"LEFT JOIN reviews rv ON rv.movie_id = m.id AND rv.rating = s.score AND rv.status = 'published' " +
+	"GROUP BY s.score ) rd ) ur ON true"
+
 func TestMoviePostgres_GetMovies(t *testing.T) {
 
 	db, mock, err := sqlmock.New()
@@ -29,33 +55,23 @@ func TestMoviePostgres_GetMovies(t *testing.T) {
 			Description: "testDescription",
 			ReleaseDate: "1996-06-20",
 			Rating:      7,
-			Actors:      "Actor 1, Actor 2",
+			Actors:      filmoteka.Cast{{ActorSummary: filmoteka.ActorSummary{Id: 1, FirstName: "Actor", LastName: "One"}}, {ActorSummary: filmoteka.ActorSummary{Id: 2, FirstName: "Actor", LastName: "Two"}}},
 		},
 	}
 
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM movies")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
 	rows := sqlmock.NewRows([]string{"id", "title", "description", "release_date", "rating", "actors"}).
-		AddRow(expectedMovies[0].Id, expectedMovies[0].Title, expectedMovies[0].Description, expectedMovies[0].ReleaseDate, expectedMovies[0].Rating, expectedMovies[0].Actors)
-	mock.ExpectQuery(regexp.QuoteMeta(`
-    SELECT 
-        m.id, 
-        m.title, 
-        m.description, 
-        TO_CHAR (m.release_date, 'YYYY-MM-DD') AS release_date, 
-        m.rating, 
-        array_agg(a.first_name || ' ' || a.last_name) AS actors
-    FROM 
-        movies m
-    LEFT JOIN 
-        moviesactors ma ON m.id = ma.movie_id
-    LEFT JOIN 
-        actors a ON ma.actor_id = a.id
-    GROUP BY 
-        m.id, m.title, m.description, m.release_date, m.rating
-    ORDER BY 
-        m.rating DESC
-    `)).WillReturnRows(rows)
-
-	movies, err := repo.GetMovies()
+		AddRow(expectedMovies[0].Id, expectedMovies[0].Title, expectedMovies[0].Description, expectedMovies[0].ReleaseDate, expectedMovies[0].Rating, mustJSON(expectedMovies[0].Actors))
+	mock.ExpectQuery(regexp.QuoteMeta(movieProjection+" ORDER BY m.rating DESC, m.id DESC LIMIT $1 OFFSET $2")).WithArgs(2, 2).WillReturnRows(rows)
+
+	list, err := repo.GetMovies(filmoteka.MovieListParams{PageParams: filmoteka.PageParams{
+		Sort:   filmoteka.SortByRating,
+		Order:  filmoteka.OrderDesc,
+		Limit:  1,
+		Offset: 2,
+	}})
 
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
@@ -63,17 +79,20 @@ func TestMoviePostgres_GetMovies(t *testing.T) {
 	}
 
 	assert.NoError(t, err)
-	assert.Equal(t, len(expectedMovies), len(movies))
-	assert.Equal(t, expectedMovies[0].Title, movies[0].Title)
-	assert.Equal(t, expectedMovies[0].Description, movies[0].Description)
-	assert.Equal(t, expectedMovies[0].ReleaseDate, movies[0].ReleaseDate)
-	assert.Equal(t, expectedMovies[0].Rating, movies[0].Rating)
-	assert.Equal(t, expectedMovies[0].Actors, movies[0].Actors)
+	assert.Equal(t, 3, list.Total)
+	assert.Equal(t, len(expectedMovies), len(list.Movies))
+	assert.Equal(t, expectedMovies[0].Title, list.Movies[0].Title)
+	assert.Equal(t, expectedMovies[0].Description, list.Movies[0].Description)
+	assert.Equal(t, expectedMovies[0].ReleaseDate, list.Movies[0].ReleaseDate)
+	assert.Equal(t, expectedMovies[0].Rating, list.Movies[0].Rating)
+	assert.Equal(t, expectedMovies[0].Actors, list.Movies[0].Actors)
+	assert.Empty(t, list.NextCursor)
+	assert.NotEmpty(t, list.PrevCursor)
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestMoviePostgres_GetMoviesSortedByTitle(t *testing.T) {
+func TestMoviePostgres_GetMoviesWithCursor(t *testing.T) {
 
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -81,75 +100,121 @@ func TestMoviePostgres_GetMoviesSortedByTitle(t *testing.T) {
 	}
 	defer db.Close()
 
-	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewMoviePostgres(sqlx.NewDb(db, "sqlmock"))
 
-	repo := NewMoviePostgres(sqlxDB)
+	keys, _ := newKeyset(movieSortKeys, "m.id", filmoteka.SortByTitle, filmoteka.OrderAsc)
 
-	// Ожидаемые результаты
-	expectedMovies := []filmoteka.MoviesWithActors{
-		{
-			Id:          1,
-			Title:       "First Title",
-			Description: "First Description",
-			ReleaseDate: "1996-06-20",
-			Rating:      7,
-			Actors:      "Actor 1, Actor 2",
-		},
-		{
-			Id:          2,
-			Title:       "Second Title",
-			Description: "Second Description",
-			ReleaseDate: "2000-01-01",
-			Rating:      8,
-			Actors:      "Actor 3, Actor 4",
-		},
-	}
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM movies")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
 
+	//A backward page is read in reverse and returned in natural order
 	rows := sqlmock.NewRows([]string{"id", "title", "description", "release_date", "rating", "actors"}).
-		AddRow(expectedMovies[0].Id, expectedMovies[0].Title, expectedMovies[0].Description, expectedMovies[0].ReleaseDate, expectedMovies[0].Rating, expectedMovies[0].Actors).
-		AddRow(expectedMovies[1].Id, expectedMovies[1].Title, expectedMovies[1].Description, expectedMovies[1].ReleaseDate, expectedMovies[1].Rating, expectedMovies[1].Actors)
-	mock.ExpectQuery(regexp.QuoteMeta(`
-    SELECT 
-        m.id, 
-        m.title, 
-        m.description, 
-        TO_CHAR (m.release_date, 'YYYY-MM-DD') AS release_date, 
-        m.rating, 
-        array_agg(a.first_name || ' ' || a.last_name) AS actors
-    FROM 
-        movies m
-    LEFT JOIN 
-        moviesactors ma ON m.id = ma.movie_id
-    LEFT JOIN 
-        actors a ON ma.actor_id = a.id
-    GROUP BY 
-        m.id, m.title, m.description, m.release_date, m.rating
-    ORDER BY 
-        m.title ASC
-    `)).WillReturnRows(rows)
-
-	movies, err := repo.GetMoviesSortedByTitle()
+		AddRow(3, "C", "Description", "2000-01-01", 7, "[]").
+		AddRow(2, "B", "Description", "2000-01-01", 7, "[]").
+		AddRow(1, "A", "Description", "2000-01-01", 7, "[]")
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE (m.title, m.id) < ($1::text, $2) ORDER BY m.title DESC, m.id DESC LIMIT $3 OFFSET $4")).
+		WithArgs("D", 4, 3, 0).WillReturnRows(rows)
+
+	list, err := repo.GetMovies(filmoteka.MovieListParams{PageParams: filmoteka.PageParams{
+		Sort:   filmoteka.SortByTitle,
+		Order:  filmoteka.OrderAsc,
+		Limit:  2,
+		Cursor: keys.cursor("D", 4, true),
+	}})
 
+	assert.NoError(t, err)
+	assert.Equal(t, 5, list.Total)
+	assert.Equal(t, 2, len(list.Movies))
+	assert.Equal(t, "B", list.Movies[0].Title)
+	assert.Equal(t, "C", list.Movies[1].Title)
+	assert.Equal(t, filmoteka.Cast{}, list.Movies[0].Actors)
+	assert.Equal(t, keys.cursor("C", 3, false), list.NextCursor)
+	assert.Equal(t, keys.cursor("B", 2, true), list.PrevCursor)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMoviePostgres_GetMoviesWithFilters(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
 	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-		return
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
 	}
+	defer db.Close()
+
+	repo := NewMoviePostgres(sqlx.NewDb(db, "sqlmock"))
+
+	keys, _ := newKeyset(movieSortKeys, "m.id", filmoteka.SortById, filmoteka.OrderAsc)
+
+	//Filters narrow the total as well as the page
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM movies m WHERE LOWER(m.title) LIKE LOWER($1) ESCAPE '\\' AND EXISTS (SELECT 1 FROM moviesactors fma WHERE fma.movie_id = m.id AND fma.actor_id = $2)")).
+		WithArgs("%dune%", 7).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "release_date", "rating", "actors"}).
+		AddRow(5, "Dune", "Description", "2021-09-15", 8, "[]")
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE LOWER(m.title) LIKE LOWER($1) ESCAPE '\\' AND EXISTS (SELECT 1 FROM moviesactors fma WHERE fma.movie_id = m.id AND fma.actor_id = $2) AND m.id > $3 ORDER BY")).
+		WithArgs("%dune%", 7, 4, 2, 0).WillReturnRows(rows)
+
+	list, err := repo.GetMovies(filmoteka.MovieListParams{
+		PageParams: filmoteka.PageParams{
+			Sort:   filmoteka.SortById,
+			Order:  filmoteka.OrderAsc,
+			Limit:  1,
+			Cursor: keys.cursor("", 4, false),
+		},
+		Title:   "dune",
+		ActorId: 7,
+	})
 
 	assert.NoError(t, err)
-	assert.Equal(t, len(expectedMovies), len(movies))
+	assert.Equal(t, 3, list.Total)
+	assert.Equal(t, 1, len(list.Movies))
+	assert.Equal(t, "Dune", list.Movies[0].Title)
 
-	for i := range expectedMovies {
-		assert.Equal(t, expectedMovies[i].Title, movies[i].Title)
-		assert.Equal(t, expectedMovies[i].Description, movies[i].Description)
-		assert.Equal(t, expectedMovies[i].ReleaseDate, movies[i].ReleaseDate)
-		assert.Equal(t, expectedMovies[i].Rating, movies[i].Rating)
-		assert.Equal(t, expectedMovies[i].Actors, movies[i].Actors)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMoviePostgres_GetMoviesWithMetadataFilters(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
 	}
+	defer db.Close()
+
+	repo := NewMoviePostgres(sqlx.NewDb(db, "sqlmock"))
+
+	where := "WHERE EXISTS (SELECT 1 FROM moviesgenres fmg WHERE fmg.movie_id = m.id AND fmg.genre_id = ANY($1)) " +
+		"AND m.country = $2 AND m.age_rating = $3 AND m.runtime >= $4 AND m.runtime BETWEEN 1 AND $5"
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM movies m "+where)).
+		WithArgs(sqlmock.AnyArg(), "US", "16+", 90, 160).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	rows := sqlmock.NewRows([]string{"id", "title", "release_date", "rating", "country", "runtime", "age_rating", "genres", "actors"}).
+		AddRow(4, "Темный рыцарь", "2008-07-14", 7, "US", 152, "16+", `[{"id":2,"name":"боевик"},{"id":7,"name":"триллер"}]`, "[]")
+	mock.ExpectQuery(regexp.QuoteMeta(movieProjection+" "+where+" ORDER BY m.rating DESC, m.id DESC LIMIT $6 OFFSET $7")).
+		WithArgs(sqlmock.AnyArg(), "US", "16+", 90, 160, 21, 0).WillReturnRows(rows)
+
+	list, err := repo.GetMovies(filmoteka.MovieListParams{
+		PageParams: filmoteka.PageParams{Sort: filmoteka.SortByRating, Order: filmoteka.OrderDesc, Limit: 20},
+		GenreIds:   []int{2, 7},
+		Country:    "US",
+		AgeRating:  "16+",
+		MinRuntime: 90,
+		MaxRuntime: 160,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, list.Total)
+	assert.Equal(t, filmoteka.Genres{{Id: 2, Name: "боевик"}, {Id: 7, Name: "триллер"}}, list.Movies[0].Genres)
+	assert.Equal(t, 152, list.Movies[0].Runtime)
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestMoviePostgres_GetMoviesSortedByDate(t *testing.T) {
+func TestMoviePostgres_GetMoviesByDirector(t *testing.T) {
 
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -157,70 +222,75 @@ func TestMoviePostgres_GetMoviesSortedByDate(t *testing.T) {
 	}
 	defer db.Close()
 
-	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewMoviePostgres(sqlx.NewDb(db, "sqlmock"))
 
-	repo := NewMoviePostgres(sqlxDB)
+	where := "WHERE EXISTS ( SELECT 1 FROM moviescrew fmc JOIN people fp ON fmc.person_id = fp.id " +
+		"WHERE fmc.movie_id = m.id AND fmc.department = 'directing' AND (LOWER(fp.first_name) LIKE LOWER($1) ESCAPE '\\' OR LOWER(fp.last_name) LIKE LOWER($1) ESCAPE '\\'))"
 
-	expectedMovies := []filmoteka.MoviesWithActors{
-		{
-			Id:          1,
-			Title:       "First Title",
-			Description: "First Description",
-			ReleaseDate: "1996-06-20",
-			Rating:      7,
-			Actors:      "Actor 1, Actor 2",
-		},
-		{
-			Id:          2,
-			Title:       "Second Title",
-			Description: "Second Description",
-			ReleaseDate: "2000-01-01",
-			Rating:      8,
-			Actors:      "Actor 3, Actor 4",
-		},
-	}
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM movies m " + where)).
+		WithArgs("%нолан%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "release_date", "rating", "actors"}).
-		AddRow(expectedMovies[0].Id, expectedMovies[0].Title, expectedMovies[0].Description, expectedMovies[0].ReleaseDate, expectedMovies[0].Rating, expectedMovies[0].Actors).
-		AddRow(expectedMovies[1].Id, expectedMovies[1].Title, expectedMovies[1].Description, expectedMovies[1].ReleaseDate, expectedMovies[1].Rating, expectedMovies[1].Actors)
-	mock.ExpectQuery(regexp.QuoteMeta(`
-    SELECT 
-        m.id, 
-        m.title, 
-        m.description, 
-        TO_CHAR (m.release_date, 'YYYY-MM-DD') AS release_date, 
-        m.rating, 
-        array_agg(a.first_name || ' ' || a.last_name) AS actors
-    FROM 
-        movies m
-    LEFT JOIN 
-        moviesactors ma ON m.id = ma.movie_id
-    LEFT JOIN 
-        actors a ON ma.actor_id = a.id
-    GROUP BY 
-        m.id, m.title, m.description, m.release_date, m.rating
-    ORDER BY 
-        m.release_date ASC
-    `)).WillReturnRows(rows)
-
-	movies, err := repo.GetMoviesSortedByDate()
+	rows := sqlmock.NewRows([]string{"id", "title", "release_date", "rating", "genres", "actors", "crew"}).
+		AddRow(4, "Темный рыцарь", "2008-07-14", 7, "[]", "[]",
+			`{"directing":[{"id":18,"first_name":"Кристофер","last_name":"Нолан","job":"Режиссёр"}],"sound":[{"id":15,"first_name":"Ханс","last_name":"Циммер","job":"Композитор"}]}`)
+	mock.ExpectQuery(regexp.QuoteMeta(movieProjection+" "+where+" ORDER BY m.rating DESC, m.id DESC LIMIT $2 OFFSET $3")).
+		WithArgs("%нолан%", 21, 0).WillReturnRows(rows)
+
+	list, err := repo.GetMovies(filmoteka.MovieListParams{
+		PageParams: filmoteka.PageParams{Sort: filmoteka.SortByRating, Order: filmoteka.OrderDesc, Limit: 20},
+		Director:   "нолан",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, filmoteka.Crew{
+		filmoteka.DepartmentDirecting: {{ActorSummary: filmoteka.ActorSummary{Id: 18, FirstName: "Кристофер", LastName: "Нолан"}, Job: "Режиссёр"}},
+		filmoteka.DepartmentSound:     {{ActorSummary: filmoteka.ActorSummary{Id: 15, FirstName: "Ханс", LastName: "Циммер"}, Job: "Композитор"}},
+	}, list.Movies[0].Crew)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
 
+func TestMoviePostgres_GetMoviesForeignCursor(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
 	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-		return
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
 	}
+	defer db.Close()
 
-	assert.NoError(t, err)
-	assert.Equal(t, len(expectedMovies), len(movies))
+	repo := NewMoviePostgres(sqlx.NewDb(db, "sqlmock"))
 
-	for i := range expectedMovies {
-		assert.Equal(t, expectedMovies[i].Title, movies[i].Title)
-		assert.Equal(t, expectedMovies[i].Description, movies[i].Description)
-		assert.Equal(t, expectedMovies[i].ReleaseDate, movies[i].ReleaseDate)
-		assert.Equal(t, expectedMovies[i].Rating, movies[i].Rating)
-		assert.Equal(t, expectedMovies[i].Actors, movies[i].Actors)
+	keys, _ := newKeyset(movieSortKeys, "m.id", filmoteka.SortByTitle, filmoteka.OrderAsc)
+
+	_, err = repo.GetMovies(filmoteka.MovieListParams{PageParams: filmoteka.PageParams{
+		Sort:   filmoteka.SortByRating,
+		Order:  filmoteka.OrderAsc,
+		Limit:  2,
+		Cursor: keys.cursor("D", 4, false),
+	}})
+
+	assert.ErrorIs(t, err, filmoteka.ErrInvalidCursor)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMoviePostgres_GetMoviesUnsupportedSort(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
 	}
+	defer db.Close()
+
+	repo := NewMoviePostgres(sqlx.NewDb(db, "sqlmock"))
 
+	_, err = repo.GetMovies(filmoteka.MovieListParams{PageParams: filmoteka.PageParams{
+		Sort:  "rating; DROP TABLE movies",
+		Order: filmoteka.OrderAsc,
+		Limit: 10,
+	}})
+
+	assert.Error(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -242,30 +312,16 @@ func TestMoviePostgres_GetMovieById(t *testing.T) {
 		Description: "Test Description",
 		ReleaseDate: "1996-06-20",
 		Rating:      7,
-		Actors:      "Actor 1, Actor 2",
+		Actors: filmoteka.Cast{
+			{ActorSummary: filmoteka.ActorSummary{Id: 1, FirstName: "Actor", LastName: "One"}, CastRole: filmoteka.CastRole{CharacterName: "Hero", BillingOrder: 1, RoleType: filmoteka.CastLead}},
+			{ActorSummary: filmoteka.ActorSummary{Id: 2, FirstName: "Actor", LastName: "Two"}, CastRole: filmoteka.CastRole{CharacterName: "Narrator", BillingOrder: 2, RoleType: filmoteka.CastVoice}},
+		},
+		Version: 3,
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "release_date", "rating", "actors"}).
-		AddRow(expectedMovie.Id, expectedMovie.Title, expectedMovie.Description, expectedMovie.ReleaseDate, expectedMovie.Rating, expectedMovie.Actors)
-	mock.ExpectQuery(regexp.QuoteMeta(`
-    SELECT 
-        m.id, 
-        m.title, 
-        m.description, 
-        TO_CHAR (m.release_date, 'YYYY-MM-DD') AS release_date, 
-        m.rating, 
-        array_agg(a.first_name || ' ' || a.last_name) AS actors
-    FROM 
-        movies m
-    LEFT JOIN 
-        moviesactors ma ON m.id = ma.movie_id
-    LEFT JOIN 
-        actors a ON ma.actor_id = a.id
-    WHERE 
-        m.id=$1
-    GROUP BY 
-        m.id, m.title, m.description, m.release_date, m.rating
-    `)).WithArgs(expectedMovie.Id).WillReturnRows(rows)
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "release_date", "rating", "version", "actors"}).
+		AddRow(expectedMovie.Id, expectedMovie.Title, expectedMovie.Description, expectedMovie.ReleaseDate, expectedMovie.Rating, expectedMovie.Version, mustJSON(expectedMovie.Actors))
+	mock.ExpectQuery(regexp.QuoteMeta(movieProjection + " WHERE m.id=$1")).WithArgs(expectedMovie.Id).WillReturnRows(rows)
 
 	movie, err := repo.GetMovieById(expectedMovie.Id)
 
@@ -281,6 +337,68 @@ func TestMoviePostgres_GetMovieById(t *testing.T) {
 	assert.Equal(t, expectedMovie.ReleaseDate, movie.ReleaseDate)
 	assert.Equal(t, expectedMovie.Rating, movie.Rating)
 	assert.Equal(t, expectedMovie.Actors, movie.Actors)
+	assert.Equal(t, expectedMovie.Version, movie.Version)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMoviePostgres_SearchMovies(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	repo := NewMoviePostgres(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM movies m WHERE m.search @@ websearch_to_tsquery('russian', $1)")).
+		WithArgs("дюна").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	//The page is ranked before the headlines are built
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "release_date", "rating", "version", "rank", "headline", "actors"}).
+		AddRow(1, "Дюна", "Пустынная планета", "2021-09-15", 8, 1, 0.6, "<mark>Дюна</mark>. Пустынная планета", "[]")
+	mock.ExpectQuery(regexp.QuoteMeta("ORDER BY rank DESC, m.id LIMIT $2 OFFSET $3 )")).
+		WithArgs("дюна", 1, 0).WillReturnRows(rows)
+
+	list, err := repo.SearchMovies(filmoteka.MovieSearchParams{Query: "дюна", Limit: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, list.Total)
+	assert.Equal(t, 1, len(list.Movies))
+	assert.Equal(t, "Дюна", list.Movies[0].Title)
+	assert.Equal(t, 0.6, list.Movies[0].Rank)
+	assert.Equal(t, "<mark>Дюна</mark>. Пустынная планета", list.Movies[0].Headline)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMoviePostgres_SearchMoviesWithActor(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	repo := NewMoviePostgres(sqlx.NewDb(db, "sqlmock"))
+
+	//The actor filter narrows the total as well as the ranked page
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM movies m WHERE m.search @@ websearch_to_tsquery('russian', $1) AND EXISTS")).
+		WithArgs("дюна", "%шаламе%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "release_date", "rating", "version", "actors", "rank", "headline"}).
+		AddRow(1, "Дюна", "", "2021-09-15", 8, 1, `[{"id":1,"first_name":"Тимоти","last_name":"Шаламе"}]`, 0.6, "<mark>Дюна</mark>. ")
+	mock.ExpectQuery(regexp.QuoteMeta("LIKE LOWER($2) ESCAPE '\\')) ORDER BY rank DESC, m.id LIMIT $3 OFFSET $4 ) SELECT m.id")).
+		WithArgs("дюна", "%шаламе%", 20, 0).WillReturnRows(rows)
+
+	list, err := repo.SearchMovies(filmoteka.MovieSearchParams{Query: "дюна", Actor: "шаламе", Limit: 20})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, list.Total)
+	assert.Equal(t, filmoteka.Cast{{ActorSummary: filmoteka.ActorSummary{Id: 1, FirstName: "Тимоти", LastName: "Шаламе"}}}, list.Movies[0].Actors)
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -304,7 +422,7 @@ func TestMoviePostgres_SearchMoviesByTitle(t *testing.T) {
 			Description: "Test Description 1",
 			ReleaseDate: "1996-06-20",
 			Rating:      7,
-			Actors:      "Actor 1, Actor 2",
+			Actors:      filmoteka.Cast{{ActorSummary: filmoteka.ActorSummary{Id: 1, FirstName: "Actor", LastName: "One"}}, {ActorSummary: filmoteka.ActorSummary{Id: 2, FirstName: "Actor", LastName: "Two"}}},
 		},
 		{
 			Id:          2,
@@ -312,28 +430,14 @@ func TestMoviePostgres_SearchMoviesByTitle(t *testing.T) {
 			Description: "Test Description 2",
 			ReleaseDate: "2000-01-01",
 			Rating:      8,
-			Actors:      "Actor 3, Actor 4",
+			Actors:      filmoteka.Cast{{ActorSummary: filmoteka.ActorSummary{Id: 3, FirstName: "Actor", LastName: "Three"}}},
 		},
 	}
 
 	rows := sqlmock.NewRows([]string{"id", "title", "description", "release_date", "rating", "actors"}).
-		AddRow(expectedMovies[0].Id, expectedMovies[0].Title, expectedMovies[0].Description, expectedMovies[0].ReleaseDate, expectedMovies[0].Rating, expectedMovies[0].Actors).
-		AddRow(expectedMovies[1].Id, expectedMovies[1].Title, expectedMovies[1].Description, expectedMovies[1].ReleaseDate, expectedMovies[1].Rating, expectedMovies[1].Actors)
-	mock.ExpectQuery(regexp.QuoteMeta(`
-        SELECT 
-            m.*,
-            array_agg(concat(a.first_name, ' ', a.last_name)) AS actors
-        FROM 
-            movies m
-        INNER JOIN 
-            moviesactors ma ON m.id = ma.movie_id
-        INNER JOIN 
-            actors a ON ma.actor_id = a.id
-        WHERE 
-            LOWER(m.title) LIKE LOWER($1)
-        GROUP BY 
-            m.id
-    `)).WithArgs("%test%").WillReturnRows(rows)
+		AddRow(expectedMovies[0].Id, expectedMovies[0].Title, expectedMovies[0].Description, expectedMovies[0].ReleaseDate, expectedMovies[0].Rating, mustJSON(expectedMovies[0].Actors)).
+		AddRow(expectedMovies[1].Id, expectedMovies[1].Title, expectedMovies[1].Description, expectedMovies[1].ReleaseDate, expectedMovies[1].Rating, mustJSON(expectedMovies[1].Actors))
+	mock.ExpectQuery(regexp.QuoteMeta(movieProjection + " WHERE LOWER(m.title) LIKE LOWER($1) ESCAPE '\\' ORDER BY m.id")).WithArgs("%test%").WillReturnRows(rows)
 
 	movies, err := repo.SearchMoviesByTitle("test")
 
@@ -374,26 +478,13 @@ func TestMoviePostgres_SearchMovieByActorName(t *testing.T) {
 		Description: "Test Description 1",
 		ReleaseDate: "1996-06-20",
 		Rating:      7,
-		Actors:      "Actor 1, Actor 2",
+		Actors:      filmoteka.Cast{{ActorSummary: filmoteka.ActorSummary{Id: 1, FirstName: "Actor", LastName: "One"}}, {ActorSummary: filmoteka.ActorSummary{Id: 2, FirstName: "Actor", LastName: "Two"}}},
+		Version:     3,
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "release_date", "rating", "actors"}).
-		AddRow(expectedMovie.Id, expectedMovie.Title, expectedMovie.Description, expectedMovie.ReleaseDate, expectedMovie.Rating, expectedMovie.Actors)
-	mock.ExpectQuery(regexp.QuoteMeta(`
-        SELECT 
-            m.*,
-            array_agg(concat(a.first_name, ' ', a.last_name)) AS actors
-        FROM 
-            movies m
-        INNER JOIN 
-            moviesactors ma ON m.id = ma.movie_id
-        INNER JOIN 
-            actors a ON ma.actor_id = a.id
-        WHERE 
-            LOWER(a.first_name) LIKE LOWER($1) OR LOWER(a.last_name) LIKE LOWER($1)
-        GROUP BY 
-            m.id
-    `)).WithArgs("%actor%").WillReturnRows(rows)
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "release_date", "rating", "version", "actors"}).
+		AddRow(expectedMovie.Id, expectedMovie.Title, expectedMovie.Description, expectedMovie.ReleaseDate, expectedMovie.Rating, expectedMovie.Version, mustJSON(expectedMovie.Actors))
+	mock.ExpectQuery(regexp.QuoteMeta(movieProjection + " WHERE EXISTS ( SELECT 1 FROM moviesactors fma JOIN people fa ON fma.actor_id = fa.id WHERE fma.movie_id = m.id AND (LOWER(fa.first_name) LIKE LOWER($1) ESCAPE '\\' OR LOWER(fa.last_name) LIKE LOWER($1) ESCAPE '\\')) ORDER BY m.id")).WithArgs("%actor%").WillReturnRows(rows)
 
 	movies, err := repo.SearchMovieByActorName("actor")
 
@@ -413,3 +504,402 @@ func TestMoviePostgres_SearchMovieByActorName(t *testing.T) {
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func mustJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
+
+func TestMoviePostgres_CreateMovie(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	repo := NewMoviePostgres(sqlx.NewDb(db, "sqlmock"))
+
+	movie := filmoteka.Movies{Title: "Дюна", OriginalTitle: "Dune", Description: "Desert", ReleaseDate: "2021-09-03", Rating: 8,
+		Country: "US", Runtime: 155, AgeRating: "12+"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM movies WHERE title = $1 AND description = $2")).
+		WithArgs(movie.Title, movie.Description).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("INSERT INTO movies").
+		WithArgs(movie.Title, movie.OriginalTitle, movie.Description, movie.ReleaseDate, movie.Rating, movie.Country, movie.Runtime, movie.AgeRating).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	id, err := repo.CreateMovie(movie)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLikePattern(t *testing.T) {
+	testTable := []struct {
+		name     string
+		fragment string
+		expected string
+	}{
+		{name: "Plain", fragment: "dune", expected: `%dune%`},
+		{name: "Underscore", fragment: "_", expected: `%\_%`},
+		{name: "Percent", fragment: "100%", expected: `%100\%%`},
+		{name: "Backslash", fragment: `a\b`, expected: `%a\\b%`},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, likePattern(testCase.fragment))
+		})
+	}
+}
+
+func TestMoviePostgres_SetMovieActors(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	repo := NewMoviePostgres(sqlx.NewDb(db, "sqlmock"))
+
+	insert := regexp.QuoteMeta("INSERT INTO moviesactors (movie_id, actor_id, character_name, billing_order, role_type) " +
+		"SELECT DISTINCT ON (c.actor_id) $1, c.actor_id, c.character_name, c.billing_order, c.role_type " +
+		"FROM unnest($2::int[], $3::text[], $4::int[], $5::text[]) WITH ORDINALITY")
+
+	//Entries without billing are billed by position, without a type as supporting
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM moviesactors WHERE movie_id=$1")).
+		WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(insert).
+		WithArgs(1, pq.Array([]int64{1, 4, 3}), pq.Array([]string{"Пол Атрейдес", "", "леди Джессика"}),
+			pq.Array([]int64{1, 2, 5}), pq.Array([]string{"lead", "supporting", "supporting"})).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	err = repo.SetMovieActors(1, []filmoteka.CastEntry{
+		{ActorId: 1, CharacterName: "Пол Атрейдес", RoleType: filmoteka.CastLead},
+		{ActorId: 4},
+		{ActorId: 3, CharacterName: "леди Джессика", BillingOrder: 5, RoleType: filmoteka.CastSupporting},
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMoviePostgres_SetMovieActorsFailure(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	repo := NewMoviePostgres(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM moviesactors WHERE movie_id=$1")).
+		WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO moviesactors")).
+		WithArgs(1, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	err = repo.SetMovieActors(1, []filmoteka.CastEntry{{ActorId: 1}, {ActorId: 2}})
+
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMoviePostgres_SetMovieActorsUnknown(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	repo := NewMoviePostgres(sqlx.NewDb(db, "sqlmock"))
+
+	//Actor 1 is listed twice, so two rows are expected, but 9 doesn't exist
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM moviesactors WHERE movie_id=$1")).
+		WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO moviesactors")).
+		WithArgs(1, pq.Array([]int64{1, 9, 1}), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT DISTINCT c.id FROM unnest($1::int[]) AS c (id) " +
+		"WHERE NOT EXISTS (SELECT 1 FROM people t WHERE t.id = c.id) ORDER BY c.id")).
+		WithArgs(pq.Array([]int64{1, 9, 1})).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+	mock.ExpectRollback()
+
+	err = repo.SetMovieActors(1, []filmoteka.CastEntry{{ActorId: 1}, {ActorId: 9}, {ActorId: 1}})
+
+	assert.ErrorIs(t, err, filmoteka.ErrValidation)
+	assert.EqualError(t, err, "unknown actor ids: 9")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMoviePostgres_SetMovieGenres(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	repo := NewMoviePostgres(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM moviesgenres WHERE movie_id=$1")).
+		WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO moviesgenres (movie_id, genre_id) SELECT $1, id FROM genres WHERE id = ANY($2)")).
+		WithArgs(1, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err = repo.SetMovieGenres(1, []int{3, 8})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMoviePostgres_SetMovieGenresUnknown(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	repo := NewMoviePostgres(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM moviesgenres WHERE movie_id=$1")).
+		WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO moviesgenres (movie_id, genre_id) SELECT $1, id FROM genres WHERE id = ANY($2)")).
+		WithArgs(1, pq.Array([]int64{3, 40, 41})).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT DISTINCT c.id FROM unnest($1::int[]) AS c (id) " +
+		"WHERE NOT EXISTS (SELECT 1 FROM genres t WHERE t.id = c.id) ORDER BY c.id")).
+		WithArgs(pq.Array([]int64{3, 40, 41})).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(40).AddRow(41))
+	mock.ExpectRollback()
+
+	err = repo.SetMovieGenres(1, []int{3, 40, 41})
+
+	assert.ErrorIs(t, err, filmoteka.ErrValidation)
+	assert.EqualError(t, err, "unknown genre ids: 40, 41")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMoviePostgres_SetMovieCrew(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	repo := NewMoviePostgres(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM moviescrew WHERE movie_id=$1")).
+		WithArgs(4).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO moviescrew (movie_id, person_id, department, job) "+
+		"SELECT DISTINCT $1::int, c.person_id, c.department, c.job "+
+		"FROM unnest($2::int[], $3::text[], $4::text[]) AS c (person_id, department, job) "+
+		"JOIN people p ON p.id = c.person_id")).
+		WithArgs(4, pq.Array([]int64{18, 18, 15}), pq.Array([]string{"directing", "writing", "sound"}),
+			pq.Array([]string{"Режиссёр", "Сценарист", "Композитор"})).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	err = repo.SetMovieCrew(4, []filmoteka.CrewEntry{
+		{PersonId: 18, Department: filmoteka.DepartmentDirecting, Job: "Режиссёр"},
+		{PersonId: 18, Department: filmoteka.DepartmentWriting, Job: "Сценарист"},
+		{PersonId: 15, Department: filmoteka.DepartmentSound, Job: "Композитор"},
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMoviePostgres_SetMovieCrewUnknown(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	repo := NewMoviePostgres(sqlx.NewDb(db, "sqlmock"))
+
+	//The repeated job counts once, so two rows are expected
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM moviescrew WHERE movie_id=$1")).
+		WithArgs(4).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO moviescrew")).
+		WithArgs(4, pq.Array([]int64{18, 18, 77}), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT DISTINCT c.id FROM unnest($1::int[]) AS c (id) " +
+		"WHERE NOT EXISTS (SELECT 1 FROM people t WHERE t.id = c.id) ORDER BY c.id")).
+		WithArgs(pq.Array([]int64{18, 18, 77})).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(77))
+	mock.ExpectRollback()
+
+	err = repo.SetMovieCrew(4, []filmoteka.CrewEntry{
+		{PersonId: 18, Department: filmoteka.DepartmentDirecting, Job: "Режиссёр"},
+		{PersonId: 18, Department: filmoteka.DepartmentDirecting, Job: "Режиссёр"},
+		{PersonId: 77, Department: filmoteka.DepartmentSound, Job: "Композитор"},
+	})
+
+	assert.ErrorIs(t, err, filmoteka.ErrValidation)
+	assert.EqualError(t, err, "unknown person ids: 77")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMoviePostgres_DeleteMovie(t *testing.T) {
+
+	testTable := []struct {
+		name         string
+		mockBehavior func(mock sqlmock.Sqlmock)
+		wantErr      bool
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM moviesactors WHERE movie_id=$1")).
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM movies WHERE id=$1")).
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Rollback on failure",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM moviesactors WHERE movie_id=$1")).
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM movies WHERE id=$1")).
+					WithArgs(1).WillReturnError(errors.New("connection reset"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM moviesactors WHERE movie_id=$1")).
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM movies WHERE id=$1")).
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+			}
+			defer db.Close()
+
+			repo := NewMoviePostgres(sqlx.NewDb(db, "sqlmock"))
+			testCase.mockBehavior(mock)
+
+			err = repo.DeleteMovie(1)
+			if testCase.name == "Not Found" {
+				assert.ErrorIs(t, err, filmoteka.ErrMovieNotFound)
+			} else if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMoviePostgres_UpdateMovie(t *testing.T) {
+	title := "New Title"
+
+	testTable := []struct {
+		name            string
+		version         int
+		mockBehavior    func(mock sqlmock.Sqlmock)
+		expectedVersion int
+		expectedErr     error
+	}{
+		{
+			name: "Unconditional",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("UPDATE movies SET title=$1, version=version+1 WHERE id=$2 RETURNING version")).
+					WithArgs(title, 1).WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(5))
+			},
+			expectedVersion: 5,
+		},
+		{
+			name:    "If Match",
+			version: 4,
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("UPDATE movies SET title=$1, version=version+1 WHERE id=$2 AND version=$3 RETURNING version")).
+					WithArgs(title, 1, 4).WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(5))
+			},
+			expectedVersion: 5,
+		},
+		{
+			name:    "Stale Version",
+			version: 3,
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("UPDATE movies SET title=$1, version=version+1 WHERE id=$2 AND version=$3 RETURNING version")).
+					WithArgs(title, 1, 3).WillReturnRows(sqlmock.NewRows([]string{"version"}))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM movies WHERE id=$1)")).
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			},
+			expectedErr: filmoteka.ErrVersionMismatch,
+		},
+		{
+			name:    "Not Found",
+			version: 3,
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("UPDATE movies SET title=$1, version=version+1 WHERE id=$2 AND version=$3 RETURNING version")).
+					WithArgs(title, 1, 3).WillReturnRows(sqlmock.NewRows([]string{"version"}))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM movies WHERE id=$1)")).
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			expectedErr: filmoteka.ErrMovieNotFound,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+			}
+			defer db.Close()
+
+			repo := NewMoviePostgres(sqlx.NewDb(db, "sqlmock"))
+			testCase.mockBehavior(mock)
+
+			version, err := repo.UpdateMovie(1, filmoteka.UpdateMovies{Title: &title}, testCase.version)
+			if testCase.expectedErr != nil {
+				assert.ErrorIs(t, err, testCase.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expectedVersion, version)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	filmoteka "vk_restAPI"
+)
+
+// personFullName is actorFullName on people p, which the trigram index
+// serves as well.
+const personFullName = "(p.first_name || ' ' || p.last_name)"
+
+type PeoplePostgres struct {
+	db Executor
+}
+
+func NewPeoplePostgres(db Executor) *PeoplePostgres {
+	return &PeoplePostgres{db: db}
+}
+
+func (p *PeoplePostgres) CreatePerson(person filmoteka.Person) (int, error) {
+	var id int
+
+	err := withTx(p.db, func(tx Executor) error {
+		var exisitngID int
+
+		query := fmt.Sprintf("SELECT id FROM %s WHERE first_name=$1 AND last_name=$2", peopleTable)
+		row := tx.QueryRow(query, person.FirstName, person.LastName)
+		if err := row.Scan(&exisitngID); err == nil {
+			id = exisitngID
+			return filmoteka.ErrPersonExists
+		} else if err != sql.ErrNoRows {
+			return err
+		}
+
+		query = fmt.Sprintf("INSERT INTO %s (first_name, last_name, gender, date_of_birth, known_for) VALUES ($1, $2, $3, $4, $5) RETURNING id", peopleTable)
+		row = tx.QueryRow(query, person.FirstName, person.LastName, person.Gender, person.DateOfBirth, person.KnownFor)
+		return dbError(row.Scan(&id))
+	})
+
+	return id, err
+}
+
+func (p *PeoplePostgres) GetPeople(params filmoteka.PersonListParams) (filmoteka.PeopleList, error) {
+	var list filmoteka.PeopleList
+
+	conditions, args := personFilters(params)
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s p %s", peopleTable, where)
+	if err := p.db.Get(&list.Total, countQuery, args...); err != nil {
+		return list, err
+	}
+
+	args = append(args, params.Limit, params.Offset)
+
+	query := fmt.Sprintf(`
+		SELECT 
+			p.id, 
+			p.first_name, 
+			p.last_name, 
+			p.gender, 
+			TO_CHAR(p.date_of_birth, 'YYYY-MM-DD') AS date_of_birth, 
+			p.known_for
+		FROM 
+			%s p
+		%s
+		ORDER BY 
+			p.last_name, p.first_name, p.id
+		LIMIT $%d OFFSET $%d
+	`, peopleTable, where, len(args)-1, len(args))
+
+	if err := p.db.Select(&list.People, query, args...); err != nil {
+		return list, err
+	}
+
+	return list, nil
+}
+
+// personFilters turns the list filters into predicates on people p.
+// Placeholders are numbered from 1 in the order of the returned args.
+func personFilters(params filmoteka.PersonListParams) ([]string, []interface{}) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+
+	if params.Name != "" {
+		args = append(args, likePattern(params.Name))
+		conditions = append(conditions, fmt.Sprintf(`%s ILIKE $%d ESCAPE '\'`, personFullName, len(args)))
+	}
+
+	if params.Department != "" {
+		args = append(args, params.Department)
+
+		credited := fmt.Sprintf("EXISTS (SELECT 1 FROM %s fmc WHERE fmc.person_id = p.id AND fmc.department = $%d)", moviesCrewTable, len(args))
+		if params.Department == filmoteka.DepartmentActing {
+			credited = fmt.Sprintf("EXISTS (SELECT 1 FROM %s fma WHERE fma.actor_id = p.id)", moviesActorsTable)
+		}
+		conditions = append(conditions, fmt.Sprintf("(p.known_for = $%d OR %s)", len(args), credited))
+	}
+
+	return conditions, args
+}
+
+// GetPersonById returns a person with every credit they have, acting and
+// crew, each in release order. Acting credits on the same date follow
+// billing, like on the actor endpoints.
+func (p *PeoplePostgres) GetPersonById(personId int) (filmoteka.PersonWithCredits, error) {
+	var person filmoteka.PersonWithCredits
+
+	query := fmt.Sprintf(`
+		SELECT 
+			p.id, 
+			p.first_name, 
+			p.last_name, 
+			p.gender, 
+			TO_CHAR(p.date_of_birth, 'YYYY-MM-DD') AS date_of_birth, 
+			p.known_for, 
+			p.version, 
+			COALESCE((
+				SELECT json_agg(json_build_object(
+					'id', m.id, 'title', m.title, 'release_date', TO_CHAR(m.release_date, 'YYYY-MM-DD'),
+					'character_name', ma.character_name, 'billing_order', ma.billing_order, 'role_type', ma.role_type
+				) ORDER BY m.release_date, ma.billing_order)
+				FROM %s ma
+				JOIN %s m ON ma.movie_id = m.id
+				WHERE ma.actor_id = p.id
+			), '[]') AS movies, 
+			COALESCE((
+				SELECT json_agg(json_build_object(
+					'id', m.id, 'title', m.title, 'release_date', TO_CHAR(m.release_date, 'YYYY-MM-DD'),
+					'department', mc.department, 'job', mc.job
+				) ORDER BY m.release_date, mc.department, mc.job)
+				FROM %s mc
+				JOIN %s m ON mc.movie_id = m.id
+				WHERE mc.person_id = p.id
+			), '[]') AS crew
+		FROM 
+			%s p
+		WHERE 
+			p.id=$1
+	`, moviesActorsTable, moviesTable, moviesCrewTable, moviesTable, peopleTable)
+
+	err := p.db.Get(&person, query, personId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return person, filmoteka.ErrPersonNotFound
+	}
+	return person, err
+}
+
+// UpdatePerson updates the given columns of a person and bumps their
+// version, see updateVersioned. Unlike UpdateActor it reaches people who
+// aren't actors.
+func (p *PeoplePostgres) UpdatePerson(personId int, input filmoteka.UpdatePerson, version int) (int, error) {
+	setValues := make([]string, 0)
+	args := make([]interface{}, 0)
+
+	set := func(column string, value interface{}) {
+		args = append(args, value)
+		setValues = append(setValues, fmt.Sprintf("%s=$%d", column, len(args)))
+	}
+
+	if input.FirstName != nil {
+		set("first_name", *input.FirstName)
+	}
+
+	if input.LastName != nil {
+		set("last_name", *input.LastName)
+	}
+
+	if input.Gender != nil {
+		set("gender", *input.Gender)
+	}
+
+	if input.DateOfBirth != nil {
+		set("date_of_birth", *input.DateOfBirth)
+	}
+
+	if input.KnownFor != nil {
+		set("known_for", *input.KnownFor)
+	}
+
+	return updateVersioned(p.db, peopleTable, personId, version, setValues, args, filmoteka.ErrPersonNotFound)
+}
+
+// DeletePerson removes a person who has no credits. A credited person is
+// kept, their movies have to be recast or recrewed first.
+func (p *PeoplePostgres) DeletePerson(personId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id=$1", peopleTable)
+	res, err := p.db.Exec(query, personId)
+	if isForeignKeyViolation(err) {
+		return filmoteka.ErrPersonInUse
+	}
+	return affectOne(res, err, filmoteka.ErrPersonNotFound)
+}
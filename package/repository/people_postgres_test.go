@@ -0,0 +1,281 @@
+package repository
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	filmoteka "vk_restAPI"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeoplePostgres_CreatePerson(t *testing.T) {
+	type mockBehavior func(mock sqlmock.Sqlmock)
+
+	person := filmoteka.Person{FirstName: "Кристофер", LastName: "Нолан", Gender: "male", DateOfBirth: "1970-07-30", KnownFor: filmoteka.DepartmentDirecting}
+
+	testTable := []struct {
+		name          string
+		mockBehavior  mockBehavior
+		expectedId    int
+		expectedError error
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM people WHERE first_name=$1 AND last_name=$2")).
+					WithArgs("Кристофер", "Нолан").WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO people (first_name, last_name, gender, date_of_birth, known_for) VALUES ($1, $2, $3, $4, $5) RETURNING id")).
+					WithArgs("Кристофер", "Нолан", "male", "1970-07-30", "directing").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(18))
+				mock.ExpectCommit()
+			},
+			expectedId: 18,
+		},
+		{
+			name: "Name Taken",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM people WHERE first_name=$1 AND last_name=$2")).
+					WithArgs("Кристофер", "Нолан").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(18))
+				mock.ExpectRollback()
+			},
+			expectedError: filmoteka.ErrPersonExists,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+			}
+			defer db.Close()
+
+			repo := NewPeoplePostgres(sqlx.NewDb(db, "sqlmock"))
+			testCase.mockBehavior(mock)
+
+			id, err := repo.CreatePerson(person)
+
+			if testCase.expectedError != nil {
+				assert.ErrorIs(t, err, testCase.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expectedId, id)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPeoplePostgres_GetPeople(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPeoplePostgres(sqlx.NewDb(db, "sqlmock"))
+
+	where := "WHERE (p.first_name || ' ' || p.last_name) ILIKE $1 ESCAPE '\\' " +
+		"AND (p.known_for = $2 OR EXISTS (SELECT 1 FROM moviescrew fmc WHERE fmc.person_id = p.id AND fmc.department = $2))"
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM people p "+where)).
+		WithArgs("%финч%", "directing").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "gender", "date_of_birth", "known_for"}).
+		AddRow(19, "Дэвид", "Финчер", "male", "1962-08-28", "directing")
+	mock.ExpectQuery(regexp.QuoteMeta("FROM people p "+where+" ORDER BY p.last_name, p.first_name, p.id LIMIT $3 OFFSET $4")).
+		WithArgs("%финч%", "directing", 20, 0).WillReturnRows(rows)
+
+	list, err := repo.GetPeople(filmoteka.PersonListParams{Name: "финч", Department: filmoteka.DepartmentDirecting, Limit: 20})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, list.Total)
+	assert.Equal(t, []filmoteka.Person{{Id: 19, FirstName: "Дэвид", LastName: "Финчер", Gender: "male", DateOfBirth: "1962-08-28", KnownFor: "directing"}}, list.People)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPeoplePostgres_GetPeopleActing(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPeoplePostgres(sqlx.NewDb(db, "sqlmock"))
+
+	//Acting credits live in the cast, not in the crew
+	where := "WHERE (p.known_for = $1 OR EXISTS (SELECT 1 FROM moviesactors fma WHERE fma.actor_id = p.id))"
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM people p " + where)).
+		WithArgs("acting").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM people p "+where+" ORDER BY p.last_name, p.first_name, p.id LIMIT $2 OFFSET $3")).
+		WithArgs("acting", 20, 40).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err = repo.GetPeople(filmoteka.PersonListParams{Department: filmoteka.DepartmentActing, Limit: 20, Offset: 40})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPeoplePostgres_GetPersonById(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPeoplePostgres(sqlx.NewDb(db, "sqlmock"))
+
+	rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "gender", "date_of_birth", "known_for", "movies", "crew"}).
+		AddRow(14, "Дени", "Вильнёв", "male", "1967-10-03", "directing", "[]",
+			`[{"id":1,"title":"Дюна: Часть вторая","release_date":"2024-02-29","department":"directing","job":"Режиссёр"},`+
+				`{"id":1,"title":"Дюна: Часть вторая","release_date":"2024-02-29","department":"writing","job":"Сценарист"}]`)
+	mock.ExpectQuery("^SELECT (.+) FROM moviescrew mc JOIN movies m ON mc.movie_id = m.id WHERE mc.person_id = p.id (.+) FROM people p WHERE p.id=\\$1$").
+		WithArgs(14).WillReturnRows(rows)
+
+	person, err := repo.GetPersonById(14)
+
+	assert.NoError(t, err)
+	assert.Equal(t, filmoteka.DepartmentDirecting, person.KnownFor)
+	assert.Equal(t, filmoteka.Appearances{}, person.Movies)
+	assert.Equal(t, filmoteka.CrewCredits{
+		{MovieSummary: filmoteka.MovieSummary{Id: 1, Title: "Дюна: Часть вторая", ReleaseDate: "2024-02-29"}, Department: "directing", Job: "Режиссёр"},
+		{MovieSummary: filmoteka.MovieSummary{Id: 1, Title: "Дюна: Часть вторая", ReleaseDate: "2024-02-29"}, Department: "writing", Job: "Сценарист"},
+	}, person.Crew)
+
+	mock.ExpectQuery("^SELECT (.+) FROM people p WHERE p.id=\\$1$").WithArgs(99).WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetPersonById(99)
+
+	assert.ErrorIs(t, err, filmoteka.ErrPersonNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPeoplePostgres_UpdatePerson(t *testing.T) {
+	department := filmoteka.DepartmentWriting
+
+	testTable := []struct {
+		name            string
+		version         int
+		mockBehavior    func(mock sqlmock.Sqlmock)
+		expectedVersion int
+		expectedErr     error
+	}{
+		{
+			name:    "If Match",
+			version: 2,
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("UPDATE people SET known_for=$1, version=version+1 WHERE id=$2 AND version=$3 RETURNING version")).
+					WithArgs(department, 14, 2).WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+			},
+			expectedVersion: 3,
+		},
+		{
+			name:    "Stale Version",
+			version: 1,
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("UPDATE people SET known_for=$1, version=version+1 WHERE id=$2 AND version=$3 RETURNING version")).
+					WithArgs(department, 14, 1).WillReturnRows(sqlmock.NewRows([]string{"version"}))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM people WHERE id=$1)")).
+					WithArgs(14).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			},
+			expectedErr: filmoteka.ErrVersionMismatch,
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("UPDATE people SET known_for=$1, version=version+1 WHERE id=$2 RETURNING version")).
+					WithArgs(department, 14).WillReturnRows(sqlmock.NewRows([]string{"version"}))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM people WHERE id=$1)")).
+					WithArgs(14).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			expectedErr: filmoteka.ErrPersonNotFound,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+			}
+			defer db.Close()
+
+			repo := NewPeoplePostgres(sqlx.NewDb(db, "sqlmock"))
+			testCase.mockBehavior(mock)
+
+			version, err := repo.UpdatePerson(14, filmoteka.UpdatePerson{KnownFor: &department}, testCase.version)
+			if testCase.expectedErr != nil {
+				assert.ErrorIs(t, err, testCase.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expectedVersion, version)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPeoplePostgres_DeletePerson(t *testing.T) {
+	type mockBehavior func(mock sqlmock.Sqlmock)
+
+	testTable := []struct {
+		name          string
+		mockBehavior  mockBehavior
+		expectedError error
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM people WHERE id=$1")).
+					WithArgs(14).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM people WHERE id=$1")).
+					WithArgs(14).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedError: filmoteka.ErrPersonNotFound,
+		},
+		{
+			name: "Credited",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM people WHERE id=$1")).
+					WithArgs(14).WillReturnError(&pq.Error{Code: pgForeignKeyViolation})
+			},
+			expectedError: filmoteka.ErrPersonInUse,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+			}
+			defer db.Close()
+
+			repo := NewPeoplePostgres(sqlx.NewDb(db, "sqlmock"))
+			testCase.mockBehavior(mock)
+
+			err = repo.DeletePerson(14)
+
+			if testCase.expectedError != nil {
+				assert.ErrorIs(t, err, testCase.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -7,10 +7,19 @@ import (
 )
 
 const (
-	userTable         = "users"
-	actorsTable       = "actors"
-	moviesTable       = "movies"
-	moviesActorsTable = "moviesactors"
+	userTable            = "users"
+	peopleTable          = "people"
+	actorsTable          = "actors"
+	moviesTable          = "movies"
+	moviesActorsTable    = "moviesactors"
+	moviesCrewTable      = "moviescrew"
+	genresTable          = "genres"
+	moviesGenresTable    = "moviesgenres"
+	reviewsTable         = "reviews"
+	collectionsTable     = "collections"
+	collectionItemsTable = "collectionitems"
+	watchedTable         = "watched"
+	refreshTokensTable   = "refreshtokens"
 )
 
 type Config struct {
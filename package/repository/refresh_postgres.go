@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"fmt"
+	filmoteka "vk_restAPI"
+)
+
+type RefreshTokenPostgres struct {
+	db Executor
+}
+
+func NewRefreshTokenPostgres(db Executor) *RefreshTokenPostgres {
+	return &RefreshTokenPostgres{db: db}
+}
+
+func (r *RefreshTokenPostgres) CreateRefreshToken(token filmoteka.RefreshToken) (int, error) {
+	var id int
+	query := fmt.Sprintf(`INSERT INTO %s (user_id, family_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4) RETURNING id`, refreshTokensTable)
+
+	row := r.db.QueryRow(query, token.UserId, token.FamilyId, token.TokenHash, token.ExpiresAt)
+	if err := row.Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetRefreshToken locks the row, so two concurrent refreshes with the same
+// token inside transactions can't both rotate it.
+func (r *RefreshTokenPostgres) GetRefreshToken(tokenHash string) (filmoteka.RefreshToken, error) {
+	var token filmoteka.RefreshToken
+	query := fmt.Sprintf(`SELECT id, user_id, family_id, token_hash, expires_at, revoked_at
+		FROM %s WHERE token_hash=$1 FOR UPDATE`, refreshTokensTable)
+	err := r.db.Get(&token, query, tokenHash)
+
+	return token, err
+}
+
+func (r *RefreshTokenPostgres) RotateRefreshToken(id, replacedBy int) error {
+	query := fmt.Sprintf("UPDATE %s SET revoked_at=NOW(), replaced_by=$1 WHERE id=$2", refreshTokensTable)
+	_, err := r.db.Exec(query, replacedBy, id)
+
+	return err
+}
+
+func (r *RefreshTokenPostgres) RevokeRefreshTokenFamily(familyId string) error {
+	query := fmt.Sprintf("UPDATE %s SET revoked_at=NOW() WHERE family_id=$1 AND revoked_at IS NULL", refreshTokensTable)
+	_, err := r.db.Exec(query, familyId)
+
+	return err
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+	filmoteka "vk_restAPI"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshTokenPostgres_CreateRefreshToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	tokenRepo := NewRefreshTokenPostgres(sqlxDB)
+
+	expiresAt := time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	type args struct {
+		token filmoteka.RefreshToken
+	}
+	type mockBehaivior func(args args, id int)
+
+	testTable := []struct {
+		name          string
+		mockBehaivior mockBehaivior
+		args          args
+		id            int
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				token: filmoteka.RefreshToken{
+					UserId:    1,
+					FamilyId:  "family",
+					TokenHash: "hash",
+					ExpiresAt: expiresAt,
+				},
+			},
+			id: 3,
+			mockBehaivior: func(args args, id int) {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(id)
+
+				mock.ExpectQuery("INSERT INTO refreshtokens").
+					WithArgs(args.token.UserId, args.token.FamilyId, args.token.TokenHash, args.token.ExpiresAt).
+					WillReturnRows(rows)
+			},
+		},
+		{
+			name: "Failure",
+			args: args{
+				token: filmoteka.RefreshToken{
+					UserId:    1,
+					FamilyId:  "family",
+					TokenHash: "hash",
+					ExpiresAt: expiresAt,
+				},
+			},
+			mockBehaivior: func(args args, id int) {
+				mock.ExpectQuery("INSERT INTO refreshtokens").
+					WithArgs(args.token.UserId, args.token.FamilyId, args.token.TokenHash, args.token.ExpiresAt).
+					WillReturnError(errors.New("some error"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehaivior(testCase.args, testCase.id)
+
+			got, err := tokenRepo.CreateRefreshToken(testCase.args.token)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.id, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRefreshTokenPostgres_GetRefreshToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	tokenRepo := NewRefreshTokenPostgres(sqlxDB)
+
+	expiresAt := time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)
+	revokedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name          string
+		mockBehaivior func(tokenHash string)
+		tokenHash     string
+		want          filmoteka.RefreshToken
+		wantErr       error
+	}{
+		{
+			name:      "OK",
+			tokenHash: "hash",
+			mockBehaivior: func(tokenHash string) {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "family_id", "token_hash", "expires_at", "revoked_at"}).
+					AddRow(1, 2, "family", tokenHash, expiresAt, revokedAt)
+
+				mock.ExpectQuery("SELECT id, user_id, family_id, token_hash, expires_at, revoked_at FROM refreshtokens WHERE token_hash=\\$1 FOR UPDATE").
+					WithArgs(tokenHash).WillReturnRows(rows)
+			},
+			want: filmoteka.RefreshToken{
+				Id:        1,
+				UserId:    2,
+				FamilyId:  "family",
+				TokenHash: "hash",
+				ExpiresAt: expiresAt,
+				RevokedAt: &revokedAt,
+			},
+		},
+		{
+			name:      "Not Found",
+			tokenHash: "unknown",
+			mockBehaivior: func(tokenHash string) {
+				mock.ExpectQuery("SELECT (.+) FROM refreshtokens WHERE token_hash=\\$1").
+					WithArgs(tokenHash).WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehaivior(testCase.tokenHash)
+
+			got, err := tokenRepo.GetRefreshToken(testCase.tokenHash)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRefreshTokenPostgres_RotateRefreshToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	tokenRepo := NewRefreshTokenPostgres(sqlxDB)
+
+	mock.ExpectExec("UPDATE refreshtokens SET revoked_at=NOW\\(\\), replaced_by=\\$1 WHERE id=\\$2").
+		WithArgs(2, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = tokenRepo.RotateRefreshToken(1, 2)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshTokenPostgres_RevokeRefreshTokenFamily(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	tokenRepo := NewRefreshTokenPostgres(sqlxDB)
+
+	mock.ExpectExec("UPDATE refreshtokens SET revoked_at=NOW\\(\\) WHERE family_id=\\$1 AND revoked_at IS NULL").
+		WithArgs("family").WillReturnResult(sqlmock.NewResult(0, 3))
+
+	err = tokenRepo.RevokeRefreshTokenFamily("family")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -6,52 +6,135 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+//go:generate mockgen -source=repository.go -destination=mocks/mock.go
+
 type Authorization interface {
 	CreateUser(user filmoteka.User) (int, error)
-	GetUser(username, password string) (filmoteka.User, error)
-	GetUserStatus(id int) (bool, error)
+	GetUser(username string) (filmoteka.User, error)
+	UpdatePasswordHash(id int, passwordHash string) error
+	GetUserRole(id int) (string, error)
+	SetUserRole(id int, role string) error
+}
+
+type RefreshTokens interface {
+	CreateRefreshToken(token filmoteka.RefreshToken) (int, error)
+	GetRefreshToken(tokenHash string) (filmoteka.RefreshToken, error)
+	RotateRefreshToken(id, replacedBy int) error
+	RevokeRefreshTokenFamily(familyId string) error
 }
 
 type Actors interface {
 	CreateActor(actor filmoteka.Actors) (int, error)
-	DeleteActor(actorId int) error
-	UpdateActor(actorId int, input filmoteka.UpdateActors) error
+	DeleteActor(actorId int, mode string) error
+	UpdateActor(actorId int, input filmoteka.UpdateActors, version int) (int, error)
 }
 
 type Movies interface {
-	CreateMovie(movie filmoteka.Movies, actorIDs []int) (int, error)
+	CreateMovie(movie filmoteka.Movies) (int, error)
+	SetMovieActors(movieId int, cast []filmoteka.CastEntry) error
+	SetMovieCrew(movieId int, crew []filmoteka.CrewEntry) error
+	SetMovieGenres(movieId int, genreIDs []int) error
 	DeleteMovie(movieId int) error
 }
 
+type Genres interface {
+	CreateGenre(genre filmoteka.Genre) (int, error)
+	GetGenres() ([]filmoteka.Genre, error)
+	GetGenreById(genreId int) (filmoteka.Genre, error)
+	UpdateGenre(genreId int, genre filmoteka.Genre) error
+	DeleteGenre(genreId int) error
+}
+
+type People interface {
+	CreatePerson(person filmoteka.Person) (int, error)
+	GetPeople(params filmoteka.PersonListParams) (filmoteka.PeopleList, error)
+	GetPersonById(personId int) (filmoteka.PersonWithCredits, error)
+	UpdatePerson(personId int, input filmoteka.UpdatePerson, version int) (int, error)
+	DeletePerson(personId int) error
+}
+
+type Reviews interface {
+	CreateReview(movieId, userId int, input filmoteka.ReviewInput) (int, error)
+	GetReviews(params filmoteka.ReviewListParams) (filmoteka.ReviewsList, error)
+	UpdateReview(reviewId, userId int, input filmoteka.UpdateReview) error
+	DeleteReview(reviewId, userId int) error
+	SetReviewStatus(reviewId int, status string) error
+}
+
+type Collections interface {
+	CreateCollection(userId int, input filmoteka.CollectionInput, slug string) (int, error)
+	GetCollections(userId int) ([]filmoteka.Collection, error)
+	GetCollection(collectionId, userId int) (filmoteka.Collection, error)
+	GetSharedCollection(slug string) (filmoteka.Collection, error)
+	UpdateCollection(collectionId, userId int, input filmoteka.UpdateCollection, slug string) error
+	DeleteCollection(collectionId, userId int) error
+	GetCollectionMovies(collectionId int, params filmoteka.CollectionMoviesParams) (filmoteka.CollectionMoviesList, error)
+	AddCollectionMovie(collectionId, userId, movieId int) error
+	RemoveCollectionMovie(collectionId, userId, movieId int) error
+	ReorderCollection(collectionId, userId int, movieIds []int) error
+}
+
+type Watched interface {
+	MarkWatched(userId, movieId int, input filmoteka.WatchedInput) error
+	UnmarkWatched(userId, movieId int) error
+	GetWatched(userId int, params filmoteka.WatchedListParams) (filmoteka.WatchedList, error)
+	GetWatchedStats(userId int) (filmoteka.WatchedStats, error)
+	GetMovieStates(userId int, movieIds []int) (map[int]filmoteka.MovieState, error)
+}
+
 type ActorsWithMovies interface {
-	GetActors() ([]filmoteka.ActorsWithMovies, error)
+	GetActors(params filmoteka.ActorListParams) (filmoteka.ActorsList, error)
 	GetActorById(actorId int) (filmoteka.ActorsWithMovies, error)
 }
 
 type MoviesWithActors interface {
-	GetMovies() ([]filmoteka.MoviesWithActors, error)
-	GetMoviesSortedByTitle() ([]filmoteka.MoviesWithActors, error)
-	GetMoviesSortedByDate() ([]filmoteka.MoviesWithActors, error)
+	GetMovies(params filmoteka.MovieListParams) (filmoteka.MoviesList, error)
 	GetMovieById(movieId int) (filmoteka.MoviesWithActors, error)
-	UpdateMovie(movieId int, input filmoteka.UpdateMovies) error
+	UpdateMovie(movieId int, input filmoteka.UpdateMovies, version int) (int, error)
+	SearchMovies(params filmoteka.MovieSearchParams) (filmoteka.MovieSearchList, error)
 	SearchMoviesByTitle(fragment string) ([]filmoteka.MoviesWithActors, error)
 	SearchMovieByActorName(fragment string) ([]filmoteka.MoviesWithActors, error)
 }
 
+type Search interface {
+	FuzzySearch(params filmoteka.FuzzySearchParams) (filmoteka.FuzzySearchResults, error)
+	Autocomplete(params filmoteka.FuzzySearchParams) ([]filmoteka.Suggestion, error)
+}
+
 type Repository struct {
 	Authorization
+	RefreshTokens
 	Actors
 	Movies
 	MoviesWithActors
 	ActorsWithMovies
+	Genres
+	People
+	Reviews
+	Collections
+	Watched
+	Search
+	Transactor
 }
 
 func NewRepository(db *sqlx.DB) *Repository {
+	return newRepository(db)
+}
+
+func newRepository(db Executor) *Repository {
 	return &Repository{
 		Authorization:    NewAuthPostgres(db),
+		RefreshTokens:    NewRefreshTokenPostgres(db),
 		Actors:           NewActorPostgres(db),
 		Movies:           NewMoviePostgres(db),
 		MoviesWithActors: NewMoviePostgres(db),
 		ActorsWithMovies: NewActorPostgres(db),
+		Genres:           NewGenrePostgres(db),
+		People:           NewPeoplePostgres(db),
+		Reviews:          NewReviewPostgres(db),
+		Collections:      NewCollectionPostgres(db),
+		Watched:          NewWatchedPostgres(db),
+		Search:           NewSearchPostgres(db),
+		Transactor:       NewTxManager(db),
 	}
 }
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	filmoteka "vk_restAPI"
+)
+
+type ReviewPostgres struct {
+	db Executor
+}
+
+func NewReviewPostgres(db Executor) *ReviewPostgres {
+	return &ReviewPostgres{db: db}
+}
+
+// CreateReview adds userId's review of movieId. A user who has already
+// reviewed the movie gets ErrReviewExists.
+func (r *ReviewPostgres) CreateReview(movieId, userId int, input filmoteka.ReviewInput) (int, error) {
+	var id int
+
+	query := fmt.Sprintf("INSERT INTO %s (movie_id, user_id, rating, body) VALUES ($1, $2, $3, $4) RETURNING id", reviewsTable)
+	err := r.db.QueryRow(query, movieId, userId, input.Rating, input.Body).Scan(&id)
+	switch {
+	case isUniqueViolation(err):
+		return 0, filmoteka.ErrReviewExists
+	case isForeignKeyViolation(err):
+		return 0, filmoteka.ErrMovieNotFound
+	}
+	return id, dbError(err)
+}
+
+func (r *ReviewPostgres) GetReviews(params filmoteka.ReviewListParams) (filmoteka.ReviewsList, error) {
+	var list filmoteka.ReviewsList
+
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+
+	if params.MovieId != 0 {
+		args = append(args, params.MovieId)
+		conditions = append(conditions, fmt.Sprintf("rv.movie_id = $%d", len(args)))
+	}
+
+	if params.UserId != 0 {
+		args = append(args, params.UserId)
+		conditions = append(conditions, fmt.Sprintf("rv.user_id = $%d", len(args)))
+	}
+
+	if params.Status != "" {
+		args = append(args, params.Status)
+		conditions = append(conditions, fmt.Sprintf("rv.status = $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s rv %s", reviewsTable, where)
+	if err := r.db.Get(&list.Total, countQuery, args...); err != nil {
+		return list, err
+	}
+
+	args = append(args, params.Limit, params.Offset)
+
+	query := fmt.Sprintf(`
+		SELECT 
+			rv.id, 
+			rv.movie_id, 
+			m.title AS movie_title, 
+			rv.user_id, 
+			u.username, 
+			rv.rating, 
+			rv.body, 
+			rv.status, 
+			rv.created_at, 
+			rv.updated_at
+		FROM 
+			%s rv
+		JOIN 
+			%s m ON rv.movie_id = m.id
+		JOIN 
+			%s u ON rv.user_id = u.id
+		%s
+		ORDER BY 
+			rv.created_at DESC, rv.id DESC
+		LIMIT $%d OFFSET $%d
+	`, reviewsTable, moviesTable, userTable, where, len(args)-1, len(args))
+
+	if err := r.db.Select(&list.Reviews, query, args...); err != nil {
+		return list, err
+	}
+
+	return list, nil
+}
+
+// UpdateReview edits a review written by userId. Someone else's review is
+// reported as not found.
+func (r *ReviewPostgres) UpdateReview(reviewId, userId int, input filmoteka.UpdateReview) error {
+	setValues := make([]string, 0)
+	args := make([]interface{}, 0)
+
+	set := func(column string, value interface{}) {
+		args = append(args, value)
+		setValues = append(setValues, fmt.Sprintf("%s=$%d", column, len(args)))
+	}
+
+	if input.Rating != nil {
+		set("rating", *input.Rating)
+	}
+
+	if input.Body != nil {
+		set("body", *input.Body)
+	}
+
+	args = append(args, reviewId, userId)
+	query := fmt.Sprintf("UPDATE %s SET %s, updated_at=NOW() WHERE id=$%d AND user_id=$%d",
+		reviewsTable, strings.Join(setValues, ", "), len(args)-1, len(args))
+
+	res, err := r.db.Exec(query, args...)
+	return affectOne(res, err, filmoteka.ErrReviewNotFound)
+}
+
+// DeleteReview deletes a review written by userId, or any review when
+// userId is 0.
+func (r *ReviewPostgres) DeleteReview(reviewId, userId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id=$1", reviewsTable)
+	args := []interface{}{reviewId}
+
+	if userId != 0 {
+		query += " AND user_id=$2"
+		args = append(args, userId)
+	}
+
+	res, err := r.db.Exec(query, args...)
+	return affectOne(res, err, filmoteka.ErrReviewNotFound)
+}
+
+func (r *ReviewPostgres) SetReviewStatus(reviewId int, status string) error {
+	query := fmt.Sprintf("UPDATE %s SET status=$1 WHERE id=$2", reviewsTable)
+	res, err := r.db.Exec(query, status, reviewId)
+	return affectOne(res, err, filmoteka.ErrReviewNotFound)
+}
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+	filmoteka "vk_restAPI"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewPostgres_CreateReview(t *testing.T) {
+	type mockBehavior func(mock sqlmock.Sqlmock)
+
+	input := filmoteka.ReviewInput{Rating: 9, Body: "Лучший фильм года"}
+	insert := regexp.QuoteMeta("INSERT INTO reviews (movie_id, user_id, rating, body) VALUES ($1, $2, $3, $4) RETURNING id")
+
+	testTable := []struct {
+		name          string
+		mockBehavior  mockBehavior
+		expectedId    int
+		expectedError error
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(insert).WithArgs(1, 2, 9, "Лучший фильм года").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+			},
+			expectedId: 5,
+		},
+		{
+			name: "Already Reviewed",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(insert).WithArgs(1, 2, 9, "Лучший фильм года").
+					WillReturnError(&pq.Error{Code: "23505"})
+			},
+			expectedError: filmoteka.ErrReviewExists,
+		},
+		{
+			name: "No Movie",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(insert).WithArgs(1, 2, 9, "Лучший фильм года").
+					WillReturnError(&pq.Error{Code: "23503"})
+			},
+			expectedError: filmoteka.ErrMovieNotFound,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+			}
+			defer db.Close()
+
+			repo := NewReviewPostgres(sqlx.NewDb(db, "sqlmock"))
+			testCase.mockBehavior(mock)
+
+			id, err := repo.CreateReview(1, 2, input)
+
+			if testCase.expectedError != nil {
+				assert.ErrorIs(t, err, testCase.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expectedId, id)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReviewPostgres_GetReviews(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	repo := NewReviewPostgres(sqlx.NewDb(db, "sqlmock"))
+
+	created := time.Date(2024, 3, 8, 20, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM reviews rv WHERE rv.movie_id = $1 AND rv.status = $2")).
+		WithArgs(1, "published").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM reviews rv JOIN movies m ON rv.movie_id = m.id JOIN users u ON rv.user_id = u.id "+
+		"WHERE rv.movie_id = $1 AND rv.status = $2 ORDER BY rv.created_at DESC, rv.id DESC LIMIT $3 OFFSET $4")).
+		WithArgs(1, "published", 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "movie_id", "movie_title", "user_id", "username", "rating", "body", "status", "created_at", "updated_at"}).
+			AddRow(5, 1, "Дюна 2", 2, "user", 9, "Лучший фильм года", "published", created, created))
+
+	list, err := repo.GetReviews(filmoteka.ReviewListParams{MovieId: 1, Status: filmoteka.ReviewPublished, Limit: 20})
+
+	assert.NoError(t, err)
+	assert.Equal(t, filmoteka.ReviewsList{
+		Reviews: []filmoteka.Review{{
+			Id: 5, MovieId: 1, MovieTitle: "Дюна 2", UserId: 2, Username: "user", Rating: 9,
+			Body: "Лучший фильм года", Status: "published", CreatedAt: created, UpdatedAt: created,
+		}},
+		Total: 1,
+	}, list)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewPostgres_UpdateReview(t *testing.T) {
+	type mockBehavior func(mock sqlmock.Sqlmock)
+
+	rating := 7
+	update := regexp.QuoteMeta("UPDATE reviews SET rating=$1, body=$2, updated_at=NOW() WHERE id=$3 AND user_id=$4")
+
+	testTable := []struct {
+		name          string
+		mockBehavior  mockBehavior
+		expectedError error
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(update).WithArgs(7, "", 5, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Someone Else's Review",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(update).WithArgs(7, "", 5, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedError: filmoteka.ErrReviewNotFound,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+			}
+			defer db.Close()
+
+			repo := NewReviewPostgres(sqlx.NewDb(db, "sqlmock"))
+			testCase.mockBehavior(mock)
+
+			err = repo.UpdateReview(5, 2, filmoteka.UpdateReview{Rating: &rating, Body: new(string)})
+
+			if testCase.expectedError != nil {
+				assert.ErrorIs(t, err, testCase.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReviewPostgres_DeleteReview(t *testing.T) {
+	type mockBehavior func(mock sqlmock.Sqlmock)
+
+	testTable := []struct {
+		name         string
+		userId       int
+		mockBehavior mockBehavior
+	}{
+		{
+			name:   "Own Review",
+			userId: 2,
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM reviews WHERE id=$1 AND user_id=$2")).
+					WithArgs(5, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:   "Any Review",
+			userId: 0,
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM reviews WHERE id=$1")).
+					WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+			}
+			defer db.Close()
+
+			repo := NewReviewPostgres(sqlx.NewDb(db, "sqlmock"))
+			testCase.mockBehavior(mock)
+
+			assert.NoError(t, repo.DeleteReview(5, testCase.userId))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReviewPostgres_SetReviewStatus(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	repo := NewReviewPostgres(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE reviews SET status=$1 WHERE id=$2")).
+		WithArgs("hidden", 5).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.SetReviewStatus(5, filmoteka.ReviewHidden)
+
+	assert.ErrorIs(t, err, filmoteka.ErrReviewNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
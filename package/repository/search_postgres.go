@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	filmoteka "vk_restAPI"
+)
+
+// actorFullName is the expression actors_name_trgm_idx is built on. Queries
+// must spell it the same way for the index to be used.
+const actorFullName = "(a.first_name || ' ' || a.last_name)"
+
+type SearchPostgres struct {
+	db Executor
+}
+
+func NewSearchPostgres(db Executor) *SearchPostgres {
+	return &SearchPostgres{db: db}
+}
+
+// FuzzySearch finds the actors and the movies whose full name or title is
+// similar to the query, most similar first. Word similarity is used so a
+// single misspelled word still matches a longer name.
+func (s *SearchPostgres) FuzzySearch(params filmoteka.FuzzySearchParams) (filmoteka.FuzzySearchResults, error) {
+	var results filmoteka.FuzzySearchResults
+
+	err := withTx(s.db, func(tx Executor) error {
+		if err := setSimilarityThreshold(tx, params.Threshold); err != nil {
+			return err
+		}
+
+		query := fmt.Sprintf(`
+			SELECT
+				a.id,
+				a.first_name,
+				a.last_name,
+				word_similarity($1, %[2]s) AS similarity
+			FROM
+				%[1]s a
+			WHERE
+				$1 <%% %[2]s
+			ORDER BY
+				similarity DESC, a.id
+			LIMIT $2
+		`, actorsTable, actorFullName)
+		if err := tx.Select(&results.Actors, query, params.Query, params.Limit); err != nil {
+			return err
+		}
+
+		query = fmt.Sprintf(`
+			SELECT
+				m.id,
+				m.title,
+				TO_CHAR(m.release_date, 'YYYY-MM-DD') AS release_date,
+				word_similarity($1, m.title) AS similarity
+			FROM
+				%s m
+			WHERE
+				$1 <%% m.title
+			ORDER BY
+				similarity DESC, m.id
+			LIMIT $2
+		`, moviesTable)
+		return tx.Select(&results.Movies, query, params.Query, params.Limit)
+	})
+
+	return results, err
+}
+
+// Autocomplete suggests actor names and movie titles for what has been
+// typed so far. Each side takes its nearest names from the trigram index
+// by distance, so the cost doesn't grow with the catalog.
+func (s *SearchPostgres) Autocomplete(params filmoteka.FuzzySearchParams) ([]filmoteka.Suggestion, error) {
+	var suggestions []filmoteka.Suggestion
+
+	err := withTx(s.db, func(tx Executor) error {
+		if err := setSimilarityThreshold(tx, params.Threshold); err != nil {
+			return err
+		}
+
+		query := fmt.Sprintf(`
+			(SELECT
+				'%[3]s' AS type,
+				a.id,
+				TRIM(%[5]s) AS label,
+				word_similarity($1, %[5]s) AS similarity
+			FROM
+				%[1]s a
+			WHERE
+				$1 <%% %[5]s
+			ORDER BY
+				$1 <<-> %[5]s
+			LIMIT $2)
+			UNION ALL
+			(SELECT
+				'%[4]s' AS type,
+				m.id,
+				m.title AS label,
+				word_similarity($1, m.title) AS similarity
+			FROM
+				%[2]s m
+			WHERE
+				$1 <%% m.title
+			ORDER BY
+				$1 <<-> m.title
+			LIMIT $2)
+			ORDER BY
+				similarity DESC, label
+			LIMIT $2
+		`, actorsTable, moviesTable, filmoteka.SuggestionActor, filmoteka.SuggestionMovie, actorFullName)
+		return tx.Select(&suggestions, query, params.Query, params.Limit)
+	})
+
+	return suggestions, err
+}
+
+// setSimilarityThreshold sets the threshold of the <% operator for the rest
+// of the transaction. The operator, unlike a comparison with
+// word_similarity(), can use the trigram indexes.
+func setSimilarityThreshold(tx Executor, threshold float64) error {
+	_, err := tx.Exec("SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)", strconv.FormatFloat(threshold, 'f', -1, 64))
+	return err
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	filmoteka "vk_restAPI"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchPostgres_FuzzySearch(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	repo := NewSearchPostgres(sqlx.NewDb(db, "sqlmock"))
+
+	//The threshold only holds inside the transaction
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)")).
+		WithArgs("0.4").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM actors a WHERE $1 <% (a.first_name || ' ' || a.last_name) ORDER BY similarity DESC, a.id LIMIT $2")).
+		WithArgs("Шаламэ", 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "first_name", "last_name", "similarity"}).AddRow(1, "Тимоти", "Шаламе", 0.71))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM movies m WHERE $1 <% m.title ORDER BY similarity DESC, m.id LIMIT $2")).
+		WithArgs("Шаламэ", 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "release_date", "similarity"}))
+	mock.ExpectCommit()
+
+	results, err := repo.FuzzySearch(filmoteka.FuzzySearchParams{Query: "Шаламэ", Threshold: 0.4, Limit: 5})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []filmoteka.ActorMatch{{ActorSummary: filmoteka.ActorSummary{Id: 1, FirstName: "Тимоти", LastName: "Шаламе"}, Similarity: 0.71}}, results.Actors)
+	assert.Empty(t, results.Movies)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchPostgres_Autocomplete(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	repo := NewSearchPostgres(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)")).
+		WithArgs("0.3").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("ORDER BY $1 <<-> (a.first_name || ' ' || a.last_name) LIMIT $2) UNION ALL")).
+		WithArgs("дика", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"type", "id", "label", "similarity"}).
+			AddRow("actor", 7, "Леонардо ДиКаприо", 0.8))
+	mock.ExpectCommit()
+
+	suggestions, err := repo.Autocomplete(filmoteka.FuzzySearchParams{Query: "дика", Threshold: 0.3, Limit: 10})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []filmoteka.Suggestion{{Type: filmoteka.SuggestionActor, Id: 7, Label: "Леонардо ДиКаприо", Similarity: 0.8}}, suggestions)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Executor is satisfied by both *sqlx.DB and *sqlx.Tx, so the same
+// repository can run standalone or as part of a unit of work.
+type Executor interface {
+	sqlx.Ext
+	Get(dest interface{}, query string, args ...interface{}) error
+	Select(dest interface{}, query string, args ...interface{}) error
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// Transactor lets the service layer compose several repository calls
+// into one atomic unit of work.
+type Transactor interface {
+	WithinTransaction(fn func(repos *Repository) error) error
+}
+
+type TxManager struct {
+	db Executor
+}
+
+func NewTxManager(db Executor) *TxManager {
+	return &TxManager{db: db}
+}
+
+// WithinTransaction runs fn against repositories bound to a single
+// transaction, committing if fn succeeds and rolling back otherwise.
+func (t *TxManager) WithinTransaction(fn func(repos *Repository) error) error {
+	return withTx(t.db, func(tx Executor) error {
+		return fn(newRepository(tx))
+	})
+}
+
+// withTx runs fn in a transaction. If db already is a transaction, fn
+// joins it and the outermost caller decides whether to commit.
+func withTx(db Executor, fn func(tx Executor) error) (err error) {
+	conn, ok := db.(*sqlx.DB)
+	if !ok {
+		return fn(db)
+	}
+
+	tx, err := conn.Beginx()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
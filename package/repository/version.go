@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	filmoteka "vk_restAPI"
+)
+
+// updateVersioned applies setValues to the row id of table and bumps its
+// version, returning the new one. setValues use placeholders numbered from
+// 1 in the order of args. When version is not 0 the row is only updated if
+// it is still at that version, otherwise filmoteka.ErrVersionMismatch is
+// returned; notFound is returned when there is no such row at all.
+func updateVersioned(db Executor, table string, id, version int, setValues []string, args []interface{}, notFound error) (int, error) {
+	setValues = append(setValues, "version=version+1")
+
+	args = append(args, id)
+	where := fmt.Sprintf("id=$%d", len(args))
+	if version != 0 {
+		args = append(args, version)
+		where += fmt.Sprintf(" AND version=$%d", len(args))
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s RETURNING version", table, strings.Join(setValues, ", "), where)
+
+	var newVersion int
+	err := db.Get(&newVersion, query, args...)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return newVersion, dbError(err)
+	}
+
+	//Nothing was updated, tell a missing row from a stale version
+	var exists bool
+	query = fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE id=$1)", table)
+	if err := db.Get(&exists, query, id); err != nil {
+		return 0, dbError(err)
+	}
+	if !exists {
+		return 0, notFound
+	}
+	return 0, filmoteka.ErrVersionMismatch
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	filmoteka "vk_restAPI"
+
+	"github.com/lib/pq"
+)
+
+// favoriteActorsLimit is how many actors WatchedStats lists.
+const favoriteActorsLimit = 10
+
+type WatchedPostgres struct {
+	db Executor
+}
+
+func NewWatchedPostgres(db Executor) *WatchedPostgres {
+	return &WatchedPostgres{db: db}
+}
+
+// MarkWatched adds a movie to userId's history, or moves it to the new date
+// and rating if it is there already.
+func (r *WatchedPostgres) MarkWatched(userId, movieId int, input filmoteka.WatchedInput) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (user_id, movie_id, watched_on, rating) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, movie_id) DO UPDATE SET watched_on = EXCLUDED.watched_on, rating = EXCLUDED.rating
+	`, watchedTable)
+
+	_, err := r.db.Exec(query, userId, movieId, input.WatchedOn, input.PersonalRating)
+	if isForeignKeyViolation(err) {
+		return filmoteka.ErrMovieNotFound
+	}
+	return dbError(err)
+}
+
+func (r *WatchedPostgres) UnmarkWatched(userId, movieId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE user_id=$1 AND movie_id=$2", watchedTable)
+	res, err := r.db.Exec(query, userId, movieId)
+	return affectOne(res, err, filmoteka.ErrWatchedNotFound)
+}
+
+func (r *WatchedPostgres) GetWatched(userId int, params filmoteka.WatchedListParams) (filmoteka.WatchedList, error) {
+	var list filmoteka.WatchedList
+
+	conditions, args := movieFilters(filmoteka.MovieListParams{Title: params.Title, GenreIds: params.GenreIds}, []interface{}{userId})
+	conditions = append([]string{"w.user_id = $1"}, conditions...)
+
+	if params.WatchedAfter != "" {
+		args = append(args, params.WatchedAfter)
+		conditions = append(conditions, fmt.Sprintf("w.watched_on >= $%d", len(args)))
+	}
+
+	if params.WatchedBefore != "" {
+		args = append(args, params.WatchedBefore)
+		conditions = append(conditions, fmt.Sprintf("w.watched_on <= $%d", len(args)))
+	}
+
+	if params.MinRating > 0 {
+		args = append(args, params.MinRating)
+		conditions = append(conditions, fmt.Sprintf("w.rating >= $%d", len(args)))
+	}
+
+	from := fmt.Sprintf("%s w JOIN %s m ON m.id = w.movie_id", watchedTable, moviesTable)
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", from, strings.Join(conditions, " AND "))
+	if err := r.db.Get(&list.Total, countQuery, args...); err != nil {
+		return list, err
+	}
+
+	args = append(args, params.Limit, params.Offset)
+
+	query := movieQuery{
+		from:       from,
+		columns:    []string{"TO_CHAR(w.watched_on, 'YYYY-MM-DD') AS watched_on", "w.rating AS personal_rating"},
+		conditions: conditions,
+		orderBy:    "w.watched_on DESC, m.id DESC",
+		limit:      fmt.Sprintf("LIMIT $%d OFFSET $%d", len(args)-1, len(args)),
+	}.String()
+
+	if err := r.db.Select(&list.Movies, query, args...); err != nil {
+		return list, err
+	}
+
+	return list, nil
+}
+
+// GetWatchedStats sums userId's history up in one query. Each movie counts
+// once per actor however many roles the actor has in it.
+func (r *WatchedPostgres) GetWatchedStats(userId int) (filmoteka.WatchedStats, error) {
+	var stats filmoteka.WatchedStats
+
+	query := fmt.Sprintf(`
+		SELECT
+			(SELECT COUNT(*) FROM %[1]s w WHERE w.user_id = $1) AS total,
+			(
+				SELECT COALESCE(ROUND(AVG(w.rating)::numeric, 1), 0)
+				FROM %[1]s w
+				WHERE w.user_id = $1 AND w.rating > 0
+			) AS average_rating,
+			(
+				SELECT COALESCE(json_agg(json_build_object('year', y.year, 'movies', y.movies) ORDER BY y.year DESC), '[]')
+				FROM (
+					SELECT EXTRACT(YEAR FROM w.watched_on)::int AS year, COUNT(*) AS movies
+					FROM %[1]s w
+					WHERE w.user_id = $1
+					GROUP BY 1
+				) y
+			) AS per_year,
+			(
+				SELECT COALESCE(json_agg(json_build_object(
+					'id', a.id, 'first_name', a.first_name, 'last_name', a.last_name, 'movies', a.movies
+				) ORDER BY a.movies DESC, a.last_name, a.id), '[]')
+				FROM (
+					SELECT p.id, p.first_name, p.last_name, COUNT(DISTINCT w.movie_id) AS movies
+					FROM %[1]s w
+					JOIN %[2]s ma ON ma.movie_id = w.movie_id
+					JOIN %[3]s p ON p.id = ma.actor_id
+					WHERE w.user_id = $1
+					GROUP BY p.id
+					ORDER BY movies DESC, p.last_name, p.id
+					LIMIT $2
+				) a
+			) AS favorite_actors
+	`, watchedTable, moviesActorsTable, peopleTable)
+
+	err := r.db.Get(&stats, query, userId, favoriteActorsLimit)
+	return stats, err
+}
+
+// GetMovieStates returns userId's state of each of movieIds with one query,
+// whatever the number of movies.
+func (r *WatchedPostgres) GetMovieStates(userId int, movieIds []int) (map[int]filmoteka.MovieState, error) {
+	var rows []struct {
+		MovieId int `db:"movie_id"`
+		filmoteka.MovieState
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			ids.movie_id,
+			EXISTS (SELECT 1 FROM %s w WHERE w.user_id = $1 AND w.movie_id = ids.movie_id) AS watched,
+			EXISTS (
+				SELECT 1
+				FROM %s ci
+				JOIN %s c ON c.id = ci.collection_id
+				WHERE c.user_id = $1 AND c.kind = '%s' AND ci.movie_id = ids.movie_id
+			) AS in_watchlist
+		FROM unnest($2::int[]) AS ids (movie_id)
+	`, watchedTable, collectionItemsTable, collectionsTable, filmoteka.CollectionWatchlist)
+
+	if err := r.db.Select(&rows, query, userId, pq.Array(movieIds)); err != nil {
+		return nil, err
+	}
+
+	states := make(map[int]filmoteka.MovieState, len(rows))
+	for _, row := range rows {
+		states[row.MovieId] = row.MovieState
+	}
+	return states, nil
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	filmoteka "vk_restAPI"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchedPostgres_MarkWatched(t *testing.T) {
+	type mockBehavior func(mock sqlmock.Sqlmock)
+
+	input := filmoteka.WatchedInput{WatchedOn: "2024-03-08", PersonalRating: 9}
+	upsert := regexp.QuoteMeta("INSERT INTO watched (user_id, movie_id, watched_on, rating) VALUES ($1, $2, $3, $4) " +
+		"ON CONFLICT (user_id, movie_id) DO UPDATE SET watched_on = EXCLUDED.watched_on, rating = EXCLUDED.rating")
+
+	testTable := []struct {
+		name          string
+		mockBehavior  mockBehavior
+		expectedError error
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(upsert).WithArgs(2, 1, "2024-03-08", 9).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "No Movie",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(upsert).WithArgs(2, 1, "2024-03-08", 9).WillReturnError(&pq.Error{Code: "23503"})
+			},
+			expectedError: filmoteka.ErrMovieNotFound,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+			}
+			defer db.Close()
+
+			repo := NewWatchedPostgres(sqlx.NewDb(db, "sqlmock"))
+			testCase.mockBehavior(mock)
+
+			err = repo.MarkWatched(2, 1, input)
+
+			if testCase.expectedError != nil {
+				assert.ErrorIs(t, err, testCase.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWatchedPostgres_GetWatched(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	repo := NewWatchedPostgres(sqlx.NewDb(db, "sqlmock"))
+
+	where := "WHERE w.user_id = $1 AND LOWER(m.title) LIKE LOWER($2) ESCAPE '\\' AND w.watched_on >= $3 AND w.rating >= $4"
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM watched w JOIN movies m ON m.id = w.movie_id "+where)).
+		WithArgs(2, "%дюна%", "2024-01-01", 8).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	query := strings.Replace(movieProjection, "cr.crew, ur.user_rating ",
+		"cr.crew, ur.user_rating, TO_CHAR(w.watched_on, 'YYYY-MM-DD') AS watched_on, w.rating AS personal_rating ", 1)
+	query = strings.Replace(query, "FROM movies m LEFT JOIN LATERAL", "FROM watched w JOIN movies m ON m.id = w.movie_id LEFT JOIN LATERAL", 1)
+	mock.ExpectQuery(regexp.QuoteMeta(query+" "+where+" ORDER BY w.watched_on DESC, m.id DESC LIMIT $5 OFFSET $6")).
+		WithArgs(2, "%дюна%", "2024-01-01", 8, 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "watched_on", "personal_rating"}).AddRow(1, "Дюна 2", "2024-03-08", 9))
+
+	list, err := repo.GetWatched(2, filmoteka.WatchedListParams{Title: "дюна", WatchedAfter: "2024-01-01", MinRating: 8, Limit: 20})
+
+	assert.NoError(t, err)
+	assert.Equal(t, filmoteka.WatchedList{
+		Movies: []filmoteka.WatchedMovie{{
+			MoviesWithActors: filmoteka.MoviesWithActors{Id: 1, Title: "Дюна 2"},
+			WatchedOn:        "2024-03-08",
+			PersonalRating:   9,
+		}},
+		Total: 1,
+	}, list)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWatchedPostgres_GetWatchedStats(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	repo := NewWatchedPostgres(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(regexp.QuoteMeta("AS favorite_actors")).WithArgs(2, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"total", "average_rating", "per_year", "favorite_actors"}).
+			AddRow(3, 8.5, `[{"year":2024,"movies":2},{"year":2023,"movies":1}]`,
+				`[{"id":2,"first_name":"Тимоти","last_name":"Шаламе","movies":2}]`))
+
+	stats, err := repo.GetWatchedStats(2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, filmoteka.WatchedStats{
+		Total:          3,
+		AverageRating:  8.5,
+		PerYear:        filmoteka.WatchedYears{{Year: 2024, Movies: 2}, {Year: 2023, Movies: 1}},
+		FavoriteActors: filmoteka.FavoriteActors{{ActorSummary: filmoteka.ActorSummary{Id: 2, FirstName: "Тимоти", LastName: "Шаламе"}, Movies: 2}},
+	}, stats)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWatchedPostgres_GetMovieStates(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when opening a stub db connection", err)
+	}
+	defer db.Close()
+
+	repo := NewWatchedPostgres(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE c.user_id = $1 AND c.kind = 'watchlist' AND ci.movie_id = ids.movie_id ) AS in_watchlist "+
+		"FROM unnest($2::int[]) AS ids (movie_id)")).
+		WithArgs(2, pq.Array([]int{1, 3})).
+		WillReturnRows(sqlmock.NewRows([]string{"movie_id", "watched", "in_watchlist"}).AddRow(1, true, false).AddRow(3, false, true))
+
+	states, err := repo.GetMovieStates(2, []int{1, 3})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]filmoteka.MovieState{
+		1: {Watched: true},
+		3: {InWatchlist: true},
+	}, states)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -22,24 +22,29 @@ func NewActorsWithMoviesService(repo repository.ActorsWithMovies) *ActorsWithMov
 }
 
 func (a *ActorService) CreateActor(actor filmoteka.Actors) (int, error) {
+	if err := actor.Validate(); err != nil {
+		return 0, err
+	}
 	return a.repo.CreateActor(actor)
 }
 
-func (a *ActorsWithMoviesService) GetActors() ([]filmoteka.ActorsWithMovies, error) {
-	return a.repo.GetActors()
+func (a *ActorsWithMoviesService) GetActors(params filmoteka.ActorListParams) (filmoteka.ActorsList, error) {
+	return a.repo.GetActors(params)
 }
 
 func (a *ActorsWithMoviesService) GetActorById(actorId int) (filmoteka.ActorsWithMovies, error) {
 	return a.repo.GetActorById(actorId)
 }
 
-func (a *ActorService) UpdateActor(actorId int, input filmoteka.UpdateActors) error {
+// UpdateActor applies input if the actor is still at version, or
+// unconditionally when version is 0, and returns the actor's new version.
+func (a *ActorService) UpdateActor(actorId int, input filmoteka.UpdateActors, version int) (int, error) {
 	if err := input.Validate(); err != nil {
-		return err
+		return 0, err
 	}
-	return a.repo.UpdateActor(actorId, input)
+	return a.repo.UpdateActor(actorId, input, version)
 }
 
-func (a *ActorService) DeleteActor(actorId int) error {
-	return a.repo.DeleteActor(actorId)
+func (a *ActorService) DeleteActor(actorId int, mode string) error {
+	return a.repo.DeleteActor(actorId, mode)
 }
@@ -1,83 +1,194 @@
 package service
 
 import (
-	"crypto/sha1"
+	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 	filmoteka "vk_restAPI"
+	logger "vk_restAPI/logs"
 	"vk_restAPI/package/repository"
 
 	"github.com/golang-jwt/jwt/v4"
 )
 
-const (
-	salt       = "sda13/er234/dfsdew3gh5"
-	signingKey = "qesdad"
-	tokenTTL   = 12 * time.Hour
-)
-
 type tokenClaims struct {
 	jwt.StandardClaims
-	UserId int `json:"user_id"`
+	UserId int    `json:"user_id"`
+	Role   string `json:"role"`
 }
 
 type AuthService struct {
-	repo repository.Authorization
+	repo   repository.Authorization
+	tokens repository.RefreshTokens
+	tx     repository.Transactor
+	keys   *KeyRing
 }
 
-func NewAuthService(repo repository.Authorization) *AuthService {
-	return &AuthService{repo: repo}
+func NewAuthService(repo repository.Authorization, tokens repository.RefreshTokens, tx repository.Transactor, keys *KeyRing) *AuthService {
+	return &AuthService{repo: repo, tokens: tokens, tx: tx, keys: keys}
 }
 
+// CreateUser registers a viewer. Other roles are only granted through
+// SetUserRole or BootstrapAdmin, whatever the input says.
 func (a *AuthService) CreateUser(user filmoteka.User) (int, error) {
-	user.Password = generatePassword(user.Password)
+	passwordHash, err := hashPassword(user.Password)
+	if err != nil {
+		return 0, err
+	}
+
+	user.Password = passwordHash
+	user.Role = filmoteka.RoleViewer
 	return a.repo.CreateUser(user)
 }
 
-func (a *AuthService) GetUserStatus(id int) (bool, error) {
-	return a.repo.GetUserStatus(id)
+func (a *AuthService) GetUserRole(id int) (filmoteka.UserRole, error) {
+	role, err := a.repo.GetUserRole(id)
+	if err != nil {
+		return filmoteka.UserRole{}, err
+	}
+
+	return filmoteka.UserRole{UserId: id, Role: role}, nil
+}
+
+// SetUserRole changes the role stored for the user. Access tokens already
+// issued keep the old role until they are refreshed.
+func (a *AuthService) SetUserRole(id int, role string) error {
+	if !filmoteka.ValidRole(role) {
+		return filmoteka.Errorf(filmoteka.ErrValidation, "role must be one of: %s", strings.Join(filmoteka.Roles, ", "))
+	}
+
+	return a.repo.SetUserRole(id, role)
+}
+
+// BootstrapAdmin makes sure username exists and is an admin. An existing
+// account keeps its password and is only promoted.
+func (a *AuthService) BootstrapAdmin(username, password string) (int, error) {
+	user, err := a.repo.GetUser(username)
+	if err == nil {
+		return user.Id, a.repo.SetUserRole(user.Id, filmoteka.RoleAdmin)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+
+	if password == "" {
+		return 0, errors.New("password is required to create the admin account")
+	}
+
+	passwordHash, err := hashPassword(password)
+	if err != nil {
+		return 0, err
+	}
+
+	return a.repo.CreateUser(filmoteka.User{Username: username, Password: passwordHash, Role: filmoteka.RoleAdmin})
 }
 
-func (a *AuthService) GenerateToken(username, password string) (string, error) {
-	user, err := a.repo.GetUser(username, generatePassword(password))
+// GenerateToken logs the user in and starts a new refresh token family.
+func (a *AuthService) GenerateToken(username, password string) (filmoteka.Tokens, error) {
+	user, err := a.authenticate(username, password)
 	if err != nil {
-		return "", err
+		return filmoteka.Tokens{}, err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &tokenClaims{
+	familyId, err := randomToken()
+	if err != nil {
+		return filmoteka.Tokens{}, err
+	}
+
+	tokens, _, err := a.issueTokens(a.tokens, user.Id, user.Role, familyId)
+	return tokens, err
+}
+
+func (a *AuthService) signAccessToken(userId int, role string) (string, error) {
+	key := a.keys.active
+
+	token := jwt.NewWithClaims(key.method(), &tokenClaims{
 		jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(tokenTTL).Unix(),
+			ExpiresAt: time.Now().Add(a.keys.ttl).Unix(),
 			IssuedAt:  time.Now().Unix(),
 		},
-		user.Id,
+		userId,
+		role,
 	})
-	return token.SignedString([]byte(signingKey))
-}
+	token.Header["kid"] = key.Id
 
-func (a *AuthService) ParseToken(accessToken string) (int, error) {
-	token, err := jwt.ParseWithClaims(accessToken, &tokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid singing method")
-		}
+	return token.SignedString(key.signKey)
+}
 
-		return []byte(signingKey), nil
-	})
+func (a *AuthService) ParseToken(accessToken string) (filmoteka.Identity, error) {
+	token, err := jwt.ParseWithClaims(accessToken, &tokenClaims{}, a.verificationKey)
 	if err != nil {
-		return 0, err
+		return filmoteka.Identity{}, err
 	}
 
 	claims, ok := token.Claims.(*tokenClaims)
 	if !ok {
-		return 0, errors.New("token claims are not of type *TokenClaims")
+		return filmoteka.Identity{}, errors.New("token claims are not of type *TokenClaims")
 	}
 
-	return claims.UserId, nil
+	return filmoteka.Identity{UserId: claims.UserId, Role: claims.Role}, nil
+}
+
+// JWKS publishes the public keys tokens can be verified with.
+func (a *AuthService) JWKS() JSONWebKeySet {
+	return a.keys.jwks()
 }
 
-func generatePassword(password string) string {
-	hash := sha1.New()
-	hash.Write([]byte(password))
+// verificationKey picks the key by the kid header. Tokens issued before key
+// ids were introduced carry no kid and are checked against the active key.
+// The algorithm must match the key's, so an RSA public key can't be passed
+// off as an HMAC secret.
+func (a *AuthService) verificationKey(token *jwt.Token) (interface{}, error) {
+	key := a.keys.active
+
+	if kid, ok := token.Header["kid"]; ok {
+		id, _ := kid.(string)
+
+		key, ok = a.keys.keys[id]
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key: %v", kid)
+		}
+	}
+
+	if token.Method.Alg() != key.Algorithm {
+		return nil, errors.New("invalid singing method")
+	}
+
+	return key.verifyKey, nil
+}
+
+// authenticate verifies the password against the stored hash and upgrades
+// legacy or outdated hashes in place. A failed upgrade doesn't fail the login.
+func (a *AuthService) authenticate(username, password string) (filmoteka.User, error) {
+	user, err := a.repo.GetUser(username)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Hash anyway so unknown usernames take as long as wrong passwords.
+		verifyPassword(password, dummyPasswordHash)
+		return user, filmoteka.ErrInvalidCredentials
+	}
+	if err != nil {
+		return user, err
+	}
+
+	ok, needsRehash, err := verifyPassword(password, user.Password)
+	if err != nil {
+		return user, err
+	}
+	if !ok {
+		return user, filmoteka.ErrInvalidCredentials
+	}
+
+	if needsRehash {
+		passwordHash, err := hashPassword(password)
+		if err == nil {
+			err = a.repo.UpdatePasswordHash(user.Id, passwordHash)
+		}
+		if err != nil {
+			logger.Log.Error("Failed to upgrade password hash:", err.Error())
+		}
+	}
 
-	return fmt.Sprintf("%x", hash.Sum([]byte(salt)))
+	return user, nil
 }
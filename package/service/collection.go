@@ -0,0 +1,118 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	filmoteka "vk_restAPI"
+	"vk_restAPI/package/repository"
+)
+
+type CollectionService struct {
+	repo repository.Collections
+}
+
+func NewCollectionService(repo repository.Collections) *CollectionService {
+	return &CollectionService{repo: repo}
+}
+
+func (c *CollectionService) CreateCollection(userId int, input filmoteka.CollectionInput) (int, error) {
+	if err := input.Validate(); err != nil {
+		return 0, err
+	}
+
+	if input.Kind == "" {
+		input.Kind = filmoteka.CollectionCustom
+	}
+
+	var slug string
+	if input.Public {
+		var err error
+		if slug, err = randomSlug(); err != nil {
+			return 0, err
+		}
+	}
+
+	return c.repo.CreateCollection(userId, input, slug)
+}
+
+func (c *CollectionService) GetCollections(userId int) ([]filmoteka.Collection, error) {
+	return c.repo.GetCollections(userId)
+}
+
+func (c *CollectionService) GetCollection(collectionId, userId int) (filmoteka.Collection, error) {
+	return c.repo.GetCollection(collectionId, userId)
+}
+
+func (c *CollectionService) GetSharedCollection(slug string) (filmoteka.Collection, error) {
+	return c.repo.GetSharedCollection(slug)
+}
+
+// UpdateCollection always draws a slug, the repository keeps the one a
+// shared collection already has.
+func (c *CollectionService) UpdateCollection(collectionId, userId int, input filmoteka.UpdateCollection) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	slug, err := randomSlug()
+	if err != nil {
+		return err
+	}
+
+	return c.repo.UpdateCollection(collectionId, userId, input, slug)
+}
+
+func (c *CollectionService) DeleteCollection(collectionId, userId int) error {
+	return c.repo.DeleteCollection(collectionId, userId)
+}
+
+func (c *CollectionService) GetCollectionMovies(collectionId, userId int, params filmoteka.CollectionMoviesParams) (filmoteka.CollectionMoviesList, error) {
+	if err := params.Validate(); err != nil {
+		return filmoteka.CollectionMoviesList{}, err
+	}
+
+	if _, err := c.repo.GetCollection(collectionId, userId); err != nil {
+		return filmoteka.CollectionMoviesList{}, err
+	}
+
+	return c.repo.GetCollectionMovies(collectionId, params)
+}
+
+func (c *CollectionService) GetSharedCollectionMovies(slug string, params filmoteka.CollectionMoviesParams) (filmoteka.CollectionMoviesList, error) {
+	if err := params.Validate(); err != nil {
+		return filmoteka.CollectionMoviesList{}, err
+	}
+
+	collection, err := c.repo.GetSharedCollection(slug)
+	if err != nil {
+		return filmoteka.CollectionMoviesList{}, err
+	}
+
+	return c.repo.GetCollectionMovies(collection.Id, params)
+}
+
+func (c *CollectionService) AddCollectionMovie(collectionId, userId int, item filmoteka.CollectionItem) error {
+	if err := item.Validate(); err != nil {
+		return err
+	}
+	return c.repo.AddCollectionMovie(collectionId, userId, item.MovieId)
+}
+
+func (c *CollectionService) RemoveCollectionMovie(collectionId, userId, movieId int) error {
+	return c.repo.RemoveCollectionMovie(collectionId, userId, movieId)
+}
+
+func (c *CollectionService) ReorderCollection(collectionId, userId int, order filmoteka.CollectionOrder) error {
+	return c.repo.ReorderCollection(collectionId, userId, order.MovieIds)
+}
+
+// randomSlug makes the link a collection is shared by. It is long enough
+// not to be guessed.
+func randomSlug() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	filmoteka "vk_restAPI"
+	"vk_restAPI/package/repository"
+)
+
+type GenreService struct {
+	repo repository.Genres
+}
+
+func NewGenreService(repo repository.Genres) *GenreService {
+	return &GenreService{repo: repo}
+}
+
+func (g *GenreService) CreateGenre(genre filmoteka.Genre) (int, error) {
+	if err := genre.Validate(); err != nil {
+		return 0, err
+	}
+	return g.repo.CreateGenre(genre)
+}
+
+func (g *GenreService) GetGenres() ([]filmoteka.Genre, error) {
+	return g.repo.GetGenres()
+}
+
+func (g *GenreService) GetGenreById(genreId int) (filmoteka.Genre, error) {
+	return g.repo.GetGenreById(genreId)
+}
+
+func (g *GenreService) UpdateGenre(genreId int, genre filmoteka.Genre) error {
+	if err := genre.Validate(); err != nil {
+		return err
+	}
+	return g.repo.UpdateGenre(genreId, genre)
+}
+
+func (g *GenreService) DeleteGenre(genreId int) error {
+	return g.repo.DeleteGenre(genreId)
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// SigningKey is one entry of the key ring. Keys configured with a public key
+// only can verify tokens but not sign them, which is how a retired key is kept
+// around until the tokens it issued expire.
+type SigningKey struct {
+	Id        string
+	Algorithm string
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// NewSigningKey parses key material for alg: a raw secret for HS256, a PEM
+// encoded private or public key for RS256 and EdDSA.
+func NewSigningKey(id, alg string, material []byte) (SigningKey, error) {
+	key := SigningKey{Id: id, Algorithm: alg}
+
+	if id == "" {
+		return key, errors.New("signing key id is required")
+	}
+
+	if len(bytes.TrimSpace(material)) == 0 {
+		return key, fmt.Errorf("signing key %s: key material is empty", id)
+	}
+
+	switch alg {
+	case AlgHS256:
+		key.signKey, key.verifyKey = material, material
+
+	case AlgRS256:
+		if private, err := jwt.ParseRSAPrivateKeyFromPEM(material); err == nil {
+			key.signKey, key.verifyKey = private, &private.PublicKey
+			break
+		}
+
+		public, err := jwt.ParseRSAPublicKeyFromPEM(material)
+		if err != nil {
+			return key, fmt.Errorf("signing key %s: %w", id, err)
+		}
+		key.verifyKey = public
+
+	case AlgEdDSA:
+		if private, err := jwt.ParseEdPrivateKeyFromPEM(material); err == nil {
+			key.signKey, key.verifyKey = private, private.(ed25519.PrivateKey).Public()
+			break
+		}
+
+		public, err := jwt.ParseEdPublicKeyFromPEM(material)
+		if err != nil {
+			return key, fmt.Errorf("signing key %s: %w", id, err)
+		}
+		key.verifyKey = public
+
+	default:
+		return key, fmt.Errorf("signing key %s: unsupported algorithm %s", id, alg)
+	}
+
+	return key, nil
+}
+
+func (k SigningKey) method() jwt.SigningMethod {
+	return jwt.GetSigningMethod(k.Algorithm)
+}
+
+// TokenConfig describes how tokens are issued. ActiveKeyId names the key new
+// access tokens are signed with; every key in Keys is accepted on parse.
+type TokenConfig struct {
+	TTL         time.Duration
+	RefreshTTL  time.Duration
+	ActiveKeyId string
+	Keys        []SigningKey
+}
+
+// KeyRing holds the keys access tokens are signed and verified with, along
+// with the lifetimes of issued tokens.
+type KeyRing struct {
+	ttl        time.Duration
+	refreshTTL time.Duration
+	active     SigningKey
+	keys       map[string]SigningKey
+	order      []string
+}
+
+func NewKeyRing(config TokenConfig) (*KeyRing, error) {
+	if config.TTL <= 0 {
+		return nil, errors.New("token ttl must be positive")
+	}
+
+	if config.RefreshTTL <= config.TTL {
+		return nil, errors.New("refresh token ttl must be longer than token ttl")
+	}
+
+	ring := &KeyRing{ttl: config.TTL, refreshTTL: config.RefreshTTL, keys: make(map[string]SigningKey)}
+
+	for _, key := range config.Keys {
+		if _, ok := ring.keys[key.Id]; ok {
+			return nil, fmt.Errorf("duplicate signing key id: %s", key.Id)
+		}
+		ring.keys[key.Id] = key
+		ring.order = append(ring.order, key.Id)
+	}
+
+	active, ok := ring.keys[config.ActiveKeyId]
+	if !ok {
+		return nil, fmt.Errorf("active signing key %q is not configured", config.ActiveKeyId)
+	}
+
+	if active.signKey == nil {
+		return nil, fmt.Errorf("active signing key %q has no private key", config.ActiveKeyId)
+	}
+
+	ring.active = active
+
+	return ring, nil
+}
+
+// JSONWebKey is the public part of a signing key as published in the JWKS.
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// jwks lists the asymmetric keys of the ring. HMAC secrets are never published.
+func (r *KeyRing) jwks() JSONWebKeySet {
+	set := JSONWebKeySet{Keys: []JSONWebKey{}}
+
+	for _, id := range r.order {
+		key := r.keys[id]
+
+		switch public := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JSONWebKey{
+				Kty: "RSA",
+				Kid: key.Id,
+				Use: "sig",
+				Alg: key.Algorithm,
+				N:   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JSONWebKey{
+				Kty: "OKP",
+				Kid: key.Id,
+				Use: "sig",
+				Alg: key.Algorithm,
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(public),
+			})
+		}
+	}
+
+	return set
+}
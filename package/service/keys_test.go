@@ -0,0 +1,180 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSigningKey(t *testing.T) {
+	testTable := []struct {
+		name     string
+		id       string
+		alg      string
+		material []byte
+		wantErr  bool
+	}{
+		{
+			name:     "HS256",
+			id:       "hs-1",
+			alg:      AlgHS256,
+			material: []byte("test-secret"),
+		},
+		{
+			name:    "Missing material",
+			id:      "hs-1",
+			alg:     AlgHS256,
+			wantErr: true,
+		},
+		{
+			name:     "Blank material",
+			id:       "hs-1",
+			alg:      AlgHS256,
+			material: []byte(" \n"),
+			wantErr:  true,
+		},
+		{
+			name:     "Missing id",
+			alg:      AlgHS256,
+			material: []byte("test-secret"),
+			wantErr:  true,
+		},
+		{
+			name:     "Not a PEM key",
+			id:       "rs-1",
+			alg:      AlgRS256,
+			material: []byte("test-secret"),
+			wantErr:  true,
+		},
+		{
+			name:     "Unsupported algorithm",
+			id:       "hs-1",
+			alg:      "none",
+			material: []byte("test-secret"),
+			wantErr:  true,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			key, err := NewSigningKey(testCase.id, testCase.alg, testCase.material)
+
+			//Asserts
+			if testCase.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.id, key.Id)
+		})
+	}
+}
+
+func TestAuthService_verificationKey(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when generating an RSA key", err)
+	}
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(private)})
+	publicDER, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when encoding an RSA key", err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+
+	hs, err := NewSigningKey("hs-1", AlgHS256, []byte("test-secret"))
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when creating a signing key", err)
+	}
+	rs, err := NewSigningKey("rs-1", AlgRS256, privatePEM)
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when creating a signing key", err)
+	}
+
+	ring, err := NewKeyRing(TokenConfig{TTL: time.Minute, RefreshTTL: time.Hour, ActiveKeyId: "hs-1", Keys: []SigningKey{hs, rs}})
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when creating a key ring", err)
+	}
+	service := NewAuthService(nil, nil, nil, ring)
+
+	sign := func(method jwt.SigningMethod, kid string, key interface{}) string {
+		token := jwt.NewWithClaims(method, &tokenClaims{
+			StandardClaims: jwt.StandardClaims{ExpiresAt: time.Now().Add(time.Minute).Unix()},
+			UserId:         2,
+			Role:           "viewer",
+		})
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("an error '%s' wasn't expected when signing a token", err)
+		}
+		return signed
+	}
+
+	testTable := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{
+			name:  "HS256 kid",
+			token: sign(jwt.SigningMethodHS256, "hs-1", []byte("test-secret")),
+		},
+		{
+			name:  "RS256 kid",
+			token: sign(jwt.SigningMethodRS256, "rs-1", private),
+		},
+		{
+			name:  "No kid uses the active key",
+			token: sign(jwt.SigningMethodHS256, "", []byte("test-secret")),
+		},
+		{
+			name:    "Unknown kid",
+			token:   sign(jwt.SigningMethodHS256, "hs-2", []byte("test-secret")),
+			wantErr: true,
+		},
+		{
+			//The public key is no secret, so an HMAC over it must not pass
+			name:    "HS256 against an RS256 kid",
+			token:   sign(jwt.SigningMethodHS256, "rs-1", publicPEM),
+			wantErr: true,
+		},
+		{
+			name:    "RS256 without kid against the HS256 active key",
+			token:   sign(jwt.SigningMethodRS256, "", private),
+			wantErr: true,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//The key is picked before the signature is checked, so a key
+			//that would fail verification anyway can't hide a missing check
+			token, _, err := new(jwt.Parser).ParseUnverified(testCase.token, &tokenClaims{})
+			if err != nil {
+				t.Fatalf("an error '%s' wasn't expected when parsing a token", err)
+			}
+			key, err := service.verificationKey(token)
+
+			identity, parseErr := service.ParseToken(testCase.token)
+
+			//Asserts
+			if testCase.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, key)
+				assert.Error(t, parseErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, parseErr)
+			assert.Equal(t, 2, identity.UserId)
+		})
+	}
+}
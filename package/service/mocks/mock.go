@@ -7,6 +7,7 @@ package mock_service
 import (
 	reflect "reflect"
 	vk_restAPI "vk_restAPI"
+	service "vk_restAPI/package/service"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -34,6 +35,21 @@ func (m *MockAuthorization) EXPECT() *MockAuthorizationMockRecorder {
 	return m.recorder
 }
 
+// BootstrapAdmin mocks base method.
+func (m *MockAuthorization) BootstrapAdmin(username, password string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapAdmin", username, password)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BootstrapAdmin indicates an expected call of BootstrapAdmin.
+func (mr *MockAuthorizationMockRecorder) BootstrapAdmin(username, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapAdmin", reflect.TypeOf((*MockAuthorization)(nil).BootstrapAdmin), username, password)
+}
+
 // CreateUser mocks base method.
 func (m *MockAuthorization) CreateUser(user vk_restAPI.User) (int, error) {
 	m.ctrl.T.Helper()
@@ -50,10 +66,10 @@ func (mr *MockAuthorizationMockRecorder) CreateUser(user interface{}) *gomock.Ca
 }
 
 // GenerateToken mocks base method.
-func (m *MockAuthorization) GenerateToken(username, password string) (string, error) {
+func (m *MockAuthorization) GenerateToken(username, password string) (vk_restAPI.Tokens, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GenerateToken", username, password)
-	ret0, _ := ret[0].(string)
+	ret0, _ := ret[0].(vk_restAPI.Tokens)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -64,26 +80,54 @@ func (mr *MockAuthorizationMockRecorder) GenerateToken(username, password interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockAuthorization)(nil).GenerateToken), username, password)
 }
 
-// GetUserStatus mocks base method.
-func (m *MockAuthorization) GetUserStatus(id int) (bool, error) {
+// GetUserRole mocks base method.
+func (m *MockAuthorization) GetUserRole(id int) (vk_restAPI.UserRole, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserStatus", id)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "GetUserRole", id)
+	ret0, _ := ret[0].(vk_restAPI.UserRole)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserStatus indicates an expected call of GetUserStatus.
-func (mr *MockAuthorizationMockRecorder) GetUserStatus(id interface{}) *gomock.Call {
+// GetUserRole indicates an expected call of GetUserRole.
+func (mr *MockAuthorizationMockRecorder) GetUserRole(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRole", reflect.TypeOf((*MockAuthorization)(nil).GetUserRole), id)
+}
+
+// JWKS mocks base method.
+func (m *MockAuthorization) JWKS() service.JSONWebKeySet {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JWKS")
+	ret0, _ := ret[0].(service.JSONWebKeySet)
+	return ret0
+}
+
+// JWKS indicates an expected call of JWKS.
+func (mr *MockAuthorizationMockRecorder) JWKS() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserStatus", reflect.TypeOf((*MockAuthorization)(nil).GetUserStatus), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JWKS", reflect.TypeOf((*MockAuthorization)(nil).JWKS))
+}
+
+// Logout mocks base method.
+func (m *MockAuthorization) Logout(refreshToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", refreshToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockAuthorizationMockRecorder) Logout(refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuthorization)(nil).Logout), refreshToken)
 }
 
 // ParseToken mocks base method.
-func (m *MockAuthorization) ParseToken(token string) (int, error) {
+func (m *MockAuthorization) ParseToken(token string) (vk_restAPI.Identity, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ParseToken", token)
-	ret0, _ := ret[0].(int)
+	ret0, _ := ret[0].(vk_restAPI.Identity)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -94,6 +138,35 @@ func (mr *MockAuthorizationMockRecorder) ParseToken(token interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseToken", reflect.TypeOf((*MockAuthorization)(nil).ParseToken), token)
 }
 
+// RefreshToken mocks base method.
+func (m *MockAuthorization) RefreshToken(refreshToken string) (vk_restAPI.Tokens, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshToken", refreshToken)
+	ret0, _ := ret[0].(vk_restAPI.Tokens)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshToken indicates an expected call of RefreshToken.
+func (mr *MockAuthorizationMockRecorder) RefreshToken(refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockAuthorization)(nil).RefreshToken), refreshToken)
+}
+
+// SetUserRole mocks base method.
+func (m *MockAuthorization) SetUserRole(id int, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserRole", id, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserRole indicates an expected call of SetUserRole.
+func (mr *MockAuthorizationMockRecorder) SetUserRole(id, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserRole", reflect.TypeOf((*MockAuthorization)(nil).SetUserRole), id, role)
+}
+
 // MockActors is a mock of Actors interface.
 type MockActors struct {
 	ctrl     *gomock.Controller
@@ -133,31 +206,32 @@ func (mr *MockActorsMockRecorder) CreateActor(actor interface{}) *gomock.Call {
 }
 
 // DeleteActor mocks base method.
-func (m *MockActors) DeleteActor(actorId int) error {
+func (m *MockActors) DeleteActor(actorId int, mode string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteActor", actorId)
+	ret := m.ctrl.Call(m, "DeleteActor", actorId, mode)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteActor indicates an expected call of DeleteActor.
-func (mr *MockActorsMockRecorder) DeleteActor(actorId interface{}) *gomock.Call {
+func (mr *MockActorsMockRecorder) DeleteActor(actorId, mode interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteActor", reflect.TypeOf((*MockActors)(nil).DeleteActor), actorId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteActor", reflect.TypeOf((*MockActors)(nil).DeleteActor), actorId, mode)
 }
 
 // UpdateActor mocks base method.
-func (m *MockActors) UpdateActor(actorId int, input vk_restAPI.UpdateActors) error {
+func (m *MockActors) UpdateActor(actorId int, input vk_restAPI.UpdateActors, version int) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateActor", actorId, input)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "UpdateActor", actorId, input, version)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // UpdateActor indicates an expected call of UpdateActor.
-func (mr *MockActorsMockRecorder) UpdateActor(actorId, input interface{}) *gomock.Call {
+func (mr *MockActorsMockRecorder) UpdateActor(actorId, input, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateActor", reflect.TypeOf((*MockActors)(nil).UpdateActor), actorId, input)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateActor", reflect.TypeOf((*MockActors)(nil).UpdateActor), actorId, input, version)
 }
 
 // MockMovies is a mock of Movies interface.
@@ -184,18 +258,18 @@ func (m *MockMovies) EXPECT() *MockMoviesMockRecorder {
 }
 
 // CreateMovie mocks base method.
-func (m *MockMovies) CreateMovie(movie vk_restAPI.Movies, actorIDs []int) (int, error) {
+func (m *MockMovies) CreateMovie(movie vk_restAPI.Movies, cast []vk_restAPI.CastEntry, crew []vk_restAPI.CrewEntry, genreIDs []int) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateMovie", movie, actorIDs)
+	ret := m.ctrl.Call(m, "CreateMovie", movie, cast, crew, genreIDs)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateMovie indicates an expected call of CreateMovie.
-func (mr *MockMoviesMockRecorder) CreateMovie(movie, actorIDs interface{}) *gomock.Call {
+func (mr *MockMoviesMockRecorder) CreateMovie(movie, cast, crew, genreIDs interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMovie", reflect.TypeOf((*MockMovies)(nil).CreateMovie), movie, actorIDs)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMovie", reflect.TypeOf((*MockMovies)(nil).CreateMovie), movie, cast, crew, genreIDs)
 }
 
 // DeleteMovie mocks base method.
@@ -212,6 +286,573 @@ func (mr *MockMoviesMockRecorder) DeleteMovie(movieId interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMovie", reflect.TypeOf((*MockMovies)(nil).DeleteMovie), movieId)
 }
 
+// MockGenres is a mock of Genres interface.
+type MockGenres struct {
+	ctrl     *gomock.Controller
+	recorder *MockGenresMockRecorder
+}
+
+// MockGenresMockRecorder is the mock recorder for MockGenres.
+type MockGenresMockRecorder struct {
+	mock *MockGenres
+}
+
+// NewMockGenres creates a new mock instance.
+func NewMockGenres(ctrl *gomock.Controller) *MockGenres {
+	mock := &MockGenres{ctrl: ctrl}
+	mock.recorder = &MockGenresMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGenres) EXPECT() *MockGenresMockRecorder {
+	return m.recorder
+}
+
+// CreateGenre mocks base method.
+func (m *MockGenres) CreateGenre(genre vk_restAPI.Genre) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGenre", genre)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGenre indicates an expected call of CreateGenre.
+func (mr *MockGenresMockRecorder) CreateGenre(genre interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGenre", reflect.TypeOf((*MockGenres)(nil).CreateGenre), genre)
+}
+
+// DeleteGenre mocks base method.
+func (m *MockGenres) DeleteGenre(genreId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGenre", genreId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGenre indicates an expected call of DeleteGenre.
+func (mr *MockGenresMockRecorder) DeleteGenre(genreId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGenre", reflect.TypeOf((*MockGenres)(nil).DeleteGenre), genreId)
+}
+
+// GetGenreById mocks base method.
+func (m *MockGenres) GetGenreById(genreId int) (vk_restAPI.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGenreById", genreId)
+	ret0, _ := ret[0].(vk_restAPI.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGenreById indicates an expected call of GetGenreById.
+func (mr *MockGenresMockRecorder) GetGenreById(genreId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenreById", reflect.TypeOf((*MockGenres)(nil).GetGenreById), genreId)
+}
+
+// GetGenres mocks base method.
+func (m *MockGenres) GetGenres() ([]vk_restAPI.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGenres")
+	ret0, _ := ret[0].([]vk_restAPI.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGenres indicates an expected call of GetGenres.
+func (mr *MockGenresMockRecorder) GetGenres() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenres", reflect.TypeOf((*MockGenres)(nil).GetGenres))
+}
+
+// UpdateGenre mocks base method.
+func (m *MockGenres) UpdateGenre(genreId int, genre vk_restAPI.Genre) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGenre", genreId, genre)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateGenre indicates an expected call of UpdateGenre.
+func (mr *MockGenresMockRecorder) UpdateGenre(genreId, genre interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGenre", reflect.TypeOf((*MockGenres)(nil).UpdateGenre), genreId, genre)
+}
+
+// MockPeople is a mock of People interface.
+type MockPeople struct {
+	ctrl     *gomock.Controller
+	recorder *MockPeopleMockRecorder
+}
+
+// MockPeopleMockRecorder is the mock recorder for MockPeople.
+type MockPeopleMockRecorder struct {
+	mock *MockPeople
+}
+
+// NewMockPeople creates a new mock instance.
+func NewMockPeople(ctrl *gomock.Controller) *MockPeople {
+	mock := &MockPeople{ctrl: ctrl}
+	mock.recorder = &MockPeopleMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPeople) EXPECT() *MockPeopleMockRecorder {
+	return m.recorder
+}
+
+// CreatePerson mocks base method.
+func (m *MockPeople) CreatePerson(person vk_restAPI.Person) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePerson", person)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePerson indicates an expected call of CreatePerson.
+func (mr *MockPeopleMockRecorder) CreatePerson(person interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePerson", reflect.TypeOf((*MockPeople)(nil).CreatePerson), person)
+}
+
+// DeletePerson mocks base method.
+func (m *MockPeople) DeletePerson(personId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePerson", personId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePerson indicates an expected call of DeletePerson.
+func (mr *MockPeopleMockRecorder) DeletePerson(personId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePerson", reflect.TypeOf((*MockPeople)(nil).DeletePerson), personId)
+}
+
+// GetPeople mocks base method.
+func (m *MockPeople) GetPeople(params vk_restAPI.PersonListParams) (vk_restAPI.PeopleList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPeople", params)
+	ret0, _ := ret[0].(vk_restAPI.PeopleList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPeople indicates an expected call of GetPeople.
+func (mr *MockPeopleMockRecorder) GetPeople(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPeople", reflect.TypeOf((*MockPeople)(nil).GetPeople), params)
+}
+
+// GetPersonById mocks base method.
+func (m *MockPeople) GetPersonById(personId int) (vk_restAPI.PersonWithCredits, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPersonById", personId)
+	ret0, _ := ret[0].(vk_restAPI.PersonWithCredits)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPersonById indicates an expected call of GetPersonById.
+func (mr *MockPeopleMockRecorder) GetPersonById(personId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPersonById", reflect.TypeOf((*MockPeople)(nil).GetPersonById), personId)
+}
+
+// UpdatePerson mocks base method.
+func (m *MockPeople) UpdatePerson(personId int, input vk_restAPI.UpdatePerson, version int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePerson", personId, input, version)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePerson indicates an expected call of UpdatePerson.
+func (mr *MockPeopleMockRecorder) UpdatePerson(personId, input, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePerson", reflect.TypeOf((*MockPeople)(nil).UpdatePerson), personId, input, version)
+}
+
+// MockReviews is a mock of Reviews interface.
+type MockReviews struct {
+	ctrl     *gomock.Controller
+	recorder *MockReviewsMockRecorder
+}
+
+// MockReviewsMockRecorder is the mock recorder for MockReviews.
+type MockReviewsMockRecorder struct {
+	mock *MockReviews
+}
+
+// NewMockReviews creates a new mock instance.
+func NewMockReviews(ctrl *gomock.Controller) *MockReviews {
+	mock := &MockReviews{ctrl: ctrl}
+	mock.recorder = &MockReviewsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReviews) EXPECT() *MockReviewsMockRecorder {
+	return m.recorder
+}
+
+// CreateReview mocks base method.
+func (m *MockReviews) CreateReview(movieId, userId int, input vk_restAPI.ReviewInput) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReview", movieId, userId, input)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReview indicates an expected call of CreateReview.
+func (mr *MockReviewsMockRecorder) CreateReview(movieId, userId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReview", reflect.TypeOf((*MockReviews)(nil).CreateReview), movieId, userId, input)
+}
+
+// DeleteReview mocks base method.
+func (m *MockReviews) DeleteReview(reviewId, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReview", reviewId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReview indicates an expected call of DeleteReview.
+func (mr *MockReviewsMockRecorder) DeleteReview(reviewId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReview", reflect.TypeOf((*MockReviews)(nil).DeleteReview), reviewId, userId)
+}
+
+// GetReviews mocks base method.
+func (m *MockReviews) GetReviews(params vk_restAPI.ReviewListParams) (vk_restAPI.ReviewsList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviews", params)
+	ret0, _ := ret[0].(vk_restAPI.ReviewsList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviews indicates an expected call of GetReviews.
+func (mr *MockReviewsMockRecorder) GetReviews(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviews", reflect.TypeOf((*MockReviews)(nil).GetReviews), params)
+}
+
+// SetReviewStatus mocks base method.
+func (m *MockReviews) SetReviewStatus(reviewId int, status vk_restAPI.ReviewStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReviewStatus", reviewId, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetReviewStatus indicates an expected call of SetReviewStatus.
+func (mr *MockReviewsMockRecorder) SetReviewStatus(reviewId, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReviewStatus", reflect.TypeOf((*MockReviews)(nil).SetReviewStatus), reviewId, status)
+}
+
+// UpdateReview mocks base method.
+func (m *MockReviews) UpdateReview(reviewId, userId int, input vk_restAPI.UpdateReview) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReview", reviewId, userId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateReview indicates an expected call of UpdateReview.
+func (mr *MockReviewsMockRecorder) UpdateReview(reviewId, userId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReview", reflect.TypeOf((*MockReviews)(nil).UpdateReview), reviewId, userId, input)
+}
+
+// MockCollections is a mock of Collections interface.
+type MockCollections struct {
+	ctrl     *gomock.Controller
+	recorder *MockCollectionsMockRecorder
+}
+
+// MockCollectionsMockRecorder is the mock recorder for MockCollections.
+type MockCollectionsMockRecorder struct {
+	mock *MockCollections
+}
+
+// NewMockCollections creates a new mock instance.
+func NewMockCollections(ctrl *gomock.Controller) *MockCollections {
+	mock := &MockCollections{ctrl: ctrl}
+	mock.recorder = &MockCollectionsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCollections) EXPECT() *MockCollectionsMockRecorder {
+	return m.recorder
+}
+
+// AddCollectionMovie mocks base method.
+func (m *MockCollections) AddCollectionMovie(collectionId, userId int, item vk_restAPI.CollectionItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCollectionMovie", collectionId, userId, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddCollectionMovie indicates an expected call of AddCollectionMovie.
+func (mr *MockCollectionsMockRecorder) AddCollectionMovie(collectionId, userId, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCollectionMovie", reflect.TypeOf((*MockCollections)(nil).AddCollectionMovie), collectionId, userId, item)
+}
+
+// CreateCollection mocks base method.
+func (m *MockCollections) CreateCollection(userId int, input vk_restAPI.CollectionInput) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCollection", userId, input)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCollection indicates an expected call of CreateCollection.
+func (mr *MockCollectionsMockRecorder) CreateCollection(userId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCollection", reflect.TypeOf((*MockCollections)(nil).CreateCollection), userId, input)
+}
+
+// DeleteCollection mocks base method.
+func (m *MockCollections) DeleteCollection(collectionId, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCollection", collectionId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCollection indicates an expected call of DeleteCollection.
+func (mr *MockCollectionsMockRecorder) DeleteCollection(collectionId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCollection", reflect.TypeOf((*MockCollections)(nil).DeleteCollection), collectionId, userId)
+}
+
+// GetCollection mocks base method.
+func (m *MockCollections) GetCollection(collectionId, userId int) (vk_restAPI.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollection", collectionId, userId)
+	ret0, _ := ret[0].(vk_restAPI.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollection indicates an expected call of GetCollection.
+func (mr *MockCollectionsMockRecorder) GetCollection(collectionId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollection", reflect.TypeOf((*MockCollections)(nil).GetCollection), collectionId, userId)
+}
+
+// GetCollectionMovies mocks base method.
+func (m *MockCollections) GetCollectionMovies(collectionId, userId int, params vk_restAPI.CollectionMoviesParams) (vk_restAPI.CollectionMoviesList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollectionMovies", collectionId, userId, params)
+	ret0, _ := ret[0].(vk_restAPI.CollectionMoviesList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollectionMovies indicates an expected call of GetCollectionMovies.
+func (mr *MockCollectionsMockRecorder) GetCollectionMovies(collectionId, userId, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollectionMovies", reflect.TypeOf((*MockCollections)(nil).GetCollectionMovies), collectionId, userId, params)
+}
+
+// GetCollections mocks base method.
+func (m *MockCollections) GetCollections(userId int) ([]vk_restAPI.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollections", userId)
+	ret0, _ := ret[0].([]vk_restAPI.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollections indicates an expected call of GetCollections.
+func (mr *MockCollectionsMockRecorder) GetCollections(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollections", reflect.TypeOf((*MockCollections)(nil).GetCollections), userId)
+}
+
+// GetSharedCollection mocks base method.
+func (m *MockCollections) GetSharedCollection(slug string) (vk_restAPI.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedCollection", slug)
+	ret0, _ := ret[0].(vk_restAPI.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedCollection indicates an expected call of GetSharedCollection.
+func (mr *MockCollectionsMockRecorder) GetSharedCollection(slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedCollection", reflect.TypeOf((*MockCollections)(nil).GetSharedCollection), slug)
+}
+
+// GetSharedCollectionMovies mocks base method.
+func (m *MockCollections) GetSharedCollectionMovies(slug string, params vk_restAPI.CollectionMoviesParams) (vk_restAPI.CollectionMoviesList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedCollectionMovies", slug, params)
+	ret0, _ := ret[0].(vk_restAPI.CollectionMoviesList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedCollectionMovies indicates an expected call of GetSharedCollectionMovies.
+func (mr *MockCollectionsMockRecorder) GetSharedCollectionMovies(slug, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedCollectionMovies", reflect.TypeOf((*MockCollections)(nil).GetSharedCollectionMovies), slug, params)
+}
+
+// RemoveCollectionMovie mocks base method.
+func (m *MockCollections) RemoveCollectionMovie(collectionId, userId, movieId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCollectionMovie", collectionId, userId, movieId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveCollectionMovie indicates an expected call of RemoveCollectionMovie.
+func (mr *MockCollectionsMockRecorder) RemoveCollectionMovie(collectionId, userId, movieId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCollectionMovie", reflect.TypeOf((*MockCollections)(nil).RemoveCollectionMovie), collectionId, userId, movieId)
+}
+
+// ReorderCollection mocks base method.
+func (m *MockCollections) ReorderCollection(collectionId, userId int, order vk_restAPI.CollectionOrder) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReorderCollection", collectionId, userId, order)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReorderCollection indicates an expected call of ReorderCollection.
+func (mr *MockCollectionsMockRecorder) ReorderCollection(collectionId, userId, order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderCollection", reflect.TypeOf((*MockCollections)(nil).ReorderCollection), collectionId, userId, order)
+}
+
+// UpdateCollection mocks base method.
+func (m *MockCollections) UpdateCollection(collectionId, userId int, input vk_restAPI.UpdateCollection) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCollection", collectionId, userId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCollection indicates an expected call of UpdateCollection.
+func (mr *MockCollectionsMockRecorder) UpdateCollection(collectionId, userId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCollection", reflect.TypeOf((*MockCollections)(nil).UpdateCollection), collectionId, userId, input)
+}
+
+// MockWatched is a mock of Watched interface.
+type MockWatched struct {
+	ctrl     *gomock.Controller
+	recorder *MockWatchedMockRecorder
+}
+
+// MockWatchedMockRecorder is the mock recorder for MockWatched.
+type MockWatchedMockRecorder struct {
+	mock *MockWatched
+}
+
+// NewMockWatched creates a new mock instance.
+func NewMockWatched(ctrl *gomock.Controller) *MockWatched {
+	mock := &MockWatched{ctrl: ctrl}
+	mock.recorder = &MockWatchedMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWatched) EXPECT() *MockWatchedMockRecorder {
+	return m.recorder
+}
+
+// GetMovieStates mocks base method.
+func (m *MockWatched) GetMovieStates(userId int, movieIds []int) (map[int]vk_restAPI.MovieState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMovieStates", userId, movieIds)
+	ret0, _ := ret[0].(map[int]vk_restAPI.MovieState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMovieStates indicates an expected call of GetMovieStates.
+func (mr *MockWatchedMockRecorder) GetMovieStates(userId, movieIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovieStates", reflect.TypeOf((*MockWatched)(nil).GetMovieStates), userId, movieIds)
+}
+
+// GetWatched mocks base method.
+func (m *MockWatched) GetWatched(userId int, params vk_restAPI.WatchedListParams) (vk_restAPI.WatchedList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatched", userId, params)
+	ret0, _ := ret[0].(vk_restAPI.WatchedList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWatched indicates an expected call of GetWatched.
+func (mr *MockWatchedMockRecorder) GetWatched(userId, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatched", reflect.TypeOf((*MockWatched)(nil).GetWatched), userId, params)
+}
+
+// GetWatchedStats mocks base method.
+func (m *MockWatched) GetWatchedStats(userId int) (vk_restAPI.WatchedStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatchedStats", userId)
+	ret0, _ := ret[0].(vk_restAPI.WatchedStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWatchedStats indicates an expected call of GetWatchedStats.
+func (mr *MockWatchedMockRecorder) GetWatchedStats(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatchedStats", reflect.TypeOf((*MockWatched)(nil).GetWatchedStats), userId)
+}
+
+// MarkWatched mocks base method.
+func (m *MockWatched) MarkWatched(userId, movieId int, input vk_restAPI.WatchedInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkWatched", userId, movieId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkWatched indicates an expected call of MarkWatched.
+func (mr *MockWatchedMockRecorder) MarkWatched(userId, movieId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWatched", reflect.TypeOf((*MockWatched)(nil).MarkWatched), userId, movieId, input)
+}
+
+// UnmarkWatched mocks base method.
+func (m *MockWatched) UnmarkWatched(userId, movieId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnmarkWatched", userId, movieId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnmarkWatched indicates an expected call of UnmarkWatched.
+func (mr *MockWatchedMockRecorder) UnmarkWatched(userId, movieId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnmarkWatched", reflect.TypeOf((*MockWatched)(nil).UnmarkWatched), userId, movieId)
+}
+
 // MockActorsWithMovies is a mock of ActorsWithMovies interface.
 type MockActorsWithMovies struct {
 	ctrl     *gomock.Controller
@@ -251,18 +892,18 @@ func (mr *MockActorsWithMoviesMockRecorder) GetActorById(actorId interface{}) *g
 }
 
 // GetActors mocks base method.
-func (m *MockActorsWithMovies) GetActors() ([]vk_restAPI.ActorsWithMovies, error) {
+func (m *MockActorsWithMovies) GetActors(params vk_restAPI.ActorListParams) (vk_restAPI.ActorsList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetActors")
-	ret0, _ := ret[0].([]vk_restAPI.ActorsWithMovies)
+	ret := m.ctrl.Call(m, "GetActors", params)
+	ret0, _ := ret[0].(vk_restAPI.ActorsList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetActors indicates an expected call of GetActors.
-func (mr *MockActorsWithMoviesMockRecorder) GetActors() *gomock.Call {
+func (mr *MockActorsWithMoviesMockRecorder) GetActors(params interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActors", reflect.TypeOf((*MockActorsWithMovies)(nil).GetActors))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActors", reflect.TypeOf((*MockActorsWithMovies)(nil).GetActors), params)
 }
 
 // MockMoviesWithActors is a mock of MoviesWithActors interface.
@@ -304,63 +945,48 @@ func (mr *MockMoviesWithActorsMockRecorder) GetMovieById(movieId interface{}) *g
 }
 
 // GetMovies mocks base method.
-func (m *MockMoviesWithActors) GetMovies() ([]vk_restAPI.MoviesWithActors, error) {
+func (m *MockMoviesWithActors) GetMovies(params vk_restAPI.MovieListParams) (vk_restAPI.MoviesList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMovies")
-	ret0, _ := ret[0].([]vk_restAPI.MoviesWithActors)
+	ret := m.ctrl.Call(m, "GetMovies", params)
+	ret0, _ := ret[0].(vk_restAPI.MoviesList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetMovies indicates an expected call of GetMovies.
-func (mr *MockMoviesWithActorsMockRecorder) GetMovies() *gomock.Call {
+func (mr *MockMoviesWithActorsMockRecorder) GetMovies(params interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovies", reflect.TypeOf((*MockMoviesWithActors)(nil).GetMovies))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovies", reflect.TypeOf((*MockMoviesWithActors)(nil).GetMovies), params)
 }
 
-// GetMoviesSortedByDate mocks base method.
-func (m *MockMoviesWithActors) GetMoviesSortedByDate() ([]vk_restAPI.MoviesWithActors, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMoviesSortedByDate")
-	ret0, _ := ret[0].([]vk_restAPI.MoviesWithActors)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// GetMoviesSortedByDate indicates an expected call of GetMoviesSortedByDate.
-func (mr *MockMoviesWithActorsMockRecorder) GetMoviesSortedByDate() *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMoviesSortedByDate", reflect.TypeOf((*MockMoviesWithActors)(nil).GetMoviesSortedByDate))
-}
-
-// GetMoviesSortedByTitle mocks base method.
-func (m *MockMoviesWithActors) GetMoviesSortedByTitle() ([]vk_restAPI.MoviesWithActors, error) {
+// SearchMovieByActorName mocks base method.
+func (m *MockMoviesWithActors) SearchMovieByActorName(fragment string) ([]vk_restAPI.MoviesWithActors, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMoviesSortedByTitle")
+	ret := m.ctrl.Call(m, "SearchMovieByActorName", fragment)
 	ret0, _ := ret[0].([]vk_restAPI.MoviesWithActors)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetMoviesSortedByTitle indicates an expected call of GetMoviesSortedByTitle.
-func (mr *MockMoviesWithActorsMockRecorder) GetMoviesSortedByTitle() *gomock.Call {
+// SearchMovieByActorName indicates an expected call of SearchMovieByActorName.
+func (mr *MockMoviesWithActorsMockRecorder) SearchMovieByActorName(fragment interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMoviesSortedByTitle", reflect.TypeOf((*MockMoviesWithActors)(nil).GetMoviesSortedByTitle))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchMovieByActorName", reflect.TypeOf((*MockMoviesWithActors)(nil).SearchMovieByActorName), fragment)
 }
 
-// SearchMovieByActorName mocks base method.
-func (m *MockMoviesWithActors) SearchMovieByActorName(fragment string) ([]vk_restAPI.MoviesWithActors, error) {
+// SearchMovies mocks base method.
+func (m *MockMoviesWithActors) SearchMovies(params vk_restAPI.MovieSearchParams) (vk_restAPI.MovieSearchList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SearchMovieByActorName", fragment)
-	ret0, _ := ret[0].([]vk_restAPI.MoviesWithActors)
+	ret := m.ctrl.Call(m, "SearchMovies", params)
+	ret0, _ := ret[0].(vk_restAPI.MovieSearchList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// SearchMovieByActorName indicates an expected call of SearchMovieByActorName.
-func (mr *MockMoviesWithActorsMockRecorder) SearchMovieByActorName(fragment interface{}) *gomock.Call {
+// SearchMovies indicates an expected call of SearchMovies.
+func (mr *MockMoviesWithActorsMockRecorder) SearchMovies(params interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchMovieByActorName", reflect.TypeOf((*MockMoviesWithActors)(nil).SearchMovieByActorName), fragment)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchMovies", reflect.TypeOf((*MockMoviesWithActors)(nil).SearchMovies), params)
 }
 
 // SearchMoviesByTitle mocks base method.
@@ -379,15 +1005,69 @@ func (mr *MockMoviesWithActorsMockRecorder) SearchMoviesByTitle(fragment interfa
 }
 
 // UpdateMovie mocks base method.
-func (m *MockMoviesWithActors) UpdateMovie(movieId int, input vk_restAPI.UpdateMovies) error {
+func (m *MockMoviesWithActors) UpdateMovie(movieId int, input vk_restAPI.UpdateMovies, version int) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateMovie", movieId, input)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "UpdateMovie", movieId, input, version)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // UpdateMovie indicates an expected call of UpdateMovie.
-func (mr *MockMoviesWithActorsMockRecorder) UpdateMovie(movieId, input interface{}) *gomock.Call {
+func (mr *MockMoviesWithActorsMockRecorder) UpdateMovie(movieId, input, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMovie", reflect.TypeOf((*MockMoviesWithActors)(nil).UpdateMovie), movieId, input, version)
+}
+
+// MockSearch is a mock of Search interface.
+type MockSearch struct {
+	ctrl     *gomock.Controller
+	recorder *MockSearchMockRecorder
+}
+
+// MockSearchMockRecorder is the mock recorder for MockSearch.
+type MockSearchMockRecorder struct {
+	mock *MockSearch
+}
+
+// NewMockSearch creates a new mock instance.
+func NewMockSearch(ctrl *gomock.Controller) *MockSearch {
+	mock := &MockSearch{ctrl: ctrl}
+	mock.recorder = &MockSearchMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSearch) EXPECT() *MockSearchMockRecorder {
+	return m.recorder
+}
+
+// Autocomplete mocks base method.
+func (m *MockSearch) Autocomplete(params vk_restAPI.FuzzySearchParams) ([]vk_restAPI.Suggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Autocomplete", params)
+	ret0, _ := ret[0].([]vk_restAPI.Suggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Autocomplete indicates an expected call of Autocomplete.
+func (mr *MockSearchMockRecorder) Autocomplete(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Autocomplete", reflect.TypeOf((*MockSearch)(nil).Autocomplete), params)
+}
+
+// FuzzySearch mocks base method.
+func (m *MockSearch) FuzzySearch(params vk_restAPI.FuzzySearchParams) (vk_restAPI.FuzzySearchResults, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FuzzySearch", params)
+	ret0, _ := ret[0].(vk_restAPI.FuzzySearchResults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FuzzySearch indicates an expected call of FuzzySearch.
+func (mr *MockSearchMockRecorder) FuzzySearch(params interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMovie", reflect.TypeOf((*MockMoviesWithActors)(nil).UpdateMovie), movieId, input)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FuzzySearch", reflect.TypeOf((*MockSearch)(nil).FuzzySearch), params)
 }
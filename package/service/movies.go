@@ -7,34 +7,56 @@ import (
 
 type MovieService struct {
 	repo repository.Movies
+	tx   repository.Transactor
 }
 
 type MoviesWithActorsService struct {
 	repo repository.MoviesWithActors
+	tx   repository.Transactor
 }
 
-func NewMovieService(repo repository.Movies) *MovieService {
-	return &MovieService{repo: repo}
+func NewMovieService(repo repository.Movies, tx repository.Transactor) *MovieService {
+	return &MovieService{repo: repo, tx: tx}
 }
 
-func NewMoviesWithActorsService(repo repository.MoviesWithActors) *MoviesWithActorsService {
-	return &MoviesWithActorsService{repo: repo}
+func NewMoviesWithActorsService(repo repository.MoviesWithActors, tx repository.Transactor) *MoviesWithActorsService {
+	return &MoviesWithActorsService{repo: repo, tx: tx}
 }
 
-func (m *MovieService) CreateMovie(movie filmoteka.Movies, actorIDs []int) (int, error) {
-	return m.repo.CreateMovie(movie, actorIDs)
-}
+func (m *MovieService) CreateMovie(movie filmoteka.Movies, cast []filmoteka.CastEntry, crew []filmoteka.CrewEntry, genreIDs []int) (int, error) {
+	if err := movie.Validate(); err != nil {
+		return 0, err
+	}
 
-func (m *MoviesWithActorsService) GetMovies() ([]filmoteka.MoviesWithActors, error) {
-	return m.repo.GetMovies()
-}
+	if err := filmoteka.ValidateEntries("actorIDs", cast); err != nil {
+		return 0, err
+	}
+
+	if err := filmoteka.ValidateEntries("crew", crew); err != nil {
+		return 0, err
+	}
+
+	var id int
+
+	err := m.tx.WithinTransaction(func(repos *repository.Repository) error {
+		var err error
+		if id, err = repos.Movies.CreateMovie(movie); err != nil {
+			return err
+		}
+		if err = repos.Movies.SetMovieActors(id, cast); err != nil {
+			return err
+		}
+		if err = repos.Movies.SetMovieCrew(id, crew); err != nil {
+			return err
+		}
+		return repos.Movies.SetMovieGenres(id, genreIDs)
+	})
 
-func (m *MoviesWithActorsService) GetMoviesSortedByTitle() ([]filmoteka.MoviesWithActors, error) {
-	return m.repo.GetMoviesSortedByTitle()
+	return id, err
 }
 
-func (m *MoviesWithActorsService) GetMoviesSortedByDate() ([]filmoteka.MoviesWithActors, error) {
-	return m.repo.GetMoviesSortedByDate()
+func (m *MoviesWithActorsService) GetMovies(params filmoteka.MovieListParams) (filmoteka.MoviesList, error) {
+	return m.repo.GetMovies(params)
 }
 
 func (m *MoviesWithActorsService) GetMovieById(movieId int) (filmoteka.MoviesWithActors, error) {
@@ -45,11 +67,44 @@ func (m *MovieService) DeleteMovie(movieId int) error {
 	return m.repo.DeleteMovie(movieId)
 }
 
-func (m *MoviesWithActorsService) UpdateMovie(movieId int, input filmoteka.UpdateMovies) error {
+// UpdateMovie applies input if the movie is still at version, or
+// unconditionally when version is 0, and returns the movie's new version.
+func (m *MoviesWithActorsService) UpdateMovie(movieId int, input filmoteka.UpdateMovies, version int) (int, error) {
 	if err := input.Validate(); err != nil {
-		return err
+		return 0, err
 	}
-	return m.repo.UpdateMovie(movieId, input)
+
+	var newVersion int
+
+	err := m.tx.WithinTransaction(func(repos *repository.Repository) error {
+		var err error
+		if newVersion, err = repos.MoviesWithActors.UpdateMovie(movieId, input, version); err != nil {
+			return err
+		}
+
+		if input.Actors != nil {
+			if err = repos.Movies.SetMovieActors(movieId, *input.Actors); err != nil {
+				return err
+			}
+		}
+
+		if input.Crew != nil {
+			if err = repos.Movies.SetMovieCrew(movieId, *input.Crew); err != nil {
+				return err
+			}
+		}
+
+		if input.Genres != nil {
+			return repos.Movies.SetMovieGenres(movieId, *input.Genres)
+		}
+		return nil
+	})
+
+	return newVersion, err
+}
+
+func (m *MoviesWithActorsService) SearchMovies(params filmoteka.MovieSearchParams) (filmoteka.MovieSearchList, error) {
+	return m.repo.SearchMovies(params)
 }
 
 func (m *MoviesWithActorsService) SearchMoviesByTitle(fragment string) ([]filmoteka.MoviesWithActors, error) {
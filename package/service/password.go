@@ -0,0 +1,100 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Argon2id parameters, RFC 9106 recommendation for memory-constrained hosts.
+// Raising any of them makes existing hashes upgrade on the next login.
+const (
+	argonTime    = 3
+	argonMemory  = 64 * 1024
+	argonThreads = 4
+	argonKeyLen  = 32
+	argonSaltLen = 16
+)
+
+// legacySalt was appended to every SHA-1 password hash before argon2id.
+// It is only used to verify and upgrade those hashes.
+const legacySalt = "sda13/er234/dfsdew3gh5"
+
+var errMalformedHash = errors.New("malformed password hash")
+
+// dummyPasswordHash is verified against when the user doesn't exist.
+const dummyPasswordHash = "$argon2id$v=19$m=65536,t=3,p=4$ktg6HoFWFqno2wVhbBe2Mg$8f/HRGCURXpfbdEHtiKhtg2EE1ovlnFISrQUukoM+FI"
+
+// hashPassword returns an argon2id hash with a random per-user salt, encoded
+// as $argon2id$v=19$m=65536,t=3,p=4$<salt>$<key>.
+func hashPassword(password string) (string, error) {
+	salt := make([]byte, argonSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, argonTime, argonMemory, argonThreads, argonKeyLen)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, argonMemory, argonTime, argonThreads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// verifyPassword checks password against a stored hash. needsRehash is set
+// when the hash is a legacy SHA-1 one or uses outdated argon2id parameters.
+func verifyPassword(password, encoded string) (ok bool, needsRehash bool, err error) {
+	if !strings.HasPrefix(encoded, "$argon2id$") {
+		legacy := legacyPasswordHash(password)
+		ok = subtle.ConstantTimeCompare([]byte(legacy), []byte(encoded)) == 1
+		return ok, true, nil
+	}
+
+	var version int
+	var memory, time uint32
+	var threads uint8
+
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return false, false, errMalformedHash
+	}
+
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return false, false, errMalformedHash
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return false, false, errMalformedHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, false, errMalformedHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false, false, errMalformedHash
+	}
+
+	candidate := argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(key)))
+	ok = subtle.ConstantTimeCompare(candidate, key) == 1
+
+	needsRehash = version != argon2.Version || memory != argonMemory || time != argonTime ||
+		threads != argonThreads || len(key) != argonKeyLen || len(salt) != argonSaltLen
+
+	return ok, needsRehash, nil
+}
+
+func legacyPasswordHash(password string) string {
+	hash := sha1.New()
+	hash.Write([]byte(password))
+
+	return fmt.Sprintf("%x", hash.Sum([]byte(legacySalt)))
+}
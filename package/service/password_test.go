@@ -0,0 +1,157 @@
+package service
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+	filmoteka "vk_restAPI"
+	mock_repository "vk_restAPI/package/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/argon2"
+)
+
+// outdatedPasswordHash hashes password with fewer argon2id passes than
+// hashPassword uses now.
+func outdatedPasswordHash(password string) string {
+	salt := []byte("0123456789abcdef")
+	key := argon2.IDKey([]byte(password), salt, argonTime-1, argonMemory, argonThreads, argonKeyLen)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, argonMemory, argonTime-1, argonThreads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key))
+}
+
+func TestVerifyPassword(t *testing.T) {
+	current, err := hashPassword("qwerty")
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when hashing a password", err)
+	}
+
+	testTable := []struct {
+		name            string
+		password        string
+		encoded         string
+		wantOk          bool
+		wantNeedsRehash bool
+	}{
+		{
+			name:            "Legacy",
+			password:        "qwerty",
+			encoded:         legacyPasswordHash("qwerty"),
+			wantOk:          true,
+			wantNeedsRehash: true,
+		},
+		{
+			name:            "Legacy wrong password",
+			password:        "wrong",
+			encoded:         legacyPasswordHash("qwerty"),
+			wantOk:          false,
+			wantNeedsRehash: true,
+		},
+		{
+			name:            "Current",
+			password:        "qwerty",
+			encoded:         current,
+			wantOk:          true,
+			wantNeedsRehash: false,
+		},
+		{
+			name:            "Current wrong password",
+			password:        "wrong",
+			encoded:         current,
+			wantOk:          false,
+			wantNeedsRehash: false,
+		},
+		{
+			name:            "Outdated parameters",
+			password:        "qwerty",
+			encoded:         outdatedPasswordHash("qwerty"),
+			wantOk:          true,
+			wantNeedsRehash: true,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ok, needsRehash, err := verifyPassword(testCase.password, testCase.encoded)
+
+			//Asserts
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.wantOk, ok)
+			assert.Equal(t, testCase.wantNeedsRehash, needsRehash)
+		})
+	}
+}
+
+func TestAuthService_authenticate(t *testing.T) {
+	type mockBehavior func(auth *mock_repository.MockAuthorization)
+
+	current, err := hashPassword("qwerty")
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when hashing a password", err)
+	}
+
+	testTable := []struct {
+		name         string
+		password     string
+		mockBehavior mockBehavior
+		wantErrIs    error
+	}{
+		{
+			name:     "Legacy hash is upgraded",
+			password: "qwerty",
+			mockBehavior: func(auth *mock_repository.MockAuthorization) {
+				auth.EXPECT().GetUser("denis").Return(filmoteka.User{Id: 1, Username: "denis", Password: legacyPasswordHash("qwerty")}, nil)
+				auth.EXPECT().UpdatePasswordHash(1, gomock.Any()).DoAndReturn(func(id int, passwordHash string) error {
+					assert.True(t, strings.HasPrefix(passwordHash, "$argon2id$"))
+
+					ok, needsRehash, err := verifyPassword("qwerty", passwordHash)
+					assert.NoError(t, err)
+					assert.True(t, ok)
+					assert.False(t, needsRehash)
+					return nil
+				})
+			},
+		},
+		{
+			name:     "Legacy hash with wrong password",
+			password: "wrong",
+			mockBehavior: func(auth *mock_repository.MockAuthorization) {
+				auth.EXPECT().GetUser("denis").Return(filmoteka.User{Id: 1, Username: "denis", Password: legacyPasswordHash("qwerty")}, nil)
+			},
+			wantErrIs: filmoteka.ErrInvalidCredentials,
+		},
+		{
+			name:     "Current hash is kept",
+			password: "qwerty",
+			mockBehavior: func(auth *mock_repository.MockAuthorization) {
+				auth.EXPECT().GetUser("denis").Return(filmoteka.User{Id: 1, Username: "denis", Password: current}, nil)
+			},
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_repository.NewMockAuthorization(c)
+			testCase.mockBehavior(auth)
+
+			service := NewAuthService(auth, nil, nil, newTestKeyRing(t))
+
+			user, err := service.authenticate("denis", testCase.password)
+
+			//Asserts
+			if testCase.wantErrIs != nil {
+				assert.ErrorIs(t, err, testCase.wantErrIs)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 1, user.Id)
+		})
+	}
+}
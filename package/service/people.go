@@ -0,0 +1,42 @@
+package service
+
+import (
+	filmoteka "vk_restAPI"
+	"vk_restAPI/package/repository"
+)
+
+type PeopleService struct {
+	repo repository.People
+}
+
+func NewPeopleService(repo repository.People) *PeopleService {
+	return &PeopleService{repo: repo}
+}
+
+func (p *PeopleService) CreatePerson(person filmoteka.Person) (int, error) {
+	if err := person.Validate(); err != nil {
+		return 0, err
+	}
+	return p.repo.CreatePerson(person)
+}
+
+func (p *PeopleService) GetPeople(params filmoteka.PersonListParams) (filmoteka.PeopleList, error) {
+	return p.repo.GetPeople(params)
+}
+
+func (p *PeopleService) GetPersonById(personId int) (filmoteka.PersonWithCredits, error) {
+	return p.repo.GetPersonById(personId)
+}
+
+// UpdatePerson applies input if the person is still at version, or
+// unconditionally when version is 0, and returns the person's new version.
+func (p *PeopleService) UpdatePerson(personId int, input filmoteka.UpdatePerson, version int) (int, error) {
+	if err := input.Validate(); err != nil {
+		return 0, err
+	}
+	return p.repo.UpdatePerson(personId, input, version)
+}
+
+func (p *PeopleService) DeletePerson(personId int) error {
+	return p.repo.DeletePerson(personId)
+}
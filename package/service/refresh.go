@@ -0,0 +1,128 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+	filmoteka "vk_restAPI"
+	logger "vk_restAPI/logs"
+	"vk_restAPI/package/repository"
+)
+
+// RefreshToken exchanges a refresh token for a new token pair. The presented
+// token is rotated out; presenting it again means it has leaked, so the whole
+// family is revoked and every session started from that login has to sign in
+// again.
+func (a *AuthService) RefreshToken(refreshToken string) (filmoteka.Tokens, error) {
+	var tokens filmoteka.Tokens
+	var reused bool
+
+	err := a.tx.WithinTransaction(func(repos *repository.Repository) error {
+		current, err := repos.RefreshTokens.GetRefreshToken(hashToken(refreshToken))
+		if errors.Is(err, sql.ErrNoRows) {
+			return filmoteka.ErrInvalidRefreshToken
+		}
+		if err != nil {
+			return err
+		}
+
+		if current.RevokedAt != nil {
+			reused = true
+			return repos.RefreshTokens.RevokeRefreshTokenFamily(current.FamilyId)
+		}
+
+		if time.Now().After(current.ExpiresAt) {
+			return filmoteka.ErrInvalidRefreshToken
+		}
+
+		// The role is read again so role changes apply from the next refresh.
+		role, err := repos.Authorization.GetUserRole(current.UserId)
+		if err != nil {
+			return err
+		}
+
+		var id int
+		tokens, id, err = a.issueTokens(repos.RefreshTokens, current.UserId, role, current.FamilyId)
+		if err != nil {
+			return err
+		}
+
+		return repos.RefreshTokens.RotateRefreshToken(current.Id, id)
+	})
+	if err != nil {
+		return filmoteka.Tokens{}, err
+	}
+
+	if reused {
+		logger.Log.Warn("Refresh token reuse detected, token family revoked")
+		return filmoteka.Tokens{}, filmoteka.ErrInvalidRefreshToken
+	}
+
+	return tokens, nil
+}
+
+// Logout revokes the refresh token family the token belongs to. Access
+// tokens already issued stay valid until they expire.
+func (a *AuthService) Logout(refreshToken string) error {
+	return a.tx.WithinTransaction(func(repos *repository.Repository) error {
+		current, err := repos.RefreshTokens.GetRefreshToken(hashToken(refreshToken))
+		if errors.Is(err, sql.ErrNoRows) {
+			return filmoteka.ErrInvalidRefreshToken
+		}
+		if err != nil {
+			return err
+		}
+
+		return repos.RefreshTokens.RevokeRefreshTokenFamily(current.FamilyId)
+	})
+}
+
+// issueTokens signs an access token and stores a new refresh token in the
+// given family. It returns the id of the stored refresh token.
+func (a *AuthService) issueTokens(repo repository.RefreshTokens, userId int, role, familyId string) (filmoteka.Tokens, int, error) {
+	accessToken, err := a.signAccessToken(userId, role)
+	if err != nil {
+		return filmoteka.Tokens{}, 0, err
+	}
+
+	refreshToken, err := randomToken()
+	if err != nil {
+		return filmoteka.Tokens{}, 0, err
+	}
+
+	id, err := repo.CreateRefreshToken(filmoteka.RefreshToken{
+		UserId:    userId,
+		FamilyId:  familyId,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: time.Now().Add(a.keys.refreshTTL),
+	})
+	if err != nil {
+		return filmoteka.Tokens{}, 0, err
+	}
+
+	return filmoteka.Tokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(a.keys.ttl.Seconds()),
+	}, id, nil
+}
+
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashToken is what gets stored; refresh tokens are high-entropy, so a plain
+// SHA-256 is enough and keeps lookups by hash possible.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+	filmoteka "vk_restAPI"
+	"vk_restAPI/package/repository"
+	mock_repository "vk_restAPI/package/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// testTransactor runs a unit of work against the given repositories, so
+// mocks can stand in for the ones bound to a transaction.
+type testTransactor struct {
+	repos *repository.Repository
+}
+
+func (t testTransactor) WithinTransaction(fn func(repos *repository.Repository) error) error {
+	return fn(t.repos)
+}
+
+func newTestKeyRing(t *testing.T) *KeyRing {
+	key, err := NewSigningKey("hs-1", AlgHS256, []byte("test-secret"))
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when creating a signing key", err)
+	}
+
+	ring, err := NewKeyRing(TokenConfig{TTL: time.Minute, RefreshTTL: time.Hour, ActiveKeyId: "hs-1", Keys: []SigningKey{key}})
+	if err != nil {
+		t.Fatalf("an error '%s' wasn't expected when creating a key ring", err)
+	}
+	return ring
+}
+
+func TestAuthService_RefreshToken(t *testing.T) {
+	type mockBehavior func(auth *mock_repository.MockAuthorization, tokens *mock_repository.MockRefreshTokens)
+
+	revokedAt := time.Now().Add(-time.Minute)
+
+	testTable := []struct {
+		name         string
+		mockBehavior mockBehavior
+		wantErrIs    error
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(auth *mock_repository.MockAuthorization, tokens *mock_repository.MockRefreshTokens) {
+				tokens.EXPECT().GetRefreshToken(hashToken("old-token")).Return(filmoteka.RefreshToken{
+					Id: 1, UserId: 2, FamilyId: "family", ExpiresAt: time.Now().Add(time.Hour),
+				}, nil)
+				auth.EXPECT().GetUserRole(2).Return(filmoteka.RoleEditor, nil)
+				tokens.EXPECT().CreateRefreshToken(gomock.Any()).DoAndReturn(func(token filmoteka.RefreshToken) (int, error) {
+					assert.Equal(t, 2, token.UserId)
+					assert.Equal(t, "family", token.FamilyId)
+					assert.NotEqual(t, hashToken("old-token"), token.TokenHash)
+					return 3, nil
+				})
+				//The presented token is revoked and points at its replacement
+				tokens.EXPECT().RotateRefreshToken(1, 3).Return(nil)
+			},
+		},
+		{
+			name: "Reused",
+			mockBehavior: func(auth *mock_repository.MockAuthorization, tokens *mock_repository.MockRefreshTokens) {
+				tokens.EXPECT().GetRefreshToken(hashToken("old-token")).Return(filmoteka.RefreshToken{
+					Id: 1, UserId: 2, FamilyId: "family", ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt,
+				}, nil)
+				tokens.EXPECT().RevokeRefreshTokenFamily("family").Return(nil)
+			},
+			wantErrIs: filmoteka.ErrInvalidRefreshToken,
+		},
+		{
+			name: "Expired",
+			mockBehavior: func(auth *mock_repository.MockAuthorization, tokens *mock_repository.MockRefreshTokens) {
+				tokens.EXPECT().GetRefreshToken(hashToken("old-token")).Return(filmoteka.RefreshToken{
+					Id: 1, UserId: 2, FamilyId: "family", ExpiresAt: time.Now().Add(-time.Second),
+				}, nil)
+			},
+			wantErrIs: filmoteka.ErrInvalidRefreshToken,
+		},
+		{
+			name: "Unknown",
+			mockBehavior: func(auth *mock_repository.MockAuthorization, tokens *mock_repository.MockRefreshTokens) {
+				tokens.EXPECT().GetRefreshToken(hashToken("old-token")).Return(filmoteka.RefreshToken{}, sql.ErrNoRows)
+			},
+			wantErrIs: filmoteka.ErrInvalidRefreshToken,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_repository.NewMockAuthorization(c)
+			tokens := mock_repository.NewMockRefreshTokens(c)
+			testCase.mockBehavior(auth, tokens)
+
+			tx := testTransactor{repos: &repository.Repository{Authorization: auth, RefreshTokens: tokens}}
+			service := NewAuthService(auth, tokens, tx, newTestKeyRing(t))
+
+			got, err := service.RefreshToken("old-token")
+
+			//Asserts
+			if testCase.wantErrIs != nil {
+				assert.ErrorIs(t, err, testCase.wantErrIs)
+				assert.Equal(t, filmoteka.Tokens{}, got)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotEmpty(t, got.RefreshToken)
+			assert.NotEqual(t, "old-token", got.RefreshToken)
+
+			identity, err := service.ParseToken(got.AccessToken)
+			assert.NoError(t, err)
+			assert.Equal(t, filmoteka.Identity{UserId: 2, Role: filmoteka.RoleEditor}, identity)
+		})
+	}
+}
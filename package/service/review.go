@@ -0,0 +1,43 @@
+package service
+
+import (
+	filmoteka "vk_restAPI"
+	"vk_restAPI/package/repository"
+)
+
+type ReviewService struct {
+	repo repository.Reviews
+}
+
+func NewReviewService(repo repository.Reviews) *ReviewService {
+	return &ReviewService{repo: repo}
+}
+
+func (r *ReviewService) CreateReview(movieId, userId int, input filmoteka.ReviewInput) (int, error) {
+	if err := input.Validate(); err != nil {
+		return 0, err
+	}
+	return r.repo.CreateReview(movieId, userId, input)
+}
+
+func (r *ReviewService) GetReviews(params filmoteka.ReviewListParams) (filmoteka.ReviewsList, error) {
+	return r.repo.GetReviews(params)
+}
+
+func (r *ReviewService) UpdateReview(reviewId, userId int, input filmoteka.UpdateReview) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+	return r.repo.UpdateReview(reviewId, userId, input)
+}
+
+func (r *ReviewService) DeleteReview(reviewId, userId int) error {
+	return r.repo.DeleteReview(reviewId, userId)
+}
+
+func (r *ReviewService) SetReviewStatus(reviewId int, status filmoteka.ReviewStatus) error {
+	if err := status.Validate(); err != nil {
+		return err
+	}
+	return r.repo.SetReviewStatus(reviewId, status.Status)
+}
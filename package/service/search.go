@@ -0,0 +1,22 @@
+package service
+
+import (
+	filmoteka "vk_restAPI"
+	"vk_restAPI/package/repository"
+)
+
+type SearchService struct {
+	repo repository.Search
+}
+
+func NewSearchService(repo repository.Search) *SearchService {
+	return &SearchService{repo: repo}
+}
+
+func (s *SearchService) FuzzySearch(params filmoteka.FuzzySearchParams) (filmoteka.FuzzySearchResults, error) {
+	return s.repo.FuzzySearch(params)
+}
+
+func (s *SearchService) Autocomplete(params filmoteka.FuzzySearchParams) ([]filmoteka.Suggestion, error) {
+	return s.repo.Autocomplete(params)
+}
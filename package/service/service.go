@@ -9,52 +9,119 @@ import (
 
 type Authorization interface {
 	CreateUser(user filmoteka.User) (int, error)
-	GetUserStatus(id int) (bool, error)
-	GenerateToken(username, password string) (string, error)
-	ParseToken(token string) (int, error)
+	GetUserRole(id int) (filmoteka.UserRole, error)
+	SetUserRole(id int, role string) error
+	BootstrapAdmin(username, password string) (int, error)
+	GenerateToken(username, password string) (filmoteka.Tokens, error)
+	RefreshToken(refreshToken string) (filmoteka.Tokens, error)
+	Logout(refreshToken string) error
+	ParseToken(token string) (filmoteka.Identity, error)
+	JWKS() JSONWebKeySet
 }
 
 type Actors interface {
 	CreateActor(actor filmoteka.Actors) (int, error)
-	DeleteActor(actorId int) error
-	UpdateActor(actorId int, input filmoteka.UpdateActors) error
+	DeleteActor(actorId int, mode string) error
+	UpdateActor(actorId int, input filmoteka.UpdateActors, version int) (int, error)
 }
 
 type Movies interface {
-	CreateMovie(movie filmoteka.Movies, actorIDs []int) (int, error)
+	CreateMovie(movie filmoteka.Movies, cast []filmoteka.CastEntry, crew []filmoteka.CrewEntry, genreIDs []int) (int, error)
 	DeleteMovie(movieId int) error
 }
 
+type Genres interface {
+	CreateGenre(genre filmoteka.Genre) (int, error)
+	GetGenres() ([]filmoteka.Genre, error)
+	GetGenreById(genreId int) (filmoteka.Genre, error)
+	UpdateGenre(genreId int, genre filmoteka.Genre) error
+	DeleteGenre(genreId int) error
+}
+
+type People interface {
+	CreatePerson(person filmoteka.Person) (int, error)
+	GetPeople(params filmoteka.PersonListParams) (filmoteka.PeopleList, error)
+	GetPersonById(personId int) (filmoteka.PersonWithCredits, error)
+	UpdatePerson(personId int, input filmoteka.UpdatePerson, version int) (int, error)
+	DeletePerson(personId int) error
+}
+
+type Reviews interface {
+	CreateReview(movieId, userId int, input filmoteka.ReviewInput) (int, error)
+	GetReviews(params filmoteka.ReviewListParams) (filmoteka.ReviewsList, error)
+	UpdateReview(reviewId, userId int, input filmoteka.UpdateReview) error
+	DeleteReview(reviewId, userId int) error
+	SetReviewStatus(reviewId int, status filmoteka.ReviewStatus) error
+}
+
+type Collections interface {
+	CreateCollection(userId int, input filmoteka.CollectionInput) (int, error)
+	GetCollections(userId int) ([]filmoteka.Collection, error)
+	GetCollection(collectionId, userId int) (filmoteka.Collection, error)
+	GetSharedCollection(slug string) (filmoteka.Collection, error)
+	UpdateCollection(collectionId, userId int, input filmoteka.UpdateCollection) error
+	DeleteCollection(collectionId, userId int) error
+	GetCollectionMovies(collectionId, userId int, params filmoteka.CollectionMoviesParams) (filmoteka.CollectionMoviesList, error)
+	GetSharedCollectionMovies(slug string, params filmoteka.CollectionMoviesParams) (filmoteka.CollectionMoviesList, error)
+	AddCollectionMovie(collectionId, userId int, item filmoteka.CollectionItem) error
+	RemoveCollectionMovie(collectionId, userId, movieId int) error
+	ReorderCollection(collectionId, userId int, order filmoteka.CollectionOrder) error
+}
+
+type Watched interface {
+	MarkWatched(userId, movieId int, input filmoteka.WatchedInput) error
+	UnmarkWatched(userId, movieId int) error
+	GetWatched(userId int, params filmoteka.WatchedListParams) (filmoteka.WatchedList, error)
+	GetWatchedStats(userId int) (filmoteka.WatchedStats, error)
+	GetMovieStates(userId int, movieIds []int) (map[int]filmoteka.MovieState, error)
+}
+
 type ActorsWithMovies interface {
-	GetActors() ([]filmoteka.ActorsWithMovies, error)
+	GetActors(params filmoteka.ActorListParams) (filmoteka.ActorsList, error)
 	GetActorById(actorId int) (filmoteka.ActorsWithMovies, error)
 }
 
 type MoviesWithActors interface {
-	GetMovies() ([]filmoteka.MoviesWithActors, error)
-	GetMoviesSortedByTitle() ([]filmoteka.MoviesWithActors, error)
-	GetMoviesSortedByDate() ([]filmoteka.MoviesWithActors, error)
+	GetMovies(params filmoteka.MovieListParams) (filmoteka.MoviesList, error)
 	GetMovieById(movieId int) (filmoteka.MoviesWithActors, error)
-	UpdateMovie(movieId int, input filmoteka.UpdateMovies) error
+	UpdateMovie(movieId int, input filmoteka.UpdateMovies, version int) (int, error)
+	SearchMovies(params filmoteka.MovieSearchParams) (filmoteka.MovieSearchList, error)
 	SearchMoviesByTitle(fragment string) ([]filmoteka.MoviesWithActors, error)
 	SearchMovieByActorName(fragment string) ([]filmoteka.MoviesWithActors, error)
 }
 
+type Search interface {
+	FuzzySearch(params filmoteka.FuzzySearchParams) (filmoteka.FuzzySearchResults, error)
+	Autocomplete(params filmoteka.FuzzySearchParams) ([]filmoteka.Suggestion, error)
+}
+
 type Service struct {
 	Authorization
 	Actors
 	Movies
 	MoviesWithActors
 	ActorsWithMovies
+	Genres
+	People
+	Reviews
+	Collections
+	Watched
+	Search
 }
 
 // Service access databaseses
-func NewService(repos *repository.Repository) *Service {
+func NewService(repos *repository.Repository, keys *KeyRing) *Service {
 	return &Service{
-		Authorization:    NewAuthService(repos.Authorization),
+		Authorization:    NewAuthService(repos.Authorization, repos.RefreshTokens, repos.Transactor, keys),
 		Actors:           NewActorService(repos.Actors),
-		Movies:           NewMovieService(repos.Movies),
-		MoviesWithActors: NewMoviesWithActorsService(repos.MoviesWithActors),
+		Movies:           NewMovieService(repos.Movies, repos.Transactor),
+		MoviesWithActors: NewMoviesWithActorsService(repos.MoviesWithActors, repos.Transactor),
 		ActorsWithMovies: NewActorsWithMoviesService(repos.ActorsWithMovies),
+		Genres:           NewGenreService(repos.Genres),
+		People:           NewPeopleService(repos.People),
+		Reviews:          NewReviewService(repos.Reviews),
+		Collections:      NewCollectionService(repos.Collections),
+		Watched:          NewWatchedService(repos.Watched),
+		Search:           NewSearchService(repos.Search),
 	}
 }
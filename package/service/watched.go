@@ -0,0 +1,49 @@
+package service
+
+import (
+	"time"
+	filmoteka "vk_restAPI"
+	"vk_restAPI/package/repository"
+)
+
+type WatchedService struct {
+	repo repository.Watched
+}
+
+func NewWatchedService(repo repository.Watched) *WatchedService {
+	return &WatchedService{repo: repo}
+}
+
+func (w *WatchedService) MarkWatched(userId, movieId int, input filmoteka.WatchedInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	if input.WatchedOn == "" {
+		input.WatchedOn = time.Now().Format(filmoteka.DateLayout)
+	}
+
+	return w.repo.MarkWatched(userId, movieId, input)
+}
+
+func (w *WatchedService) UnmarkWatched(userId, movieId int) error {
+	return w.repo.UnmarkWatched(userId, movieId)
+}
+
+func (w *WatchedService) GetWatched(userId int, params filmoteka.WatchedListParams) (filmoteka.WatchedList, error) {
+	if err := params.Validate(); err != nil {
+		return filmoteka.WatchedList{}, err
+	}
+	return w.repo.GetWatched(userId, params)
+}
+
+func (w *WatchedService) GetWatchedStats(userId int) (filmoteka.WatchedStats, error) {
+	return w.repo.GetWatchedStats(userId)
+}
+
+func (w *WatchedService) GetMovieStates(userId int, movieIds []int) (map[int]filmoteka.MovieState, error) {
+	if len(movieIds) == 0 {
+		return map[int]filmoteka.MovieState{}, nil
+	}
+	return w.repo.GetMovieStates(userId, movieIds)
+}
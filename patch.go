@@ -0,0 +1,50 @@
+package filmoteka
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+)
+
+// ApplyMergePatch sets the fields of the update struct dst points to from a
+// JSON merge patch (RFC 7396) decoded into its top level members. Members
+// that are absent leave their field nil. A null member clears the field,
+// which sets it to its zero value when the field is tagged patch:"nullable"
+// and is reported as a FieldError otherwise, like a value of the wrong type.
+// Unknown members are ignored.
+func ApplyMergePatch(patch map[string]json.RawMessage, dst interface{}) error {
+	value := reflect.ValueOf(dst).Elem()
+	fields := value.Type()
+
+	var errs ValidationErrors
+	for i := 0; i < fields.NumField(); i++ {
+		name := jsonName(fields.Field(i))
+		raw, ok := patch[name]
+		if !ok {
+			continue
+		}
+
+		field := value.Field(i)
+		if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+			if fields.Field(i).Tag.Get("patch") != "nullable" {
+				errs = append(errs, FieldError{Field: name, Message: "can't be cleared"})
+				continue
+			}
+			zero := reflect.New(field.Type().Elem())
+			if zero.Elem().Kind() == reflect.Slice {
+				zero.Elem().Set(reflect.MakeSlice(zero.Elem().Type(), 0, 0))
+			}
+			field.Set(zero)
+			continue
+		}
+
+		if err := json.Unmarshal(raw, field.Addr().Interface()); err != nil {
+			errs = append(errs, FieldError{Field: name, Message: "has the wrong type"})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
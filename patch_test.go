@@ -0,0 +1,75 @@
+package filmoteka
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyMergePatch(t *testing.T) {
+	testTable := []struct {
+		name     string
+		patch    string
+		expected UpdateMovies
+		errors   ValidationErrors
+	}{
+		{
+			name:     "Absent Members Stay Nil",
+			patch:    `{"title":"Dune","rating":8}`,
+			expected: UpdateMovies{Title: strPtr("Dune"), Rating: intPtr(8)},
+		},
+		{
+			name:     "Null Clears Nullable Fields",
+			patch:    `{"description":null,"actors":null}`,
+			expected: UpdateMovies{Description: strPtr(""), Actors: &[]CastEntry{}},
+		},
+		{
+			name:  "Null On Required Field",
+			patch: `{"title":null,"release_date":null,"rating":7}`,
+			errors: ValidationErrors{
+				{Field: "title", Message: "can't be cleared"},
+				{Field: "release_date", Message: "can't be cleared"},
+			},
+		},
+		{
+			name:  "Wrong Type",
+			patch: `{"rating":"high"}`,
+			errors: ValidationErrors{
+				{Field: "rating", Message: "has the wrong type"},
+			},
+		},
+		{
+			name:     "Unknown Members Ignored",
+			patch:    `{"director":"Villeneuve","actors":[1,2]}`,
+			expected: UpdateMovies{Actors: &[]CastEntry{{ActorId: 1}, {ActorId: 2}}},
+		},
+		{
+			name:  "Cast Entries With Roles",
+			patch: `{"actors":[{"actor_id":1,"character_name":"Пол Атрейдес","role_type":"lead"},2]}`,
+			expected: UpdateMovies{Actors: &[]CastEntry{
+				{ActorId: 1, CharacterName: "Пол Атрейдес", RoleType: CastLead},
+				{ActorId: 2},
+			}},
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			var patch map[string]json.RawMessage
+			if err := json.Unmarshal([]byte(testCase.patch), &patch); err != nil {
+				t.Fatal(err)
+			}
+
+			var input UpdateMovies
+			err := ApplyMergePatch(patch, &input)
+
+			if testCase.errors != nil {
+				assert.ErrorIs(t, err, ErrValidation)
+				assert.Equal(t, testCase.errors, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, input)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package filmoteka
+
+import "time"
+
+const (
+	ReviewPublished = "published"
+	ReviewHidden    = "hidden"
+)
+
+// Review is a user's rating of a movie from 1 to 10, with an optional text.
+// A user has at most one review per movie. Hidden reviews were taken down
+// by a moderator and are only shown to their author and to moderators.
+type Review struct {
+	Id         int       `json:"id" db:"id"`
+	MovieId    int       `json:"movie_id" db:"movie_id"`
+	MovieTitle string    `json:"movie_title" db:"movie_title"`
+	UserId     int       `json:"user_id" db:"user_id"`
+	Username   string    `json:"username" db:"username"`
+	Rating     int       `json:"rating" db:"rating"`
+	Body       string    `json:"body" db:"body"`
+	Status     string    `json:"status" db:"status"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ReviewInput is what a user sends to review a movie.
+type ReviewInput struct {
+	Rating int    `json:"rating" validate:"min=1,max=10"`
+	Body   string `json:"body" validate:"max=5000"`
+}
+
+// UpdateReview is a partial update of one's own review, see UpdateMovies.
+type UpdateReview struct {
+	Rating *int    `json:"rating" validate:"required,min=1,max=10"`
+	Body   *string `json:"body" validate:"max=5000" patch:"nullable"`
+}
+
+// ReviewStatus is a moderator's decision on a review.
+type ReviewStatus struct {
+	Status string `json:"status" validate:"required,oneof=published hidden"`
+}
+
+// ReviewListParams narrows a list of reviews down. MovieId and UserId are 0
+// when not set, Status is blank for reviews in any status. Reviews are
+// ordered newest first, so pages are addressed by offset only.
+type ReviewListParams struct {
+	MovieId int    `json:"movie_id"`
+	UserId  int    `json:"user_id"`
+	Status  string `json:"status" validate:"oneof=published hidden"`
+	Limit   int    `json:"limit"`
+	Offset  int    `json:"offset"`
+}
+
+type ReviewsList struct {
+	Reviews []Review
+	Total   int
+}
+
+// UserRating aggregates the published reviews of a movie. Average is
+// rounded to one decimal place and 0 without votes. Distribution has a
+// bucket for every score from 1 to 10, empty ones included.
+type UserRating struct {
+	Average      float64        `json:"average"`
+	Votes        int            `json:"votes"`
+	Distribution []RatingBucket `json:"distribution"`
+}
+
+type RatingBucket struct {
+	Score int `json:"score"`
+	Votes int `json:"votes"`
+}
+
+// Scan reads a UserRating built with json_build_object.
+func (u *UserRating) Scan(src interface{}) error {
+	*u = UserRating{}
+	return scanJSON(src, u)
+}
+
+func (r ReviewInput) Validate() error {
+	return Validate(r)
+}
+
+func (u UpdateReview) Validate() error {
+	if u.Rating == nil && u.Body == nil {
+		return Errorf(ErrValidation, "update structure has no values")
+	}
+	return Validate(u)
+}
+
+func (s ReviewStatus) Validate() error {
+	return Validate(s)
+}
+
+func (p ReviewListParams) Validate() error {
+	if err := Validate(p); err != nil {
+		return err
+	}
+	return validatePage(p.Limit, p.Offset)
+}
@@ -0,0 +1,66 @@
+package filmoteka
+
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+const (
+	PermMoviesRead   = "movies:read"
+	PermMoviesWrite  = "movies:write"
+	PermMoviesDelete = "movies:delete"
+	PermActorsRead   = "actors:read"
+	PermActorsWrite  = "actors:write"
+	PermActorsDelete = "actors:delete"
+	PermUsersManage  = "users:manage"
+
+	PermReviewsWrite    = "reviews:write"
+	PermReviewsModerate = "reviews:moderate"
+
+	PermCollectionsWrite = "collections:write"
+	PermHistoryWrite     = "history:write"
+)
+
+// Roles lists the roles from least to most privileged.
+var Roles = []string{RoleViewer, RoleEditor, RoleAdmin}
+
+// rolePermissions is the single source of truth for what a role may do.
+// Editors can create and fix catalog entries but not delete them. Everyone
+// may review movies and keep collections and a watched history, only
+// admins moderate reviews.
+var rolePermissions = map[string][]string{
+	RoleViewer: {PermMoviesRead, PermActorsRead, PermReviewsWrite, PermCollectionsWrite, PermHistoryWrite},
+	RoleEditor: {PermMoviesRead, PermActorsRead, PermReviewsWrite, PermCollectionsWrite, PermHistoryWrite, PermMoviesWrite, PermActorsWrite},
+	RoleAdmin: {PermMoviesRead, PermActorsRead, PermReviewsWrite, PermCollectionsWrite, PermHistoryWrite, PermMoviesWrite, PermActorsWrite,
+		PermMoviesDelete, PermActorsDelete, PermUsersManage, PermReviewsModerate},
+}
+
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// Permissions returns what role is allowed to do; unknown roles get nothing.
+func Permissions(role string) []string {
+	return rolePermissions[role]
+}
+
+func HasPermission(role, permission string) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Identity is who an access token was issued to.
+type Identity struct {
+	UserId int
+	Role   string
+}
+
+func (i Identity) Can(permission string) bool {
+	return HasPermission(i.Role, permission)
+}
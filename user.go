@@ -1,8 +1,35 @@
 package filmoteka
 
+import "time"
+
 type User struct {
 	Id       int    `json:"-" db:"id"`
-	Username string `json:"username"`
-	Password string `json:"password"`
-	Is_admin bool   `json:"is_admin"`
+	Username string `json:"username" db:"username"`
+	Password string `json:"password" db:"password_hash"`
+	Role     string `json:"role" db:"role"`
+}
+
+// UserRole is the role of an account as exposed by the role endpoints.
+type UserRole struct {
+	UserId int    `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+// RefreshToken is the server-side record of an issued refresh token. Only
+// the hash of the token is stored. Tokens rotated from the same login share
+// a FamilyId, so reuse of a rotated token can revoke the whole chain.
+type RefreshToken struct {
+	Id        int        `db:"id"`
+	UserId    int        `db:"user_id"`
+	FamilyId  string     `db:"family_id"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+}
+
+// Tokens is the pair handed out on log in and refresh.
+type Tokens struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
 }
@@ -0,0 +1,187 @@
+package filmoteka
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DateLayout is the ISO 8601 calendar date format used for every date in
+// the API.
+const DateLayout = "2006-01-02"
+
+// FieldError is a single invalid field, named as in JSON.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors lists every invalid field of a value at once, so a
+// client can fix them all before retrying. It is an ErrValidation.
+type ValidationErrors []FieldError
+
+func (v ValidationErrors) Error() string {
+	messages := make([]string, 0, len(v))
+	for _, field := range v {
+		messages = append(messages, field.Field+": "+field.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (v ValidationErrors) Unwrap() error {
+	return ErrValidation
+}
+
+// Validate checks the fields of the struct v points to (or v itself)
+// against their `validate` tags and returns nil or ValidationErrors.
+// Rules are comma separated:
+//
+//	required   the value must not be blank
+//	min=N      strings: at least N characters; numbers: at least N
+//	max=N      strings: at most N characters; numbers: at most N
+//	date       a YYYY-MM-DD date
+//	notfuture  a date that is not after today
+//	oneof=a b  one of the space separated values
+//	country    an ISO 3166-1 alpha-2 code in upper case, like RU
+//	dive       on its own: check every struct of a slice, reporting
+//	           their fields as name[i].field
+//
+// Nil pointer fields are not set and are skipped, which is what partial
+// updates need. Blank optional strings skip every rule but required.
+func Validate(v interface{}) error {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	fields := value.Type()
+
+	var errs ValidationErrors
+	for i := 0; i < fields.NumField(); i++ {
+		tag := fields.Field(i).Tag.Get("validate")
+		if tag == "" {
+			continue
+		}
+
+		field := value.Field(i)
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				continue
+			}
+			field = field.Elem()
+		}
+
+		if tag == "dive" {
+			errs = append(errs, validateEach(jsonName(fields.Field(i)), field)...)
+			continue
+		}
+
+		if message := checkRules(field, strings.Split(tag, ",")); message != "" {
+			errs = append(errs, FieldError{Field: jsonName(fields.Field(i)), Message: message})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ValidateEntries checks every entry of a list sent as the field named
+// field, such as the cast or the crew of a movie.
+func ValidateEntries[T any](field string, entries []T) error {
+	if errs := validateEach(field, reflect.ValueOf(entries)); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateEach validates the structs of slice, naming their fields after
+// the field the slice was sent as.
+func validateEach(name string, slice reflect.Value) ValidationErrors {
+	var errs ValidationErrors
+	for i := 0; i < slice.Len(); i++ {
+		var elemErrs ValidationErrors
+		if errors.As(Validate(slice.Index(i).Interface()), &elemErrs) {
+			for _, fieldErr := range elemErrs {
+				fieldErr.Field = fmt.Sprintf("%s[%d].%s", name, i, fieldErr.Field)
+				errs = append(errs, fieldErr)
+			}
+		}
+	}
+	return errs
+}
+
+// checkRules returns the message of the first rule field breaks.
+func checkRules(field reflect.Value, rules []string) string {
+	if field.Kind() == reflect.String && strings.TrimSpace(field.String()) == "" {
+		for _, rule := range rules {
+			if rule == "required" {
+				return "is required"
+			}
+		}
+		return ""
+	}
+
+	for _, rule := range rules {
+		name, arg, _ := strings.Cut(rule, "=")
+		if message := checkRule(field, name, arg); message != "" {
+			return message
+		}
+	}
+	return ""
+}
+
+func checkRule(field reflect.Value, name, arg string) string {
+	switch name {
+	case "min", "max":
+		limit, _ := strconv.Atoi(arg)
+
+		var n int
+		var unit string
+		switch field.Kind() {
+		case reflect.String:
+			n, unit = utf8.RuneCountInString(field.String()), " characters"
+		case reflect.Int:
+			n = int(field.Int())
+		}
+
+		if name == "min" && n < limit {
+			return fmt.Sprintf("must be at least %d%s", limit, unit)
+		}
+		if name == "max" && n > limit {
+			return fmt.Sprintf("must be at most %d%s", limit, unit)
+		}
+	case "date":
+		if _, err := time.Parse(DateLayout, field.String()); err != nil {
+			return "must be a date in YYYY-MM-DD format"
+		}
+	case "notfuture":
+		date, err := time.Parse(DateLayout, field.String())
+		if err == nil && date.After(time.Now().UTC()) {
+			return "must not be in the future"
+		}
+	case "oneof":
+		options := strings.Fields(arg)
+		for _, option := range options {
+			if field.String() == option {
+				return ""
+			}
+		}
+		return "must be one of: " + strings.Join(options, ", ")
+	case "country":
+		code := field.String()
+		if len(code) != 2 || code[0] < 'A' || code[0] > 'Z' || code[1] < 'A' || code[1] > 'Z' {
+			return "must be a two letter country code in upper case"
+		}
+	}
+	return ""
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
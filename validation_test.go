@@ -0,0 +1,143 @@
+package filmoteka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func strPtr(s string) *string { return &s }
+func intPtr(n int) *int       { return &n }
+
+func TestValidate(t *testing.T) {
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format(DateLayout)
+
+	testTable := []struct {
+		name     string
+		input    interface{ Validate() error }
+		expected ValidationErrors
+	}{
+		{
+			name:  "Valid Movie",
+			input: Movies{Title: "Dune", Description: "Desert", ReleaseDate: "2021-09-15", Rating: 8},
+		},
+		{
+			name:  "Every Movie Field Reported",
+			input: Movies{Title: "", Description: string(make([]rune, 1001)), ReleaseDate: "15.09.2021", Rating: 11},
+			expected: ValidationErrors{
+				{Field: "title", Message: "is required"},
+				{Field: "description", Message: "must be at most 1000 characters"},
+				{Field: "release_date", Message: "must be a date in YYYY-MM-DD format"},
+				{Field: "rating", Message: "must be at most 10"},
+			},
+		},
+		{
+			name:  "Title Length Counts Characters",
+			input: Movies{Title: "Дюна: Часть вторая", ReleaseDate: "2024-02-29", Rating: 9},
+		},
+		{
+			name:  "Valid Movie Metadata",
+			input: Movies{Title: "Дюна", OriginalTitle: "Dune", ReleaseDate: "2021-09-15", Country: "US", Runtime: 155, AgeRating: "12+"},
+		},
+		{
+			name:  "Invalid Movie Metadata",
+			input: Movies{Title: "Дюна", ReleaseDate: "2021-09-15", Country: "usa", Runtime: -1, AgeRating: "PG-13"},
+			expected: ValidationErrors{
+				{Field: "country", Message: "must be a two letter country code in upper case"},
+				{Field: "runtime", Message: "must be at least 0"},
+				{Field: "age_rating", Message: "must be one of: 0+, 6+, 12+, 16+, 18+"},
+			},
+		},
+		{
+			name:  "Valid Actor",
+			input: Actors{FirstName: "Zendaya", Gender: "female", DateOfBirth: "1996-09-01"},
+		},
+		{
+			name:  "Invalid Actor",
+			input: Actors{FirstName: "Zendaya", Gender: "Женщина", DateOfBirth: tomorrow},
+			expected: ValidationErrors{
+				{Field: "gender", Message: "must be one of: male, female, other"},
+				{Field: "date_of_birth", Message: "must not be in the future"},
+			},
+		},
+		{
+			name:  "Partial Update Checks Only Set Fields",
+			input: UpdateMovies{Rating: intPtr(-1)},
+			expected: ValidationErrors{
+				{Field: "rating", Message: "must be at least 0"},
+			},
+		},
+		{
+			name: "Every Cast Entry Checked",
+			input: UpdateMovies{Actors: &[]CastEntry{
+				{ActorId: 1, RoleType: CastLead},
+				{ActorId: 0, BillingOrder: -1, RoleType: "extra"},
+			}},
+			expected: ValidationErrors{
+				{Field: "actors[1].actor_id", Message: "must be at least 1"},
+				{Field: "actors[1].billing_order", Message: "must be at least 0"},
+				{Field: "actors[1].role_type", Message: "must be one of: lead, supporting, cameo, voice"},
+			},
+		},
+		{
+			name: "Every Crew Entry Checked",
+			input: UpdateMovies{Crew: &[]CrewEntry{
+				{PersonId: 18, Department: DepartmentDirecting, Job: "Режиссёр"},
+				{PersonId: 18, Department: DepartmentActing},
+			}},
+			expected: ValidationErrors{
+				{Field: "crew[1].department", Message: "must be one of: directing, writing, production, sound, camera, editing"},
+				{Field: "crew[1].job", Message: "is required"},
+			},
+		},
+		{
+			name:  "Person Without Department",
+			input: Person{FirstName: "Трент", LastName: "Резнор", Gender: "male", DateOfBirth: "1965-05-17"},
+			expected: ValidationErrors{
+				{Field: "known_for", Message: "is required"},
+			},
+		},
+		{
+			name:  "Review Out Of Scale",
+			input: ReviewInput{Rating: 11, Body: "Шедевр"},
+			expected: ValidationErrors{
+				{Field: "rating", Message: "must be at most 10"},
+			},
+		},
+		{
+			name:  "Collection Of Unknown Kind",
+			input: CollectionInput{Name: "Позже", Kind: "later"},
+			expected: ValidationErrors{
+				{Field: "kind", Message: "must be one of: watchlist, favorites, custom"},
+			},
+		},
+		{
+			name:  "Watched In The Future",
+			input: WatchedInput{WatchedOn: "2999-01-01", PersonalRating: 11},
+			expected: ValidationErrors{
+				{Field: "watched_on", Message: "must not be in the future"},
+				{Field: "personal_rating", Message: "must be at most 10"},
+			},
+		},
+		{
+			name:  "Blank Required Field In Update",
+			input: UpdateActors{FirstName: strPtr(" "), LastName: strPtr("")},
+			expected: ValidationErrors{
+				{Field: "first_name", Message: "is required"},
+			},
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.input.Validate()
+
+			if testCase.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrValidation)
+			assert.Equal(t, testCase.expected, err)
+		})
+	}
+}
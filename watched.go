@@ -0,0 +1,89 @@
+package filmoteka
+
+// WatchedInput marks a movie as watched. A blank WatchedOn is today, a
+// PersonalRating of 0 is no rating.
+type WatchedInput struct {
+	WatchedOn      string `json:"watched_on" validate:"date,notfuture"`
+	PersonalRating int    `json:"personal_rating" validate:"min=0,max=10"`
+}
+
+// WatchedMovie is a movie of a user's history.
+type WatchedMovie struct {
+	MoviesWithActors
+	WatchedOn      string `json:"watched_on" db:"watched_on"`
+	PersonalRating int    `json:"personal_rating" db:"personal_rating"`
+}
+
+// WatchedListParams narrows a user's history down. The dates are inclusive,
+// Title and GenreIds filter like in MovieListParams. The history is ordered
+// by the date watched, latest first, so pages are addressed by offset only.
+type WatchedListParams struct {
+	WatchedAfter  string `json:"watched_after" validate:"date"`
+	WatchedBefore string `json:"watched_before" validate:"date"`
+	MinRating     int    `json:"min_rating" validate:"min=0,max=10"`
+	Title         string `json:"q"`
+	GenreIds      []int  `json:"genre"`
+	Limit         int    `json:"limit"`
+	Offset        int    `json:"offset"`
+}
+
+type WatchedList struct {
+	Movies []WatchedMovie
+	Total  int
+}
+
+// WatchedStats sums a user's history up. AverageRating is over the movies
+// given a personal rating and 0 without any. FavoriteActors are the actors
+// of the most watched movies.
+type WatchedStats struct {
+	Total          int            `json:"total" db:"total"`
+	AverageRating  float64        `json:"average_rating" db:"average_rating"`
+	PerYear        WatchedYears   `json:"per_year" db:"per_year"`
+	FavoriteActors FavoriteActors `json:"favorite_actors" db:"favorite_actors"`
+}
+
+// WatchedYears is scanned from a JSON array built with json_agg, latest
+// year first.
+type WatchedYears []WatchedYear
+
+type WatchedYear struct {
+	Year   int `json:"year"`
+	Movies int `json:"movies"`
+}
+
+// FavoriteActors is scanned from a JSON array built with json_agg, the most
+// watched actor first.
+type FavoriteActors []FavoriteActor
+
+type FavoriteActor struct {
+	ActorSummary
+	Movies int `json:"movies"`
+}
+
+// MovieState is what a user has done with a movie, see
+// MoviesWithActors.State.
+type MovieState struct {
+	Watched     bool `json:"watched" db:"watched"`
+	InWatchlist bool `json:"in_watchlist" db:"in_watchlist"`
+}
+
+func (y *WatchedYears) Scan(src interface{}) error {
+	*y = WatchedYears{}
+	return scanJSON(src, y)
+}
+
+func (a *FavoriteActors) Scan(src interface{}) error {
+	*a = FavoriteActors{}
+	return scanJSON(src, a)
+}
+
+func (w WatchedInput) Validate() error {
+	return Validate(w)
+}
+
+func (p WatchedListParams) Validate() error {
+	if err := Validate(p); err != nil {
+		return err
+	}
+	return validatePage(p.Limit, p.Offset)
+}